@@ -6,6 +6,7 @@ import type {
   CertificateListResponse,
   InitOrgCARequest,
   IssueMemberCertRequest,
+  IssueMemberCertFromCSRRequest,
   RevokeCertRequest,
   MemberCertResponse,
   OrgCAResponse,
@@ -20,6 +21,7 @@ export type {
   CertificateListResponse,
   InitOrgCARequest,
   IssueMemberCertRequest,
+  IssueMemberCertFromCSRRequest,
   RevokeCertRequest,
   MemberCertResponse,
   OrgCAResponse,
@@ -98,6 +100,26 @@ const useIssueMemberCert = ({
   });
 };
 
+const useIssueMemberCertFromCSR = ({
+  onSuccess,
+  onError,
+}: MutationOptions<MemberCertResponse, IssueMemberCertFromCSRRequest> = {}) => {
+  const { invalidateCertificates } = useInvalidateQueries();
+
+  return useMutation({
+    mutationFn: (data: IssueMemberCertFromCSRRequest) =>
+      sdk.certificates.issueMemberCertFromCsr(data),
+    onSuccess: (data, variables) => {
+      onSuccess?.({ data, variables });
+      invalidateCertificates();
+    },
+    onError: (error, variables) => {
+      console.error("Failed to issue member certificate from CSR:", error);
+      onError?.({ error, variables });
+    },
+  });
+};
+
 const useRevokeCert = ({
   onSuccess,
   onError,
@@ -140,6 +162,7 @@ export const certificates = {
   getRootCA: useRootCA,
   initOrgCA: useInitOrgCA,
   issueMemberCert: useIssueMemberCert,
+  issueMemberCertFromCSR: useIssueMemberCertFromCSR,
   revokeCert: useRevokeCert,
   getCRL: useCRL,
   checkOCSP: useCheckOCSP,
@@ -2,6 +2,7 @@ import { type Context } from "hono";
 
 import { CertificateService } from "@/services/certificate.service";
 import { AuditLogService } from "@/services/audit_log.service";
+import { config } from "@/utils/env";
 
 export class CertificateController {
 	public static readonly initOrgCA = async (c: Context) => {
@@ -71,6 +72,42 @@ export class CertificateController {
 		return c.json(cert, 201);
 	};
 
+	public static readonly issueMemberCertFromCSR = async (c: Context) => {
+		if (config.MINIKMS_CSR_ISSUANCE_ENABLED !== "true") {
+			return c.json({ error: "Issuing certificates from a CSR is not supported by this server's KMS." }, 501);
+		}
+
+		const org_id = c.get("org_id");
+		const user_id = c.get("user_id");
+		const { csr_pem, member_email, role, description, metadata } = await c.req.json();
+
+		const cert = await CertificateService.issueMemberCertFromCSR(
+			org_id,
+			user_id,
+			csr_pem,
+			member_email,
+			role,
+			description,
+			metadata,
+		);
+
+		await AuditLogService.notifyAuditSystem({
+			action: "cert_member_issued",
+			org_id,
+			user_id,
+			message: `Member certificate issued from CSR for: ${member_email}`,
+			details: {
+				certificate_id: cert.id,
+				serial_hex: cert.serial_hex,
+				member_email,
+				role,
+				from_csr: true,
+			},
+		});
+
+		return c.json(cert, 201);
+	};
+
 	public static readonly listCertificates = async (c: Context) => {
 		const org_id = c.get("org_id");
 		const user_id = c.get("user_id");
@@ -519,6 +519,38 @@ export class KMSClient {
 		}
 	}
 
+	/**
+	 * Issue a member certificate for the public key in a PKCS#10 CSR via miniKMS PKI.
+	 * The returned keyPem is empty because the private key never leaves the caller.
+	 */
+	public async issueMemberCertFromCSR(
+		memberId: string,
+		memberEmail: string,
+		orgId: string,
+		role: string,
+		csrPem: string,
+	): Promise<IssueMemberCertResult> {
+		try {
+			const response = await this.rpcCall<GrpcIssueMemberCertResponse>(this.pkiStub, "IssueMemberCertFromCSR", {
+				member_id: memberId,
+				member_email: memberEmail,
+				org_id: orgId,
+				role,
+				csr_pem: csrPem,
+			});
+			return {
+				certPem: response.cert_pem,
+				keyPem: response.key_pem,
+				serialHex: response.serial_hex,
+			};
+		} catch (error) {
+			if (error instanceof Error) {
+				infoLogs(`PKI IssueMemberCertFromCSR error: ${error.message}`, LogTypes.ERROR, "KMSClient");
+			}
+			throw error;
+		}
+	}
+
 	/**
 	 * Revoke a certificate via miniKMS PKI.
 	 */
@@ -7,6 +7,7 @@ import { requirePermission } from "@/middlewares/permission.middleware";
 import {
 	initOrgCARequestSchema,
 	issueMemberCertRequestSchema,
+	issueMemberCertFromCSRRequestSchema,
 	revokeCertRequestSchema,
 	orgCAResponseSchema,
 	memberCertResponseSchema,
@@ -120,6 +121,35 @@ app.post(
 	CertificateController.issueMemberCert,
 );
 
+// Issue member certificate from a CSR
+app.post(
+	"/issue/csr",
+	requirePermission("can_manage_certificates", "org"),
+	describeRoute({
+		operationId: "issueMemberCertFromCSR",
+		summary: "Issue Member Certificate From CSR",
+		description:
+			"Issue a member certificate for the public key of a PKCS#10 certificate signing request. The private key never leaves the caller, so key_pem is empty. Requires a miniKMS that implements IssueMemberCertFromCSR, enabled with MINIKMS_CSR_ISSUANCE_ENABLED.",
+		tags: ["Certificates"],
+		responses: {
+			201: {
+				description: "Member certificate issued successfully",
+				content: { "application/json": { schema: resolver(memberCertResponseSchema) } },
+			},
+			501: {
+				description: "CSR issuance is not enabled on this server",
+				content: { "application/json": { schema: resolver(errorResponseSchema) } },
+			},
+			500: {
+				description: "Internal server error",
+				content: { "application/json": { schema: resolver(errorResponseSchema) } },
+			},
+		},
+	}),
+	zValidator("json", issueMemberCertFromCSRRequestSchema),
+	CertificateController.issueMemberCertFromCSR,
+);
+
 // Get CRL
 app.get(
 	"/crl",
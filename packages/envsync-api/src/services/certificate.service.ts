@@ -121,6 +121,7 @@ export class CertificateService {
 		role: string,
 		description?: string,
 		metadata?: Record<string, string>,
+		csr_pem?: string,
 	) => {
 		const db = await DB.getInstance();
 		const orgCA = await db
@@ -146,7 +147,9 @@ export class CertificateService {
 				name: "kms-issue-cert",
 				execute: async () => {
 					const kms = await KMSClient.getInstance();
-					const result = await kms.issueMemberCert(user_id, member_email, org_id, role);
+					const result = csr_pem
+						? await kms.issueMemberCertFromCSR(user_id, member_email, org_id, role, csr_pem)
+						: await kms.issueMemberCert(user_id, member_email, org_id, role);
 					memberCertPem = result.certPem;
 					memberKeyPem = result.keyPem;
 					memberSerialHex = result.serialHex;
@@ -209,6 +212,31 @@ export class CertificateService {
 		};
 	};
 
+	/**
+	 * Issue a member certificate for the public key of a PKCS#10 CSR. The
+	 * private key stays with the caller, so key_pem is always empty.
+	 */
+	public static issueMemberCertFromCSR = async (
+		org_id: string,
+		user_id: string,
+		csr_pem: string,
+		member_email: string,
+		role: string,
+		description?: string,
+		metadata?: Record<string, string>,
+	) => {
+		const cert = await CertificateService.issueMemberCert(
+			org_id,
+			user_id,
+			member_email,
+			role,
+			description,
+			metadata,
+			csr_pem,
+		);
+		return { ...cert, key_pem: "" };
+	};
+
 	public static listCertificates = async (org_id: string, page = 1, per_page = 50) => {
 		const db = await DB.getInstance();
 		return db
@@ -56,6 +56,8 @@ export const env = z.object({
 	MINIKMS_GRPC_ADDR: z.string().default("localhost:50051"),
 	MINIKMS_TLS_ENABLED: z.string().default("false"),
 	MINIKMS_TLS_CA_CERT: z.string().optional(),
+	// Set once the deployed miniKMS implements PKIService.IssueMemberCertFromCSR
+	MINIKMS_CSR_ISSUANCE_ENABLED: z.string().default("false"),
 	// OpenTelemetry configuration
 	OTEL_EXPORTER_OTLP_ENDPOINT: z.string().default("http://localhost:4318"),
 	OTEL_SERVICE_NAME: z.string().default("envsync-api"),
@@ -19,6 +19,21 @@ export const issueMemberCertRequestSchema = z
 	})
 	.openapi({ ref: "IssueMemberCertRequest" });
 
+export const issueMemberCertFromCSRRequestSchema = z
+	.object({
+		csr_pem: z
+			.string()
+			.regex(/-----BEGIN CERTIFICATE REQUEST-----[\s\S]+-----END CERTIFICATE REQUEST-----/, {
+				message: "csr_pem must be a PEM encoded PKCS#10 certificate request",
+			})
+			.openapi({ example: "-----BEGIN CERTIFICATE REQUEST-----..." }),
+		member_email: z.string().email().openapi({ example: "user@example.com" }),
+		role: z.string().min(1).openapi({ example: "developer" }),
+		description: z.string().optional().openapi({ example: "Developer certificate" }),
+		metadata: z.record(z.string(), z.string()).optional().openapi({ example: { service: "api-gateway", env: "prod" } }),
+	})
+	.openapi({ ref: "IssueMemberCertFromCSRRequest" });
+
 export const revokeCertRequestSchema = z
 	.object({
 		reason: z.number().int().min(0).max(10).openapi({ example: 0 }),
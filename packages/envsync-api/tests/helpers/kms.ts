@@ -212,6 +212,17 @@ export const MockKMSClient = {
 		return { certPem, keyPem, serialHex };
 	},
 
+	async issueMemberCertFromCSR(
+		memberId: string,
+		memberEmail: string,
+		orgId: string,
+		role: string,
+		_csrPem: string,
+	): Promise<{ certPem: string; keyPem: string; serialHex: string }> {
+		const { certPem, serialHex } = await this.issueMemberCert(memberId, memberEmail, orgId, role);
+		return { certPem, keyPem: "", serialHex };
+	},
+
 	async revokeCert(
 		serialHex: string,
 		orgId: string,
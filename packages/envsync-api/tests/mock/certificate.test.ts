@@ -4,6 +4,7 @@ import { testRequest } from "../helpers/request";
 import { seedOrg, seedUser, type SeedOrgResult } from "../helpers/db";
 import { resetFGA, setupUserOrgTuples, MockFGAClient } from "../helpers/fga";
 import { resetPKI } from "../helpers/kms";
+import { config } from "@/utils/env";
 
 let seed: SeedOrgResult;
 let viewerToken: string;
@@ -165,6 +166,76 @@ describe("POST /api/certificate/issue", () => {
 	});
 });
 
+describe("POST /api/certificate/issue/csr", () => {
+	const csrPem = "-----BEGIN CERTIFICATE REQUEST-----\nMIIBCSR\n-----END CERTIFICATE REQUEST-----";
+
+	test("master issues member cert from CSR without a key (201)", async () => {
+		const res = await testRequest("/api/certificate/issue/csr", {
+			method: "POST",
+			token: seed.masterUser.token,
+			body: {
+				csr_pem: csrPem,
+				member_email: "csr@example.com",
+				role: "developer",
+			},
+		});
+		expect(res.status).toBe(201);
+
+		const body = await res.json<{
+			cert_type: string;
+			cert_pem: string;
+			key_pem: string;
+		}>();
+		expect(body.cert_type).toBe("member");
+		expect(body.cert_pem).toContain("BEGIN CERTIFICATE");
+		expect(body.key_pem).toBe("");
+	});
+
+	test("rejects a body without a CSR (400)", async () => {
+		const res = await testRequest("/api/certificate/issue/csr", {
+			method: "POST",
+			token: seed.masterUser.token,
+			body: {
+				csr_pem: "not a csr",
+				member_email: "csr@example.com",
+				role: "developer",
+			},
+		});
+		expect(res.status).toBe(400);
+	});
+
+	test("viewer denied issue (403)", async () => {
+		const res = await testRequest("/api/certificate/issue/csr", {
+			method: "POST",
+			token: viewerToken,
+			body: {
+				csr_pem: csrPem,
+				member_email: "fail@example.com",
+				role: "viewer",
+			},
+		});
+		expect(res.status).toBe(403);
+	});
+
+	test("is unavailable until the KMS supports it (501)", async () => {
+		config.MINIKMS_CSR_ISSUANCE_ENABLED = "false";
+		try {
+			const res = await testRequest("/api/certificate/issue/csr", {
+				method: "POST",
+				token: seed.masterUser.token,
+				body: {
+					csr_pem: csrPem,
+					member_email: "csr@example.com",
+					role: "developer",
+				},
+			});
+			expect(res.status).toBe(501);
+		} finally {
+			config.MINIKMS_CSR_ISSUANCE_ENABLED = "true";
+		}
+	});
+});
+
 // ─── List certs ─────────────────────────────────────────────────────
 
 describe("GET /api/certificate", () => {
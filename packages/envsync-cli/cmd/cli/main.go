@@ -12,6 +12,7 @@ import (
 	certUseCases "github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/certificate"
 	configUseCases "github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/config"
 	envUseCases "github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/environment"
+	exportUseCases "github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/export"
 	genpem "github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/gen_pem"
 	gpgUseCases "github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/gpg_key"
 	inituc "github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/init"
@@ -36,6 +37,7 @@ func main() {
 		container.GenPEMKeyHandler,
 		container.GpgKeyHandler,
 		container.CertificateHandler,
+		container.ExportHandler,
 	)
 
 	// Build CLI app
@@ -59,6 +61,7 @@ type Container struct {
 	GenPEMKeyHandler   *handlers.GenPEMKeyHandler
 	GpgKeyHandler      *handlers.GpgKeyHandler
 	CertificateHandler *handlers.CertificateHandler
+	ExportHandler      *handlers.ExportHandler
 }
 
 // buildDependencyContainer creates and wires all handler dependencies
@@ -100,6 +103,12 @@ func buildDependencyContainer() *Container {
 	fetchAppUseCase := run.NewFetchAppUseCase()
 	readConfigUseCase := run.NewReadConfigUseCase()
 	runUseCase := run.NewRedactor()
+	watchChangesUseCase := run.NewWatchChangesUseCase()
+	envCacheUseCase := run.NewEnvCacheUseCase()
+	fetchEnvUseCase := run.NewFetchEnvUseCase()
+	loadProcessesUseCase := run.NewLoadProcessesUseCase()
+	superviseProcessesUseCase := run.NewSuperviseProcessesUseCase()
+	interpolateEnvUseCase := run.NewInterpolateEnvUseCase()
 
 	genPEMKeyUseCase := genpem.NewGenKeyPairUseCase()
 
@@ -111,16 +120,35 @@ func buildDependencyContainer() *Container {
 	gpgExportUseCase := gpgUseCases.NewExportUseCase()
 	gpgRevokeUseCase := gpgUseCases.NewRevokeUseCase()
 	gpgDeleteKeyUseCase := gpgUseCases.NewDeleteKeyUseCase()
+	gpgRefreshKeyringUseCase := gpgUseCases.NewRefreshKeyringUseCase()
+	gpgListKeyringUseCase := gpgUseCases.NewListKeyringUseCase()
+	gpgGitSignUseCase := gpgUseCases.NewGitSignUseCase()
+	gpgImportKeyUseCase := gpgUseCases.NewImportKeyUseCase()
+	gpgShowKeyUseCase := gpgUseCases.NewShowKeyUseCase()
+	gpgTrustKeyUseCase := gpgUseCases.NewTrustKeyUseCase()
+	gpgSignManifestUseCase := gpgUseCases.NewSignManifestUseCase()
+	gpgVerifyManifestUseCase := gpgUseCases.NewVerifyManifestUseCase()
+	gpgSignSnapshotUseCase := gpgUseCases.NewSignSnapshotUseCase()
+	gpgVerifySnapshotUseCase := gpgUseCases.NewVerifySnapshotUseCase()
 
 	// Certificate use cases
 	certInitCAUseCase := certUseCases.NewInitCAUseCase()
 	certCAStatusUseCase := certUseCases.NewCAStatusUseCase()
 	certIssueCertUseCase := certUseCases.NewIssueCertUseCase()
+	certIssueCSRUseCase := certUseCases.NewIssueCertFromCSRUseCase()
 	certListCertsUseCase := certUseCases.NewListCertsUseCase()
 	certRevokeCertUseCase := certUseCases.NewRevokeCertUseCase()
 	certCheckOCSPUseCase := certUseCases.NewCheckOCSPUseCase()
 	certGetCRLUseCase := certUseCases.NewGetCRLUseCase()
 	certGetRootCAUseCase := certUseCases.NewGetRootCAUseCase()
+	certVerifyCertUseCase := certUseCases.NewVerifyCertUseCase()
+	certExpiringUseCase := certUseCases.NewExpiringCertsUseCase()
+	certRenewAgentUseCase := certUseCases.NewRenewAgentUseCase()
+	certBundleUseCase := certUseCases.NewBundleCertUseCase()
+
+	// Export use cases
+	exportReadEnvUseCase := exportUseCases.NewReadEnvUseCase()
+	exportUseCase := exportUseCases.NewExportUseCase()
 
 	// Initialize handlers
 	c.AppHandler = handlers.NewAppHandler(
@@ -154,6 +182,7 @@ func buildDependencyContainer() *Container {
 	c.SyncHandler = handlers.NewSyncHandler(
 		pullUseCase,
 		pushUseCase,
+		gpgSignSnapshotUseCase,
 		syncFormatter,
 	)
 
@@ -168,6 +197,13 @@ func buildDependencyContainer() *Container {
 		injectSecretUseCase,
 		fetchAppUseCase,
 		readConfigUseCase,
+		watchChangesUseCase,
+		envCacheUseCase,
+		fetchEnvUseCase,
+		loadProcessesUseCase,
+		superviseProcessesUseCase,
+		interpolateEnvUseCase,
+		gpgVerifySnapshotUseCase,
 	)
 
 	c.GenPEMKeyHandler = handlers.NewGenPEMKeyHandler(
@@ -183,6 +219,14 @@ func buildDependencyContainer() *Container {
 		gpgExportUseCase,
 		gpgRevokeUseCase,
 		gpgDeleteKeyUseCase,
+		gpgRefreshKeyringUseCase,
+		gpgListKeyringUseCase,
+		gpgGitSignUseCase,
+		gpgImportKeyUseCase,
+		gpgShowKeyUseCase,
+		gpgTrustKeyUseCase,
+		gpgSignManifestUseCase,
+		gpgVerifyManifestUseCase,
 		gpgKeyFormatter,
 	)
 
@@ -191,13 +235,30 @@ func buildDependencyContainer() *Container {
 		certInitCAUseCase,
 		certCAStatusUseCase,
 		certIssueCertUseCase,
+		certIssueCSRUseCase,
 		certListCertsUseCase,
 		certRevokeCertUseCase,
 		certCheckOCSPUseCase,
 		certGetCRLUseCase,
 		certGetRootCAUseCase,
+		certVerifyCertUseCase,
+		certExpiringUseCase,
+		certRenewAgentUseCase,
+		certBundleUseCase,
 		certFormatter,
 	)
 
+	exportFormatter := formatters.NewExportFormatter()
+	c.ExportHandler = handlers.NewExportHandler(
+		readConfigUseCase,
+		fetchAppUseCase,
+		injectSecretUseCase,
+		interpolateEnvUseCase,
+		exportReadEnvUseCase,
+		exportUseCase,
+		gpgVerifySnapshotUseCase,
+		exportFormatter,
+	)
+
 	return c
 }
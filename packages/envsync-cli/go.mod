@@ -5,6 +5,7 @@ go 1.24.3
 require (
 	github.com/BurntSushi/toml v1.5.0
 	github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk v0.0.0-00010101000000-000000000000
+	github.com/ProtonMail/go-crypto v1.3.0
 	github.com/aymanbagabas/go-pty v0.2.2
 	github.com/charmbracelet/bubbles v0.21.0
 	github.com/charmbracelet/bubbletea v1.3.5
@@ -25,6 +26,7 @@ require (
 	go.uber.org/zap v1.27.1
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1
 	resty.dev/v3 v3.0.0-beta.3
+	software.sslmate.com/src/go-pkcs12 v0.5.0
 )
 
 replace github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk => ../../sdks/envsync-go-sdk
@@ -40,6 +42,7 @@ require (
 	github.com/charmbracelet/x/cellbuf v0.0.13 // indirect
 	github.com/charmbracelet/x/exp/strings v0.0.0-20250629123816-066ae234febc // indirect
 	github.com/charmbracelet/x/term v0.2.1 // indirect
+	github.com/cloudflare/circl v1.6.1 // indirect
 	github.com/creack/pty v1.1.24 // indirect
 	github.com/dustin/go-humanize v1.0.1 // indirect
 	github.com/erikgeiser/coninput v0.0.0-20211004153227-1c3628e74d0f // indirect
@@ -5,11 +5,23 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 )
 
+// DefaultCacheMaxStaleness is used when cache_max_staleness is not configured
+const DefaultCacheMaxStaleness = 7 * 24 * time.Hour
+
 type AppConfig struct {
-	AccessToken string `json:"access_token"`
-	BackendURL  string `json:"backend_url"`
+	AccessToken       string `json:"access_token"`
+	BackendURL        string `json:"backend_url"`
+	CacheMaxStaleness string `json:"cache_max_staleness,omitempty"`
+	// ClientCert and ClientKey are the paths of a member certificate and its
+	// key, used to authenticate with mutual TLS
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"`
+	// CACert is the path of the CA certificates that verify the backend,
+	// instead of the system roots
+	CACert string `json:"ca_cert,omitempty"`
 }
 
 var cfg AppConfig
@@ -54,6 +66,15 @@ func New() AppConfig {
 	return cfg
 }
 
+// MaxStaleness returns how old the offline environment cache may be before it
+// is no longer used.
+func (c AppConfig) MaxStaleness() time.Duration {
+	if d, err := time.ParseDuration(c.CacheMaxStaleness); err == nil && d > 0 {
+		return d
+	}
+	return DefaultCacheMaxStaleness
+}
+
 func (c *AppConfig) WriteConfigFile() error {
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
@@ -31,3 +31,51 @@ type OCSPResult struct {
 	Status    string  `json:"status"`
 	RevokedAt *string `json:"revoked_at,omitempty"`
 }
+
+// Statuses of a certificate verification check
+const (
+	CertCheckPass = "pass"
+	CertCheckWarn = "warn"
+	CertCheckFail = "fail"
+	CertCheckSkip = "skip"
+)
+
+// CertVerification is the outcome of verifying a certificate locally. It is
+// valid when none of its checks failed.
+type CertVerification struct {
+	Valid     bool        `json:"valid"`
+	SerialHex string      `json:"serial_hex"`
+	SubjectCN string      `json:"subject_cn"`
+	NotBefore time.Time   `json:"not_before"`
+	NotAfter  time.Time   `json:"not_after"`
+	Checks    []CertCheck `json:"checks"`
+}
+
+type CertCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// RenewConfig is the configuration file of 'envsync cert renew-agent'
+type RenewConfig struct {
+	CheckInterval string        `toml:"check_interval,omitempty"`
+	RenewBefore   string        `toml:"renew_before,omitempty"`
+	Certificates  []RenewTarget `toml:"certificate"`
+}
+
+// RenewTarget is a certificate kept on disk that is re-issued before it
+// expires. The key pair is generated locally on every renewal.
+type RenewTarget struct {
+	Email         string            `toml:"email"`
+	Role          string            `toml:"role"`
+	Description   string            `toml:"description,omitempty"`
+	Metadata      map[string]string `toml:"metadata,omitempty"`
+	KeyType       string            `toml:"key_type,omitempty"`
+	CertFile      string            `toml:"cert_file"`
+	KeyFile       string            `toml:"key_file"`
+	RenewBefore   string            `toml:"renew_before,omitempty"`
+	ReloadCommand string            `toml:"reload_command,omitempty"`
+	ReloadSignal  string            `toml:"reload_signal,omitempty"`
+	PIDFile       string            `toml:"pid_file,omitempty"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// EnvCache is the last successfully fetched environment of an app/env pair,
+// kept locally so commands keep working while the API is unreachable.
+type EnvCache struct {
+	AppID            string            `json:"app_id"`
+	EnvTypeID        string            `json:"env_type_id"`
+	Variables        map[string]string `json:"variables"`
+	Secrets          map[string]string `json:"secrets,omitempty"`
+	FetchedAt        time.Time         `json:"fetched_at"`
+	SecretsFetchedAt time.Time         `json:"secrets_fetched_at,omitempty"`
+}
+
+// OldestFetch returns when the least recently fetched part of the cache was
+// stored. Secrets are only considered when the cache holds any.
+func (c *EnvCache) OldestFetch() time.Time {
+	if len(c.Secrets) > 0 && c.SecretsFetchedAt.Before(c.FetchedAt) {
+		return c.SecretsFetchedAt
+	}
+	return c.FetchedAt
+}
+
+// Merged returns the variables and secrets as a single map, with secrets
+// taking precedence.
+func (c *EnvCache) Merged() map[string]string {
+	env := make(map[string]string, len(c.Variables)+len(c.Secrets))
+	for key, value := range c.Variables {
+		env[key] = value
+	}
+	for key, value := range c.Secrets {
+		env[key] = value
+	}
+	return env
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "sort"
+
+// EnvChange describes which keys differ between two resolved environments.
+// Only key names are tracked so the change can be displayed without leaking values.
+type EnvChange struct {
+	Added   []string
+	Updated []string
+	Removed []string
+}
+
+// DiffEnv compares two resolved environments and returns the changed keys in
+// sorted order.
+func DiffEnv(previous, current map[string]string) EnvChange {
+	change := EnvChange{
+		Added:   make([]string, 0),
+		Updated: make([]string, 0),
+		Removed: make([]string, 0),
+	}
+
+	for key, value := range current {
+		oldValue, exists := previous[key]
+		if !exists {
+			change.Added = append(change.Added, key)
+		} else if oldValue != value {
+			change.Updated = append(change.Updated, key)
+		}
+	}
+
+	for key := range previous {
+		if _, exists := current[key]; !exists {
+			change.Removed = append(change.Removed, key)
+		}
+	}
+
+	sort.Strings(change.Added)
+	sort.Strings(change.Updated)
+	sort.Strings(change.Removed)
+
+	return change
+}
+
+// HasChanges returns true if any key was added, updated or removed
+func (c EnvChange) HasChanges() bool {
+	return len(c.Added) > 0 || len(c.Updated) > 0 || len(c.Removed) > 0
+}
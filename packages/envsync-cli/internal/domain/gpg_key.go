@@ -17,6 +17,8 @@ type GpgKey struct {
 	IsDefault   bool       `json:"is_default"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+
+	RevocationReason *string `json:"revocation_reason,omitempty"`
 }
 
 type GpgSignRequest struct {
@@ -33,7 +35,49 @@ type GpgSignatureResult struct {
 }
 
 type GpgVerifyResult struct {
-	Valid             bool    `json:"valid"`
-	SignerFingerprint *string `json:"signer_fingerprint,omitempty"`
-	SignerKeyID       *string `json:"signer_key_id,omitempty"`
+	Valid             bool       `json:"valid"`
+	SignerFingerprint *string    `json:"signer_fingerprint,omitempty"`
+	SignerKeyID       *string    `json:"signer_key_id,omitempty"`
+	SignerName        string     `json:"signer_name,omitempty"`
+	SignedAt          *time.Time `json:"signed_at,omitempty"`
+	// Reason explains why a signature is invalid
+	Reason string `json:"reason,omitempty"`
+}
+
+// KeyringEntry is an organization public key cached in the local keyring,
+// with the key metadata known when it was fetched
+type KeyringEntry struct {
+	GpgKey
+	PublicKey string    `json:"public_key"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// GitSignResult is the outcome of a signing or verification request made by
+// git, in the form GnuPG reports it
+type GitSignResult struct {
+	// Signature is the armored signature when signing
+	Signature string
+	// Status holds the status lines without the "[GNUPG:] " prefix
+	Status []string
+	// Message is the human-readable outcome git shows to the user
+	Message string
+	OK      bool
+}
+
+// ManifestEntry is a file listed in a checksum manifest
+type ManifestEntry struct {
+	Name   string `json:"name"`
+	Digest string `json:"digest"`
+	// Status is OK, FAILED or MISSING after verification
+	Status string `json:"status,omitempty"`
+}
+
+// ManifestResult describes a signed checksum manifest such as SHA256SUMS
+type ManifestResult struct {
+	Manifest     string           `json:"manifest"`
+	Signature    string           `json:"signature"`
+	Algorithm    string           `json:"algorithm"`
+	Entries      []ManifestEntry  `json:"entries"`
+	Verification *GpgVerifyResult `json:"verification,omitempty"`
+	Valid        bool             `json:"valid"`
 }
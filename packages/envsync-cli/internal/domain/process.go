@@ -0,0 +1,43 @@
+package domain
+
+// RestartPolicy controls whether a supervised process is started again after
+// it exits
+type RestartPolicy string
+
+const (
+	RestartNever     RestartPolicy = "no"
+	RestartOnFailure RestartPolicy = "on-failure"
+	RestartAlways    RestartPolicy = "always"
+)
+
+// Valid reports whether the policy is known. The empty policy means "no".
+func (p RestartPolicy) Valid() bool {
+	switch p {
+	case "", RestartNever, RestartOnFailure, RestartAlways:
+		return true
+	}
+	return false
+}
+
+// ShouldRestart reports whether a process that exited with exitCode is restarted
+func (p RestartPolicy) ShouldRestart(exitCode int) bool {
+	switch p {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return exitCode != 0
+	}
+	return false
+}
+
+// Process is one process run by 'envsync run' from a Procfile or from the
+// [processes] table of the project configuration
+type Process struct {
+	Name        string            `toml:"-"`
+	Command     string            `toml:"command,omitempty"`
+	EnvTypeID   string            `toml:"env_type_id,omitempty"`
+	Dir         string            `toml:"dir,omitempty"`
+	Env         map[string]string `toml:"env,omitempty"`
+	Restart     RestartPolicy     `toml:"restart,omitempty"`
+	MaxRestarts int               `toml:"max_restarts,omitempty"`
+}
@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"time"
+)
 
 // EnvironmentVariable represents a single environment variable
 type EnvironmentVariable struct {
@@ -20,8 +25,9 @@ type EnvironmentSync struct {
 
 // SyncConfig represents the configuration needed for syncing
 type SyncConfig struct {
-	AppID     string `toml:"app_id"`
-	EnvTypeID string `toml:"env_type_id"`
+	AppID     string             `toml:"app_id"`
+	EnvTypeID string             `toml:"env_type_id"`
+	Processes map[string]Process `toml:"processes,omitempty"`
 }
 
 // NewEnvironmentSync creates a new EnvironmentSync instance
@@ -101,3 +107,31 @@ type SyncSummary struct {
 	DeleteCount int
 	LastSynced  time.Time
 }
+
+// EnvSnapshot is the set of variables fetched for an app/env pair, as signed
+// by 'envsync pull --sign'.
+type EnvSnapshot struct {
+	AppID     string
+	EnvTypeID string
+	Variables map[string]string
+}
+
+// Canonical returns the byte form of the snapshot that is signed. Variables
+// are sorted by key and quoted, so the form does not depend on map order or
+// on how the variables are later written to disk.
+func (s EnvSnapshot) Canonical() []byte {
+	keys := make([]string, 0, len(s.Variables))
+	for key := range s.Variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("envsync-snapshot v1\n")
+	buf.WriteString("app_id " + strconv.Quote(s.AppID) + "\n")
+	buf.WriteString("env_type_id " + strconv.Quote(s.EnvTypeID) + "\n")
+	for _, key := range keys {
+		buf.WriteString(strconv.Quote(key) + "=" + strconv.Quote(s.Variables[key]) + "\n")
+	}
+	return buf.Bytes()
+}
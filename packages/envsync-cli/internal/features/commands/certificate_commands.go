@@ -18,6 +18,10 @@ func CertificateCommands(handler *handlers.CertificateHandler) *cli.Command {
 			certOCSPCommand(handler),
 			certCRLCommand(handler),
 			certRootCACommand(handler),
+			certVerifyCommand(handler),
+			certExpiringCommand(handler),
+			certRenewAgentCommand(handler),
+			certBundleCommand(handler),
 		},
 	}
 }
@@ -84,6 +88,15 @@ func certIssueCommand(handler *handlers.CertificateHandler) *cli.Command {
 				Name:  "output-key",
 				Usage: "Save private key PEM to file",
 			},
+			&cli.BoolFlag{
+				Name:  "csr",
+				Usage: "Generate the key pair locally and submit a certificate signing request, so the private key never leaves this machine (requires --output-key)",
+			},
+			&cli.StringFlag{
+				Name:  "key-type",
+				Usage: "Key type for --csr (rsa, ecdsa-p256, ecdsa-p384, ed25519)",
+				Value: "ecdsa-p256",
+			},
 		},
 	}
 }
@@ -158,3 +171,119 @@ func certRootCACommand(handler *handlers.CertificateHandler) *cli.Command {
 		},
 	}
 }
+
+func certVerifyCommand(handler *handlers.CertificateHandler) *cli.Command {
+	return &cli.Command{
+		Name:   "verify",
+		Usage:  "Verify the chain, validity, key usage and revocation of a certificate locally",
+		Action: handler.VerifyCert,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "cert",
+				Usage:    "Certificate PEM file, optionally followed by its chain",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "root-ca",
+				Usage: "Root CA PEM file (default: fetched from the server)",
+			},
+			&cli.StringFlag{
+				Name:  "chain",
+				Usage: "Intermediate CA PEM file (default: the organization CA)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "crl",
+				Usage: "CRL file, full or delta, PEM or DER (repeatable; default: fetched from the server)",
+			},
+			&cli.StringFlag{
+				Name:  "usage",
+				Usage: "Key usage the certificate must allow (client, server, any)",
+				Value: "client",
+			},
+			&cli.BoolFlag{
+				Name:  "ocsp",
+				Usage: "Cross-check the revocation status with the server's OCSP responder",
+			},
+		},
+	}
+}
+
+func certExpiringCommand(handler *handlers.CertificateHandler) *cli.Command {
+	return &cli.Command{
+		Name:   "expiring",
+		Usage:  "List active certificates that expire soon",
+		Action: handler.ExpiringCerts,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "within",
+				Usage: "Time window, e.g. 30d or 72h",
+				Value: "30d",
+			},
+		},
+	}
+}
+
+func certRenewAgentCommand(handler *handlers.CertificateHandler) *cli.Command {
+	return &cli.Command{
+		Name:   "renew-agent",
+		Usage:  "Re-issue certificates on disk before they expire and reload their consumers",
+		Action: handler.RenewAgent,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "config",
+				Usage:    "Renew configuration file (TOML)",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:  "once",
+				Usage: "Check the certificates once and exit",
+			},
+		},
+	}
+}
+
+func certBundleCommand(handler *handlers.CertificateHandler) *cli.Command {
+	return &cli.Command{
+		Name:   "bundle",
+		Usage:  "Package a certificate, its key and the CA chain as PKCS#12, full-chain PEM or combined PEM",
+		Action: handler.BundleCert,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "cert",
+				Usage:    "Certificate PEM file, optionally followed by its chain",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "key",
+				Usage: "Private key PEM file (required for pkcs12 and combined)",
+			},
+			&cli.StringFlag{
+				Name:  "chain",
+				Usage: "CA chain PEM file (default: the organization and root CAs)",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Bundle format (pkcs12, fullchain, combined)",
+				Value: "pkcs12",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output file path (default: stdout, except for pkcs12)",
+			},
+			&cli.StringFlag{
+				Name:    "password",
+				Usage:   "PKCS#12 password",
+				Sources: cli.EnvVars("ENVSYNC_BUNDLE_PASSWORD"),
+			},
+			&cli.StringFlag{
+				Name:  "password-file",
+				Usage: "Read the PKCS#12 password from a file",
+			},
+			&cli.BoolFlag{
+				Name:  "legacy",
+				Usage: "Encrypt PKCS#12 bundles with legacy algorithms for Java before 8u301 and OpenSSL 1.x",
+			},
+		},
+	}
+}
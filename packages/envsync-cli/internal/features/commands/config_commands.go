@@ -29,9 +29,13 @@ func Set(handler *handlers.ConfigHandler) *cli.Command {
 
 Examples:
   envsync config set backend_url=https://api.envsync.cloud
+  envsync config set client_cert=service.crt client_key=service.key
 
 Supported keys:
-  - backend_url: Backend API URL`,
+  - backend_url: Backend API URL
+  - cache_max_staleness: Maximum age of the offline environment cache
+  - client_cert, client_key: Member certificate and key for mutual TLS
+  - ca_cert: CA certificates that verify the backend`,
 	}
 }
 
@@ -48,7 +52,10 @@ Examples:
   envsync config get backend_url
 
 Supported keys:
-  - backend_url: Backend API URL`,
+  - backend_url: Backend API URL
+  - cache_max_staleness: Maximum age of the offline environment cache
+  - client_cert, client_key: Member certificate and key for mutual TLS
+  - ca_cert: CA certificates that verify the backend`,
 	}
 }
 
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/handlers"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/export"
+	"github.com/urfave/cli/v3"
+)
+
+func ExportCommand(handler *handlers.ExportHandler) *cli.Command {
+	return &cli.Command{
+		Name:   "export",
+		Usage:  "Render the environment into a deployment format",
+		Action: handler.Export,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "format",
+				Usage:    "Output format (" + strings.Join(export.SupportedFormats(), ", ") + ")",
+				Aliases:  []string{"f"},
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "output",
+				Usage:    "File to write the export to (written with 0600 permissions); defaults to stdout",
+				Aliases:  []string{"o"},
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "name",
+				Usage:    "Resource name for Kubernetes formats (defaults to the application name)",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "namespace",
+				Usage:    "Namespace for Kubernetes formats",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "private-key",
+				Usage:    "Path to the private key for decrypting non-managed secrets",
+				Aliases:  []string{"pk"},
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "strict-references",
+				Usage:    "Fail on ${...} references that cannot be resolved instead of keeping them verbatim",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "require-signature",
+				Usage:    "Refuse to continue unless the variables match the snapshot signed by 'envsync pull --sign'",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "signature",
+				Usage:    "Snapshot signature to verify with --require-signature",
+				Value:    ".env.sig",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "signer-fingerprint",
+				Usage:    "Fingerprint of the only key trusted to sign snapshots",
+				Sources:  cli.EnvVars("ENVSYNC_SIGNER_FINGERPRINT"),
+				Required: false,
+			},
+		},
+	}
+}
@@ -13,11 +13,18 @@ func GpgKeyCommands(handler *handlers.GpgKeyHandler) *cli.Command {
 		Commands: []*cli.Command{
 			gpgListCommand(handler),
 			gpgGenerateCommand(handler),
+			gpgImportCommand(handler),
+			gpgShowCommand(handler),
+			gpgTrustCommand(handler),
 			gpgSignCommand(handler),
 			gpgVerifyCommand(handler),
+			gpgSignManifestCommand(handler),
+			gpgVerifyManifestCommand(handler),
 			gpgExportCommand(handler),
 			gpgRevokeCommand(handler),
 			gpgDeleteCommand(handler),
+			gpgKeyringCommand(handler),
+			gpgGitSignCommand(handler),
 		},
 	}
 }
@@ -67,6 +74,69 @@ func gpgGenerateCommand(handler *handlers.GpgKeyHandler) *cli.Command {
 	}
 }
 
+func gpgImportCommand(handler *handlers.GpgKeyHandler) *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Import an armored public or private GPG key",
+		ArgsUsage: "[file]",
+		Action:    handler.Import,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "file",
+				Usage: "Path to the armored key (or pipe via stdin)",
+			},
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "Key name (default: name of the primary user ID)",
+			},
+			&cli.StringFlag{
+				Name:    "passphrase",
+				Usage:   "Passphrase of an encrypted private key (prompted for if omitted)",
+				Sources: cli.EnvVars("ENVSYNC_GPG_PASSPHRASE"),
+			},
+			&cli.StringFlag{
+				Name:  "passphrase-file",
+				Usage: "Read the private key passphrase from a file",
+			},
+		},
+	}
+}
+
+func gpgShowCommand(handler *handlers.GpgKeyHandler) *cli.Command {
+	return &cli.Command{
+		Name:   "show",
+		Usage:  "Show fingerprint, usage and trust details of a GPG key",
+		Action: handler.Show,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "key-id",
+				Usage:    "GPG key ID to show",
+				Required: true,
+			},
+		},
+	}
+}
+
+func gpgTrustCommand(handler *handlers.GpgKeyHandler) *cli.Command {
+	return &cli.Command{
+		Name:   "trust",
+		Usage:  "Set the trust level of a GPG key",
+		Action: handler.Trust,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "key-id",
+				Usage:    "GPG key ID to update",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "level",
+				Usage:    "Trust level (unknown, never, marginal, full, ultimate)",
+				Required: true,
+			},
+		},
+	}
+}
+
 func gpgSignCommand(handler *handlers.GpgKeyHandler) *cli.Command {
 	return &cli.Command{
 		Name:   "sign",
@@ -103,22 +173,77 @@ func gpgSignCommand(handler *handlers.GpgKeyHandler) *cli.Command {
 func gpgVerifyCommand(handler *handlers.GpgKeyHandler) *cli.Command {
 	return &cli.Command{
 		Name:   "verify",
-		Usage:  "Verify a GPG signature",
+		Usage:  "Verify a GPG signature locally against the cached organization keys",
 		Action: handler.Verify,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:     "file",
-				Usage:    "Path to the signed data file",
+				Usage:    "Path to the signed data file, or to a clearsigned file",
 				Required: true,
 			},
 			&cli.StringFlag{
-				Name:     "signature",
-				Usage:    "Path to the signature file",
-				Required: true,
+				Name:  "signature",
+				Usage: "Path to the detached signature file (omit for clearsigned files)",
+			},
+			&cli.StringFlag{
+				Name:  "key-id",
+				Usage: "GPG key ID, fingerprint or OpenPGP key ID (optional, tries all org keys if omitted)",
+			},
+			&cli.BoolFlag{
+				Name:  "refresh",
+				Usage: "Refresh the local keyring from the API before verifying",
 			},
+		},
+	}
+}
+
+func gpgSignManifestCommand(handler *handlers.GpgKeyHandler) *cli.Command {
+	return &cli.Command{
+		Name:      "sign-manifest",
+		Usage:     "Write a checksum manifest of release artifacts and sign it",
+		ArgsUsage: "<file> [file2 ...]",
+		Action:    handler.SignManifest,
+		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:  "key-id",
-				Usage: "GPG key ID (optional, tries all org keys if omitted)",
+				Usage: "GPG key ID, fingerprint or email (default: the organization default key)",
+			},
+			&cli.StringFlag{
+				Name:  "algorithm",
+				Usage: "Digest algorithm (sha256, sha512)",
+				Value: "sha256",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Manifest path (default: SHA256SUMS or SHA512SUMS next to the first file)",
+			},
+			&cli.BoolFlag{
+				Name:  "clearsign",
+				Usage: "Write a clearsigned copy of the manifest instead of a detached signature",
+			},
+		},
+	}
+}
+
+func gpgVerifyManifestCommand(handler *handlers.GpgKeyHandler) *cli.Command {
+	return &cli.Command{
+		Name:      "verify-manifest",
+		Usage:     "Verify a signed checksum manifest and every file it lists",
+		ArgsUsage: "<manifest>",
+		Action:    handler.VerifyManifest,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "signature",
+				Usage: "Path to the manifest signature (default: <manifest>.asc, not needed for clearsigned manifests)",
+			},
+			&cli.StringFlag{
+				Name:  "key-id",
+				Usage: "GPG key ID, fingerprint or OpenPGP key ID (optional, tries all org keys if omitted)",
+			},
+			&cli.BoolFlag{
+				Name:  "refresh",
+				Usage: "Refresh the local keyring from the API before verifying",
 			},
 		},
 	}
@@ -176,3 +301,40 @@ func gpgDeleteCommand(handler *handlers.GpgKeyHandler) *cli.Command {
 		},
 	}
 }
+
+func gpgKeyringCommand(handler *handlers.GpgKeyHandler) *cli.Command {
+	return &cli.Command{
+		Name:  "keyring",
+		Usage: "Manage the local keyring used for offline verification",
+		Commands: []*cli.Command{
+			{
+				Name:   "refresh",
+				Usage:  "Fetch organization public keys into the local keyring",
+				Action: handler.RefreshKeyring,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "key-id",
+						Usage: "Only fetch this key (default: all organization keys)",
+					},
+				},
+			},
+			{
+				Name:   "list",
+				Usage:  "List the keys in the local keyring",
+				Action: handler.ListKeyring,
+			},
+		},
+	}
+}
+
+func gpgGitSignCommand(handler *handlers.GpgKeyHandler) *cli.Command {
+	return &cli.Command{
+		Name:      "git-sign",
+		Usage:     "Sign and verify git commits and tags with organization keys (use as gpg.program)",
+		UsageText: "envsync gpg git-sign --status-fd=2 -bsau <key>\nenvsync gpg git-sign --status-fd=1 --verify <signature> -",
+		Description: "Accepts the gpg options git passes to gpg.program. Since git runs gpg.program\n" +
+			"without a shell, point it at a script that runs `envsync gpg git-sign \"$@\"`.",
+		SkipFlagParsing: true,
+		Action:          handler.GitSign,
+	}
+}
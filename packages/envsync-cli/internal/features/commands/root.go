@@ -34,6 +34,7 @@ type CommandRegistry struct {
 	genPEMKeyHandler   *handlers.GenPEMKeyHandler
 	gpgKeyHandler      *handlers.GpgKeyHandler
 	certificateHandler *handlers.CertificateHandler
+	exportHandler      *handlers.ExportHandler
 }
 
 func NewCommandRegistry(
@@ -47,6 +48,7 @@ func NewCommandRegistry(
 	genPEMKeyHandler *handlers.GenPEMKeyHandler,
 	gpgKeyHandler *handlers.GpgKeyHandler,
 	certificateHandler *handlers.CertificateHandler,
+	exportHandler *handlers.ExportHandler,
 ) *CommandRegistry {
 	return &CommandRegistry{
 		appHandler:         appHandler,
@@ -59,6 +61,7 @@ func NewCommandRegistry(
 		genPEMKeyHandler:   genPEMKeyHandler,
 		gpgKeyHandler:      gpgKeyHandler,
 		certificateHandler: certificateHandler,
+		exportHandler:      exportHandler,
 	}
 }
 
@@ -91,6 +94,7 @@ func (r *CommandRegistry) RegisterCLI() *cli.Command {
 			GenereatePrivateKeyCommand(r.genPEMKeyHandler),
 			GpgKeyCommands(r.gpgKeyHandler),
 			CertificateCommands(r.certificateHandler),
+			ExportCommand(r.exportHandler),
 		},
 	}
 }
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"time"
+
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/handlers"
 	"github.com/urfave/cli/v3"
 )
@@ -15,7 +17,12 @@ func RunCommand(handler *handlers.RunHandler) *cli.Command {
 				Name:     "command",
 				Usage:    "Command to run the application",
 				Aliases:  []string{"c"},
-				Required: true,
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "procfile",
+				Usage:    "Run all processes of a Procfile with a shared environment",
+				Required: false,
 			},
 			&cli.StringFlag{
 				Name:     "private-key",
@@ -23,6 +30,56 @@ func RunCommand(handler *handlers.RunHandler) *cli.Command {
 				Aliases:  []string{"pk"},
 				Required: false,
 			},
+			&cli.BoolFlag{
+				Name:     "offline",
+				Usage:    "Use the locally cached environment without contacting the API",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "watch",
+				Usage:    "Restart the application when the remote environment changes",
+				Aliases:  []string{"w"},
+				Required: false,
+			},
+			&cli.DurationFlag{
+				Name:     "watch-interval",
+				Usage:    "How often to poll for remote changes in watch mode",
+				Value:    10 * time.Second,
+				Required: false,
+			},
+			&cli.DurationFlag{
+				Name:     "debounce",
+				Usage:    "Quiet period to wait for further changes before reloading",
+				Value:    2 * time.Second,
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "reload-signal",
+				Usage:    "Send this signal (e.g. SIGHUP) instead of restarting in watch mode",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "strict-references",
+				Usage:    "Fail on ${...} references that cannot be resolved instead of keeping them verbatim",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "require-signature",
+				Usage:    "Refuse to continue unless the variables match the snapshot signed by 'envsync pull --sign'",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "signature",
+				Usage:    "Snapshot signature to verify with --require-signature",
+				Value:    ".env.sig",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "signer-fingerprint",
+				Usage:    "Fingerprint of the only key trusted to sign snapshots",
+				Sources:  cli.EnvVars("ENVSYNC_SIGNER_FINGERPRINT"),
+				Required: false,
+			},
 		},
 	}
 }
@@ -18,6 +18,27 @@ func PullCommand(handler *handlers.SyncHandler) *cli.Command {
 				Usage:       "Path to the configuration file",
 				Value:       "envsyncrc.toml",
 			},
+			&cli.BoolFlag{
+				Name:     "offline",
+				Usage:    "Write the locally cached variables without contacting the API",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "sign",
+				Usage:    "Sign the fetched variables with an organization GPG key",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "key-id",
+				Usage:    "GPG key ID, fingerprint or email to sign with (default: the organization default key)",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "signature",
+				Usage:    "File to write the snapshot signature to",
+				Value:    ".env.sig",
+				Required: false,
+			},
 		},
 	}
 }
@@ -2,24 +2,35 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
 	certUC "github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/certificate"
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/presentation/formatters"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/utils"
 )
 
 type CertificateHandler struct {
 	initCAUseCase     certUC.InitCAUseCase
 	caStatusUseCase   certUC.CAStatusUseCase
 	issueCertUseCase  certUC.IssueCertUseCase
+	issueCSRUseCase   certUC.IssueCertFromCSRUseCase
 	listCertsUseCase  certUC.ListCertsUseCase
 	revokeCertUseCase certUC.RevokeCertUseCase
 	checkOCSPUseCase  certUC.CheckOCSPUseCase
 	getCRLUseCase     certUC.GetCRLUseCase
 	getRootCAUseCase  certUC.GetRootCAUseCase
+	verifyCertUseCase certUC.VerifyCertUseCase
+	expiringUseCase   certUC.ExpiringCertsUseCase
+	renewAgentUseCase certUC.RenewAgentUseCase
+	bundleUseCase     certUC.BundleCertUseCase
 	formatter         *formatters.CertificateFormatter
 }
 
@@ -27,22 +38,32 @@ func NewCertificateHandler(
 	initCAUseCase certUC.InitCAUseCase,
 	caStatusUseCase certUC.CAStatusUseCase,
 	issueCertUseCase certUC.IssueCertUseCase,
+	issueCSRUseCase certUC.IssueCertFromCSRUseCase,
 	listCertsUseCase certUC.ListCertsUseCase,
 	revokeCertUseCase certUC.RevokeCertUseCase,
 	checkOCSPUseCase certUC.CheckOCSPUseCase,
 	getCRLUseCase certUC.GetCRLUseCase,
 	getRootCAUseCase certUC.GetRootCAUseCase,
+	verifyCertUseCase certUC.VerifyCertUseCase,
+	expiringUseCase certUC.ExpiringCertsUseCase,
+	renewAgentUseCase certUC.RenewAgentUseCase,
+	bundleUseCase certUC.BundleCertUseCase,
 	formatter *formatters.CertificateFormatter,
 ) *CertificateHandler {
 	return &CertificateHandler{
 		initCAUseCase:     initCAUseCase,
 		caStatusUseCase:   caStatusUseCase,
 		issueCertUseCase:  issueCertUseCase,
+		issueCSRUseCase:   issueCSRUseCase,
 		listCertsUseCase:  listCertsUseCase,
 		revokeCertUseCase: revokeCertUseCase,
 		checkOCSPUseCase:  checkOCSPUseCase,
 		getCRLUseCase:     getCRLUseCase,
 		getRootCAUseCase:  getRootCAUseCase,
+		verifyCertUseCase: verifyCertUseCase,
+		expiringUseCase:   expiringUseCase,
+		renewAgentUseCase: renewAgentUseCase,
+		bundleUseCase:     bundleUseCase,
 		formatter:         formatter,
 	}
 }
@@ -95,24 +116,36 @@ func (h *CertificateHandler) IssueCert(ctx context.Context, cmd *cli.Command) er
 		}
 	}
 
-	cert, err := h.issueCertUseCase.Execute(ctx, email, role, description, metadata)
-	if err != nil {
-		return h.formatError(cmd, err)
-	}
-
 	// Save cert/key to files if output paths specified
 	certPath := cmd.String("output-cert")
 	keyPath := cmd.String("output-key")
 
-	if certPath != "" && cert.CertPEM != "" {
-		if err := os.WriteFile(certPath, []byte(cert.CertPEM+"\n"), 0644); err != nil {
-			return h.formatter.FormatError(cmd.Writer, "Failed to write certificate: "+err.Error())
+	var cert *domain.Certificate
+	var err error
+	if cmd.Bool("csr") {
+		// The key is generated locally, so it must be kept somewhere
+		if keyPath == "" {
+			return h.formatter.FormatError(cmd.Writer, "--output-key is required with --csr")
 		}
+		cert, err = h.issueCSRUseCase.Execute(ctx, email, role, description, cmd.String("key-type"), metadata)
+	} else {
+		cert, err = h.issueCertUseCase.Execute(ctx, email, role, description, metadata)
 	}
+	if err != nil {
+		return h.formatError(cmd, err)
+	}
+
+	// The key and certificate are written together, so neither is left
+	// behind without the other
+	var files []utils.AtomicFile
 	if keyPath != "" && cert.KeyPEM != "" {
-		if err := os.WriteFile(keyPath, []byte(cert.KeyPEM+"\n"), 0600); err != nil {
-			return h.formatter.FormatError(cmd.Writer, "Failed to write key: "+err.Error())
-		}
+		files = append(files, utils.AtomicFile{Path: keyPath, Data: []byte(cert.KeyPEM + "\n"), Perm: 0600})
+	}
+	if certPath != "" && cert.CertPEM != "" {
+		files = append(files, utils.AtomicFile{Path: certPath, Data: []byte(cert.CertPEM + "\n"), Perm: 0644})
+	}
+	if err := utils.WriteFilesAtomic(files...); err != nil {
+		return h.formatter.FormatError(cmd.Writer, "Failed to write certificate and key: "+err.Error())
 	}
 
 	if cmd.Bool("json") {
@@ -229,6 +262,146 @@ func (h *CertificateHandler) GetRootCA(ctx context.Context, cmd *cli.Command) er
 	return h.formatter.FormatCertPEM(cmd.Writer, certPEM)
 }
 
+func (h *CertificateHandler) VerifyCert(ctx context.Context, cmd *cli.Command) error {
+	certPEM, err := os.ReadFile(cmd.String("cert"))
+	if err != nil {
+		return h.formatError(cmd, certUC.NewIOError("failed to read certificate", err))
+	}
+
+	opts := certUC.VerifyCertOptions{
+		Usage: cmd.String("usage"),
+		OCSP:  cmd.Bool("ocsp"),
+	}
+	if path := cmd.String("root-ca"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return h.formatError(cmd, certUC.NewIOError("failed to read root CA", err))
+		}
+		opts.RootCAPEM = string(data)
+	}
+	if path := cmd.String("chain"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return h.formatError(cmd, certUC.NewIOError("failed to read chain", err))
+		}
+		opts.ChainPEM = string(data)
+	}
+	for _, path := range cmd.StringSlice("crl") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return h.formatError(cmd, certUC.NewIOError("failed to read CRL", err))
+		}
+		opts.CRLPEMs = append(opts.CRLPEMs, string(data))
+	}
+
+	result, err := h.verifyCertUseCase.Execute(ctx, string(certPEM), opts)
+	if err != nil {
+		return h.formatError(cmd, err)
+	}
+
+	if cmd.Bool("json") {
+		err = h.formatter.FormatJSON(cmd.Writer, result)
+	} else {
+		err = h.formatter.FormatVerification(cmd.Writer, *result)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Fail the command so health checks can rely on the exit status
+	if !result.Valid {
+		return errors.New("certificate verification failed")
+	}
+	return nil
+}
+
+func (h *CertificateHandler) ExpiringCerts(ctx context.Context, cmd *cli.Command) error {
+	within, err := utils.ParseDuration(cmd.String("within"))
+	if err != nil || within < 0 {
+		return h.formatError(cmd, certUC.NewValidationError("within must be a duration such as 30d or 72h", err))
+	}
+
+	certs, err := h.expiringUseCase.Execute(ctx, within)
+	if err != nil {
+		return h.formatError(cmd, err)
+	}
+
+	if cmd.Bool("json") {
+		return h.formatter.FormatJSON(cmd.Writer, certs)
+	}
+
+	return h.formatter.FormatExpiringCerts(cmd.Writer, certs)
+}
+
+func (h *CertificateHandler) RenewAgent(ctx context.Context, cmd *cli.Command) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := h.renewAgentUseCase.Execute(ctx, cmd.String("config"), cmd.Bool("once"), cmd.Writer)
+	if _, ok := err.(*certUC.CertError); ok {
+		return h.formatError(cmd, err)
+	}
+	// Renewal failures were already reported and fail the command
+	return err
+}
+
+func (h *CertificateHandler) BundleCert(ctx context.Context, cmd *cli.Command) error {
+	req := certUC.BundleRequest{
+		Format:   certUC.BundleFormat(cmd.String("format")),
+		Output:   cmd.String("output"),
+		Password: cmd.String("password"),
+		Legacy:   cmd.Bool("legacy"),
+	}
+	if req.Format == certUC.BundlePKCS12 && req.Output == "" {
+		return h.formatError(cmd, certUC.NewValidationError("--output is required for pkcs12 bundles", nil))
+	}
+
+	files := []struct {
+		flag string
+		dest *string
+	}{
+		{"cert", &req.CertPEM},
+		{"key", &req.KeyPEM},
+		{"chain", &req.ChainPEM},
+		{"password-file", &req.Password},
+	}
+	for _, file := range files {
+		path := cmd.String(file.flag)
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return h.formatError(cmd, certUC.NewIOError("failed to read "+file.flag, err))
+		}
+		*file.dest = string(data)
+	}
+	if cmd.IsSet("password-file") {
+		req.Password = strings.TrimRight(req.Password, "\r\n")
+	}
+
+	res, err := h.bundleUseCase.Execute(ctx, req)
+	if err != nil {
+		return h.formatError(cmd, err)
+	}
+
+	// Without an output file the bundle is the command output
+	if res.Path == "" {
+		_, err := cmd.Writer.Write(res.Data)
+		return err
+	}
+
+	if cmd.Bool("json") {
+		return h.formatter.FormatJSON(cmd.Writer, map[string]any{
+			"format": res.Format,
+			"path":   res.Path,
+			"chain":  res.Chain,
+		})
+	}
+
+	return h.formatter.FormatSuccess(cmd.Writer, fmt.Sprintf("Wrote %s bundle with %d CA certificates to %s", res.Format, res.Chain, res.Path))
+}
+
 func (h *CertificateHandler) formatError(cmd *cli.Command, err error) error {
 	if cmd.Bool("json") {
 		return h.formatter.FormatJSONError(cmd.Writer, err)
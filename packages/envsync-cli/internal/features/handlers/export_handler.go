@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/export"
+	gpg_key "github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/gpg_key"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/run"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/presentation/formatters"
+)
+
+type ExportHandler struct {
+	readConfigUseCase     run.ReadConfigUseCase
+	appUseCase            run.FetchAppUseCase
+	injectSecretUseCase   run.InjectSecretsUseCase
+	interpolateUseCase    run.InterpolateEnvUseCase
+	readEnvUseCase        export.ReadEnvUseCase
+	exportUseCase         export.ExportUseCase
+	verifySnapshotUseCase gpg_key.VerifySnapshotUseCase
+	formatter             *formatters.ExportFormatter
+}
+
+func NewExportHandler(
+	readConfigUseCase run.ReadConfigUseCase,
+	appUseCase run.FetchAppUseCase,
+	injectSecretUseCase run.InjectSecretsUseCase,
+	interpolateUseCase run.InterpolateEnvUseCase,
+	readEnvUseCase export.ReadEnvUseCase,
+	exportUseCase export.ExportUseCase,
+	verifySnapshotUseCase gpg_key.VerifySnapshotUseCase,
+	formatter *formatters.ExportFormatter,
+) *ExportHandler {
+	return &ExportHandler{
+		readConfigUseCase:     readConfigUseCase,
+		appUseCase:            appUseCase,
+		injectSecretUseCase:   injectSecretUseCase,
+		interpolateUseCase:    interpolateUseCase,
+		readEnvUseCase:        readEnvUseCase,
+		exportUseCase:         exportUseCase,
+		verifySnapshotUseCase: verifySnapshotUseCase,
+		formatter:             formatter,
+	}
+}
+
+func (h *ExportHandler) Export(ctx context.Context, cmd *cli.Command) error {
+	configData, err := h.readConfigUseCase.Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	app, err := h.appUseCase.Execute(ctx, configData.AppID)
+	if err != nil {
+		return err
+	}
+
+	variables, err := h.readEnvUseCase.Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Bool("require-signature") {
+		ctx = requireSignature(ctx, cmd)
+	}
+	if err := verifySnapshot(ctx, h.verifySnapshotUseCase, configData, variables); err != nil {
+		return err
+	}
+
+	var secrets map[string]string
+	if app.EnableSecrets {
+		if !cmd.IsSet("private-key") && !app.IsManagedSecret {
+			return errors.New("private-key flag is required when secrets are enabled")
+		}
+
+		ctx = context.WithValue(ctx, "managedSecret", app.IsManagedSecret)
+		ctx = context.WithValue(ctx, "privateKeyPath", cmd.String("private-key"))
+		ctx = context.WithValue(ctx, "appID", configData.AppID)
+		ctx = context.WithValue(ctx, "envTypeID", configData.EnvTypeID)
+
+		secrets, err = h.injectSecretUseCase.Execute(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Variables built from secrets are exported as secrets
+	variables, secrets, unresolved, err := h.interpolateUseCase.Execute(ctx, configData.AppID, variables, secrets, cmd.Bool("strict-references"))
+	if err != nil {
+		return fmt.Errorf("failed to interpolate environment: %w", err)
+	}
+	warnUnresolved(unresolved)
+
+	name := cmd.String("name")
+	if name == "" {
+		name = app.Name
+	}
+
+	res, err := h.exportUseCase.Execute(ctx, export.ExportRequest{
+		Format:    export.Format(cmd.String("format")),
+		Output:    cmd.String("output"),
+		Name:      name,
+		Namespace: cmd.String("namespace"),
+		Variables: variables,
+		Secrets:   secrets,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Without an output file the rendered environment is the command output
+	if res.Path == "" {
+		_, err := cmd.Writer.Write(res.Data)
+		return err
+	}
+
+	if cmd.Bool("json") {
+		return h.formatter.FormatJSON(cmd.Writer, map[string]any{
+			"format": res.Format,
+			"path":   res.Path,
+			"count":  res.Count,
+		})
+	}
+
+	return h.formatter.FormatSuccess(cmd.Writer, fmt.Sprintf("Exported %d variables as %s to %s", res.Count, res.Format, res.Path))
+}
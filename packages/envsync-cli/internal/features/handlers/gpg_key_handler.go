@@ -2,23 +2,37 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
 	gpg_key "github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/gpg_key"
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/presentation/formatters"
 )
 
 type GpgKeyHandler struct {
-	listUseCase     gpg_key.ListKeysUseCase
-	generateUseCase gpg_key.GenerateKeyUseCase
-	signUseCase     gpg_key.SignUseCase
-	verifyUseCase   gpg_key.VerifyUseCase
-	exportUseCase   gpg_key.ExportUseCase
-	revokeUseCase   gpg_key.RevokeUseCase
-	deleteUseCase   gpg_key.DeleteKeyUseCase
-	formatter       *formatters.GpgKeyFormatter
+	listUseCase           gpg_key.ListKeysUseCase
+	generateUseCase       gpg_key.GenerateKeyUseCase
+	signUseCase           gpg_key.SignUseCase
+	verifyUseCase         gpg_key.VerifyUseCase
+	exportUseCase         gpg_key.ExportUseCase
+	revokeUseCase         gpg_key.RevokeUseCase
+	deleteUseCase         gpg_key.DeleteKeyUseCase
+	refreshUseCase        gpg_key.RefreshKeyringUseCase
+	keyringUseCase        gpg_key.ListKeyringUseCase
+	gitSignUseCase        gpg_key.GitSignUseCase
+	importUseCase         gpg_key.ImportKeyUseCase
+	showUseCase           gpg_key.ShowKeyUseCase
+	trustUseCase          gpg_key.TrustKeyUseCase
+	signManifestUseCase   gpg_key.SignManifestUseCase
+	verifyManifestUseCase gpg_key.VerifyManifestUseCase
+	formatter             *formatters.GpgKeyFormatter
 }
 
 func NewGpgKeyHandler(
@@ -29,17 +43,33 @@ func NewGpgKeyHandler(
 	exportUseCase gpg_key.ExportUseCase,
 	revokeUseCase gpg_key.RevokeUseCase,
 	deleteUseCase gpg_key.DeleteKeyUseCase,
+	refreshUseCase gpg_key.RefreshKeyringUseCase,
+	keyringUseCase gpg_key.ListKeyringUseCase,
+	gitSignUseCase gpg_key.GitSignUseCase,
+	importUseCase gpg_key.ImportKeyUseCase,
+	showUseCase gpg_key.ShowKeyUseCase,
+	trustUseCase gpg_key.TrustKeyUseCase,
+	signManifestUseCase gpg_key.SignManifestUseCase,
+	verifyManifestUseCase gpg_key.VerifyManifestUseCase,
 	formatter *formatters.GpgKeyFormatter,
 ) *GpgKeyHandler {
 	return &GpgKeyHandler{
-		listUseCase:     listUseCase,
-		generateUseCase: generateUseCase,
-		signUseCase:     signUseCase,
-		verifyUseCase:   verifyUseCase,
-		exportUseCase:   exportUseCase,
-		revokeUseCase:   revokeUseCase,
-		deleteUseCase:   deleteUseCase,
-		formatter:       formatter,
+		listUseCase:           listUseCase,
+		generateUseCase:       generateUseCase,
+		signUseCase:           signUseCase,
+		verifyUseCase:         verifyUseCase,
+		exportUseCase:         exportUseCase,
+		revokeUseCase:         revokeUseCase,
+		deleteUseCase:         deleteUseCase,
+		refreshUseCase:        refreshUseCase,
+		keyringUseCase:        keyringUseCase,
+		gitSignUseCase:        gitSignUseCase,
+		importUseCase:         importUseCase,
+		showUseCase:           showUseCase,
+		trustUseCase:          trustUseCase,
+		signManifestUseCase:   signManifestUseCase,
+		verifyManifestUseCase: verifyManifestUseCase,
+		formatter:             formatter,
 	}
 }
 
@@ -129,7 +159,35 @@ func (h *GpgKeyHandler) Verify(ctx context.Context, cmd *cli.Command) error {
 	signaturePath := cmd.String("signature")
 	keyID := cmd.String("key-id")
 
-	result, err := h.verifyUseCase.Execute(ctx, filePath, signaturePath, keyID)
+	result, err := h.verifyUseCase.Execute(ctx, filePath, signaturePath, keyID, cmd.Bool("refresh"))
+	if err != nil {
+		return h.formatError(cmd, err)
+	}
+
+	if cmd.Bool("json") {
+		err = h.formatter.FormatJSON(cmd.Writer, result)
+	} else {
+		err = h.formatter.FormatVerifyResult(cmd.Writer, *result)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Fail the command so release pipelines can rely on the exit status
+	if !result.Valid {
+		return errors.New("signature verification failed")
+	}
+	return nil
+}
+
+func (h *GpgKeyHandler) SignManifest(ctx context.Context, cmd *cli.Command) error {
+	result, err := h.signManifestUseCase.Execute(ctx, gpg_key.SignManifestRequest{
+		KeyID:     cmd.String("key-id"),
+		Files:     cmd.Args().Slice(),
+		Algorithm: gpg_key.ManifestAlgorithm(strings.ToLower(cmd.String("algorithm"))),
+		Output:    cmd.String("output"),
+		Clearsign: cmd.Bool("clearsign"),
+	})
 	if err != nil {
 		return h.formatError(cmd, err)
 	}
@@ -138,7 +196,59 @@ func (h *GpgKeyHandler) Verify(ctx context.Context, cmd *cli.Command) error {
 		return h.formatter.FormatJSON(cmd.Writer, result)
 	}
 
-	return h.formatter.FormatVerifyResult(cmd.Writer, *result)
+	return h.formatter.FormatManifestSigned(cmd.Writer, *result)
+}
+
+func (h *GpgKeyHandler) VerifyManifest(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() != 1 {
+		return h.formatError(cmd, gpg_key.NewValidationError("expected exactly one manifest file", gpg_key.ErrFileNotFound))
+	}
+
+	result, err := h.verifyManifestUseCase.Execute(ctx, cmd.Args().First(),
+		cmd.String("signature"), cmd.String("key-id"), cmd.Bool("refresh"))
+	if err != nil {
+		return h.formatError(cmd, err)
+	}
+
+	if cmd.Bool("json") {
+		err = h.formatter.FormatJSON(cmd.Writer, result)
+	} else {
+		err = h.formatter.FormatManifestVerified(cmd.Writer, *result)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !result.Valid {
+		return errors.New("manifest verification failed")
+	}
+	return nil
+}
+
+func (h *GpgKeyHandler) RefreshKeyring(ctx context.Context, cmd *cli.Command) error {
+	entries, err := h.refreshUseCase.Execute(ctx, cmd.String("key-id"))
+	if err != nil {
+		return h.formatError(cmd, err)
+	}
+
+	if cmd.Bool("json") {
+		return h.formatter.FormatJSON(cmd.Writer, entries)
+	}
+
+	return h.formatter.FormatSuccess(cmd.Writer, fmt.Sprintf("Local keyring updated with %d key(s)", len(entries)))
+}
+
+func (h *GpgKeyHandler) ListKeyring(ctx context.Context, cmd *cli.Command) error {
+	entries, err := h.keyringUseCase.Execute(ctx)
+	if err != nil {
+		return h.formatError(cmd, err)
+	}
+
+	if cmd.Bool("json") {
+		return h.formatter.FormatJSON(cmd.Writer, entries)
+	}
+
+	return h.formatter.FormatKeyring(cmd.Writer, entries)
 }
 
 func (h *GpgKeyHandler) Export(ctx context.Context, cmd *cli.Command) error {
@@ -165,6 +275,81 @@ func (h *GpgKeyHandler) Export(ctx context.Context, cmd *cli.Command) error {
 	return h.formatter.FormatExport(cmd.Writer, publicKey)
 }
 
+func (h *GpgKeyHandler) Import(ctx context.Context, cmd *cli.Command) error {
+	filePath := cmd.String("file")
+	if filePath == "" && cmd.Args().Len() > 0 {
+		filePath = cmd.Args().First()
+	}
+
+	// The key is read from stdin when no file is given, which rules out a
+	// passphrase prompt
+	interactive := true
+	var armored []byte
+	var err error
+	if filePath == "" || filePath == "-" {
+		stat, _ := os.Stdin.Stat()
+		if stat == nil || stat.Mode()&os.ModeCharDevice != 0 {
+			return h.formatError(cmd, gpg_key.NewValidationError("no input provided", gpg_key.ErrNoInputProvided))
+		}
+		armored, err = io.ReadAll(os.Stdin)
+		interactive = false
+	} else {
+		armored, err = os.ReadFile(filePath)
+	}
+	if err != nil {
+		return h.formatError(cmd, gpg_key.NewIOError("failed to read key", err))
+	}
+
+	passphrase := cmd.String("passphrase")
+	if path := cmd.String("passphrase-file"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return h.formatError(cmd, gpg_key.NewIOError("failed to read passphrase file", err))
+		}
+		passphrase = strings.TrimRight(string(data), "\r\n")
+	}
+	if cmd.Bool("json") {
+		interactive = false
+	}
+
+	key, err := h.importUseCase.Execute(ctx, cmd.String("name"), armored, passphrase, interactive)
+	if err != nil {
+		return h.formatError(cmd, err)
+	}
+
+	if cmd.Bool("json") {
+		return h.formatter.FormatJSON(cmd.Writer, key)
+	}
+
+	return h.formatter.FormatKeyImported(cmd.Writer, *key)
+}
+
+func (h *GpgKeyHandler) Show(ctx context.Context, cmd *cli.Command) error {
+	key, err := h.showUseCase.Execute(ctx, cmd.String("key-id"))
+	if err != nil {
+		return h.formatError(cmd, err)
+	}
+
+	if cmd.Bool("json") {
+		return h.formatter.FormatJSON(cmd.Writer, key)
+	}
+
+	return h.formatter.FormatKeyDetail(cmd.Writer, *key)
+}
+
+func (h *GpgKeyHandler) Trust(ctx context.Context, cmd *cli.Command) error {
+	key, err := h.trustUseCase.Execute(ctx, cmd.String("key-id"), cmd.String("level"))
+	if err != nil {
+		return h.formatError(cmd, err)
+	}
+
+	if cmd.Bool("json") {
+		return h.formatter.FormatJSON(cmd.Writer, key)
+	}
+
+	return h.formatter.FormatSuccess(cmd.Writer, fmt.Sprintf("GPG key %s trust level set to %s", key.ID, key.TrustLevel))
+}
+
 func (h *GpgKeyHandler) Revoke(ctx context.Context, cmd *cli.Command) error {
 	keyID := cmd.String("key-id")
 	reason := cmd.String("reason")
@@ -195,6 +380,165 @@ func (h *GpgKeyHandler) Delete(ctx context.Context, cmd *cli.Command) error {
 	return h.formatter.FormatSuccess(cmd.Writer, "GPG key deleted: "+keyID)
 }
 
+// GitSign implements the subset of the gpg command line git uses for
+// gpg.program: "--status-fd=2 -bsau <key>" signs stdin, and
+// "--status-fd=1 --verify <signature> -" verifies stdin. Errors go to stderr
+// only, since stdout carries the signature or the status lines.
+func (h *GpgKeyHandler) GitSign(ctx context.Context, cmd *cli.Command) error {
+	args, err := parseGitSignArgs(cmd.Args().Slice())
+	if err != nil {
+		return err
+	}
+
+	root := cmd.Root()
+	status := root.ErrWriter
+	if args.statusFD == 1 {
+		status = cmd.Writer
+	}
+
+	var result *domain.GitSignResult
+	if args.verify {
+		if len(args.files) == 0 {
+			return errors.New("--verify requires a signature file")
+		}
+		signature, err := os.ReadFile(args.files[0])
+		if err != nil {
+			return fmt.Errorf("failed to read signature: %w", err)
+		}
+		data, err := readFileOrStdin(root.Reader, args.files[1:])
+		if err != nil {
+			return err
+		}
+		result, err = h.gitSignUseCase.Verify(ctx, data, signature)
+		if err != nil {
+			return err
+		}
+	} else {
+		if !args.sign {
+			return errors.New("only signing (-bsau) and --verify are supported")
+		}
+		data, err := readFileOrStdin(root.Reader, args.files)
+		if err != nil {
+			return err
+		}
+		result, err = h.gitSignUseCase.Sign(ctx, args.keyID, data)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(cmd.Writer, result.Signature); err != nil {
+			return err
+		}
+	}
+
+	if args.statusFD != 0 {
+		for _, line := range result.Status {
+			fmt.Fprintf(status, "[GNUPG:] %s\n", line)
+		}
+	}
+	if result.Message != "" {
+		fmt.Fprintf(root.ErrWriter, "envsync: %s\n", result.Message)
+	}
+	if !result.OK {
+		return errors.New("signature verification failed")
+	}
+	return nil
+}
+
+type gitSignArgs struct {
+	sign     bool
+	verify   bool
+	keyID    string
+	statusFD int
+	files    []string
+}
+
+// parseGitSignArgs parses gpg options, including bundled short options such
+// as -bsau. Options that do not change the output, like --keyid-format, are
+// ignored.
+func parseGitSignArgs(argv []string) (*gitSignArgs, error) {
+	args := &gitSignArgs{}
+	for i := 0; i < len(argv); i++ {
+		arg := argv[i]
+		next := func() (string, error) {
+			if i+1 >= len(argv) {
+				return "", fmt.Errorf("option %s requires a value", arg)
+			}
+			i++
+			return argv[i], nil
+		}
+
+		switch {
+		case arg == "--verify":
+			args.verify = true
+		case arg == "--status-fd" || strings.HasPrefix(arg, "--status-fd="):
+			value, found := strings.CutPrefix(arg, "--status-fd=")
+			if !found {
+				var err error
+				if value, err = next(); err != nil {
+					return nil, err
+				}
+			}
+			fd, err := strconv.Atoi(value)
+			if err != nil || fd != 1 && fd != 2 {
+				return nil, fmt.Errorf("unsupported status file descriptor %q", value)
+			}
+			args.statusFD = fd
+		case arg == "--local-user" || strings.HasPrefix(arg, "--local-user="):
+			value, found := strings.CutPrefix(arg, "--local-user=")
+			if !found {
+				var err error
+				if value, err = next(); err != nil {
+					return nil, err
+				}
+			}
+			args.keyID = value
+		case arg == "--detach-sign" || arg == "--sign":
+			args.sign = true
+		case arg == "--":
+			args.files = append(args.files, argv[i+1:]...)
+			return args, nil
+		case arg == "-":
+			args.files = append(args.files, arg)
+		case strings.HasPrefix(arg, "--"):
+		case strings.HasPrefix(arg, "-"):
+		shorts:
+			for j, c := range arg[1:] {
+				switch c {
+				case 'b', 's':
+					args.sign = true
+				case 'a':
+				case 'u':
+					// The key follows in the same argument or the next one
+					if rest := arg[j+2:]; rest != "" {
+						args.keyID = rest
+					} else {
+						value, err := next()
+						if err != nil {
+							return nil, err
+						}
+						args.keyID = value
+					}
+					break shorts
+				default:
+					return nil, fmt.Errorf("unsupported option -%c", c)
+				}
+			}
+		default:
+			args.files = append(args.files, arg)
+		}
+	}
+	return args, nil
+}
+
+// readFileOrStdin reads the single file argument, or stdin when there is none
+// or it is "-".
+func readFileOrStdin(stdin io.Reader, files []string) ([]byte, error) {
+	if len(files) == 0 || files[0] == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(files[0])
+}
+
 func (h *GpgKeyHandler) formatError(cmd *cli.Command, err error) error {
 	if cmd.Bool("json") {
 		return h.formatter.FormatJSONError(cmd.Writer, err)
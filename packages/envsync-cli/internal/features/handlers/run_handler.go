@@ -3,18 +3,32 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	gpg_key "github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/gpg_key"
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/run"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/utils"
 	"github.com/urfave/cli/v3"
 )
 
 type RunHandler struct {
-	redactUseCase       run.RedactUseCase
-	injectEnvUseCase    run.InjectEnvUseCase
-	injectSecretUseCase run.InjectSecretsUseCase
-	appUseCase          run.FetchAppUseCase
-	readConfigUseCase   run.ReadConfigUseCase
+	redactUseCase         run.RedactUseCase
+	injectEnvUseCase      run.InjectEnvUseCase
+	injectSecretUseCase   run.InjectSecretsUseCase
+	appUseCase            run.FetchAppUseCase
+	readConfigUseCase     run.ReadConfigUseCase
+	watchChangesUseCase   run.WatchChangesUseCase
+	envCacheUseCase       run.EnvCacheUseCase
+	fetchEnvUseCase       run.FetchEnvUseCase
+	loadProcessesUseCase  run.LoadProcessesUseCase
+	processesUseCase      run.SuperviseProcessesUseCase
+	interpolateUseCase    run.InterpolateEnvUseCase
+	verifySnapshotUseCase gpg_key.VerifySnapshotUseCase
 }
 
 func NewRunHandler(
@@ -23,32 +37,94 @@ func NewRunHandler(
 	isuc run.InjectSecretsUseCase,
 	auc run.FetchAppUseCase,
 	rcuc run.ReadConfigUseCase,
+	wcuc run.WatchChangesUseCase,
+	ecuc run.EnvCacheUseCase,
+	feuc run.FetchEnvUseCase,
+	lpuc run.LoadProcessesUseCase,
+	spuc run.SuperviseProcessesUseCase,
+	ieuc run.InterpolateEnvUseCase,
+	vsuc gpg_key.VerifySnapshotUseCase,
 ) *RunHandler {
 	return &RunHandler{
-		redactUseCase:       ruc,
-		injectEnvUseCase:    iuc,
-		injectSecretUseCase: isuc,
-		appUseCase:          auc,
-		readConfigUseCase:   rcuc,
+		redactUseCase:         ruc,
+		injectEnvUseCase:      iuc,
+		injectSecretUseCase:   isuc,
+		appUseCase:            auc,
+		readConfigUseCase:     rcuc,
+		watchChangesUseCase:   wcuc,
+		envCacheUseCase:       ecuc,
+		fetchEnvUseCase:       feuc,
+		loadProcessesUseCase:  lpuc,
+		processesUseCase:      spuc,
+		interpolateUseCase:    ieuc,
+		verifySnapshotUseCase: vsuc,
 	}
 }
 
 func (h *RunHandler) Run(ctx context.Context, cmd *cli.Command) error {
-	c := strings.Split(cmd.String("command"), " ")
-
 	configData, err := h.readConfigUseCase.Execute(ctx)
 	if err != nil {
 		return err
 	}
 
-	app, err := h.appUseCase.Execute(ctx, configData.AppID)
-	if err != nil {
-		return err
+	if cmd.Bool("require-signature") {
+		if cmd.Bool("watch") {
+			return errors.New("require-signature flag cannot be combined with --watch")
+		}
+		ctx = requireSignature(ctx, cmd)
 	}
+	ctx = context.WithValue(ctx, "strictReferences", cmd.Bool("strict-references"))
 
-	envs, err := h.injectEnvUseCase.Execute(ctx)
-	if err != nil {
-		return err
+	if cmd.IsSet("procfile") {
+		if cmd.IsSet("command") {
+			return errors.New("command flag cannot be combined with --procfile")
+		}
+		return h.runProcesses(ctx, cmd, configData)
+	}
+	if !cmd.IsSet("command") {
+		if len(configData.Processes) == 0 {
+			return errors.New("command flag is required unless --procfile or a [processes] table in envsyncrc.toml is used")
+		}
+		return h.runProcesses(ctx, cmd, configData)
+	}
+
+	c := strings.Split(cmd.String("command"), " ")
+
+	offline := cmd.Bool("offline")
+	if offline && cmd.Bool("watch") {
+		return errors.New("watch flag cannot be combined with --offline")
+	}
+
+	var app *domain.Application
+	var fetchErr error
+	if !offline {
+		app, fetchErr = h.appUseCase.Execute(ctx, configData.AppID)
+		if fetchErr != nil && (!run.IsBackendUnavailable(fetchErr) || cmd.Bool("watch")) {
+			return fetchErr
+		}
+	}
+
+	// Offline, or the API is down: run with the last cached environment
+	if app == nil {
+		envs, err := h.restoreEnvironment(ctx, configData, fetchErr)
+		if err != nil {
+			return err
+		}
+
+		_ = h.redactUseCase.Execute(ctx, c, envs)
+
+		return nil
+	}
+
+	var reloadSignal os.Signal
+	if cmd.IsSet("reload-signal") {
+		if !cmd.Bool("watch") {
+			return errors.New("reload-signal flag requires --watch")
+		}
+		reloadSignal, err = utils.ParseSignal(cmd.String("reload-signal"))
+		if err != nil {
+			return err
+		}
 	}
 
 	if app.EnableSecrets {
@@ -60,18 +136,335 @@ func (h *RunHandler) Run(ctx context.Context, cmd *cli.Command) error {
 		ctx = context.WithValue(ctx, "privateKeyPath", cmd.String("private-key"))
 		ctx = context.WithValue(ctx, "appID", configData.AppID)
 		ctx = context.WithValue(ctx, "envTypeID", configData.EnvTypeID)
+	}
 
-		secrets, err := h.injectSecretUseCase.Execute(ctx)
+	if !cmd.Bool("watch") {
+		envs, err := h.loadEnvironment(ctx, configData, app.EnableSecrets)
 		if err != nil {
 			return err
 		}
 
-		for key, value := range secrets {
-			envs[key] = value
+		_ = h.redactUseCase.Execute(ctx, c, envs)
+
+		return nil
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Start watching before the first fetch so no change can slip in between
+	changes, err := h.watchChangesUseCase.Execute(watchCtx, run.WatchOptions{
+		AppID:          configData.AppID,
+		EnvTypeID:      configData.EnvTypeID,
+		IncludeSecrets: app.EnableSecrets,
+		Interval:       cmd.Duration("watch-interval"),
+		Debounce:       cmd.Duration("debounce"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch remote environment: %w", err)
+	}
+
+	envs, err := h.loadEnvironment(ctx, configData, app.EnableSecrets)
+	if err != nil {
+		return err
+	}
+
+	reloads := make(chan run.Reload)
+	go h.forwardChanges(watchCtx, changes, envs, configData, app.EnableSecrets, reloadSignal, reloads)
+
+	_ = h.redactUseCase.Supervise(ctx, c, envs, reloads)
+
+	return nil
+}
+
+// runProcesses fetches the environment once per environment type and runs all
+// processes of the Procfile or the [processes] table with it
+func (h *RunHandler) runProcesses(ctx context.Context, cmd *cli.Command, configData *domain.SyncConfig) error {
+	if cmd.Bool("watch") {
+		return errors.New("watch flag is not supported when running multiple processes")
+	}
+
+	processes, err := h.loadProcessesUseCase.Execute(ctx, cmd.String("procfile"), configData.Processes)
+	if err != nil {
+		return err
+	}
+
+	// Captured before any injection so processes only see their own target
+	baseEnv := os.Environ()
+
+	var app *domain.Application
+	var fetchErr error
+	if !cmd.Bool("offline") {
+		app, fetchErr = h.appUseCase.Execute(ctx, configData.AppID)
+		if fetchErr != nil && !run.IsBackendUnavailable(fetchErr) {
+			return fetchErr
+		}
+	}
+
+	includeSecrets := app != nil && app.EnableSecrets
+	if includeSecrets {
+		if !cmd.IsSet("private-key") && !app.IsManagedSecret {
+			return errors.New("private-key flag is required when secrets are enabled")
+		}
+
+		ctx = context.WithValue(ctx, "managedSecret", app.IsManagedSecret)
+		ctx = context.WithValue(ctx, "privateKeyPath", cmd.String("private-key"))
+		ctx = context.WithValue(ctx, "appID", configData.AppID)
+	}
+
+	targets := make(map[string]map[string]string)
+	specs := make([]run.ProcessSpec, 0, len(processes))
+	for _, p := range processes {
+		target := *configData
+		if p.EnvTypeID != "" {
+			target.EnvTypeID = p.EnvTypeID
+		}
+
+		envs, ok := targets[target.EnvTypeID]
+		if !ok {
+			if app == nil {
+				envs, err = h.restoreEnvironment(ctx, &target, fetchErr)
+			} else {
+				envs, err = h.loadTargetEnvironment(ctx, &target, includeSecrets)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to load environment for '%s': %w", p.Name, err)
+			}
+			targets[target.EnvTypeID] = envs
 		}
+
+		specs = append(specs, run.ProcessSpec{
+			Name:        p.Name,
+			Command:     p.Command,
+			Dir:         p.Dir,
+			Env:         processEnv(baseEnv, envs, p.Env),
+			Redact:      envs,
+			Restart:     p.Restart,
+			MaxRestarts: p.MaxRestarts,
+		})
 	}
 
-	_ = h.redactUseCase.Execute(ctx, c, envs)
+	_ = h.processesUseCase.Execute(ctx, specs)
 
 	return nil
 }
+
+// processEnv layers the fetched environment and the overrides of a process on
+// top of the environment envsync was started with
+func processEnv(base []string, envs, overrides map[string]string) []string {
+	merged := make(map[string]string, len(base)+len(envs)+len(overrides))
+	for _, kv := range base {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			merged[key] = value
+		}
+	}
+	for key, value := range envs {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+
+	env := make([]string, 0, len(merged))
+	for key, value := range merged {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+
+	return env
+}
+
+// loadEnvironment fetches the variables, and secrets when enabled, and injects
+// them into the process environment. Successful fetches refresh the offline
+// cache, which is used instead when the API cannot be reached.
+func (h *RunHandler) loadEnvironment(ctx context.Context, configData *domain.SyncConfig, includeSecrets bool) (map[string]string, error) {
+	variables, err := h.injectEnvUseCase.Execute(ctx)
+	return h.completeEnvironment(ctx, configData, includeSecrets, variables, err)
+}
+
+// loadTargetEnvironment is loadEnvironment for the environment type in
+// configData, which may differ from the project's
+func (h *RunHandler) loadTargetEnvironment(ctx context.Context, configData *domain.SyncConfig, includeSecrets bool) (map[string]string, error) {
+	ctx = context.WithValue(ctx, "envTypeID", configData.EnvTypeID)
+
+	variables, err := h.fetchEnvUseCase.Execute(ctx, configData.AppID, configData.EnvTypeID)
+	return h.completeEnvironment(ctx, configData, includeSecrets, variables, err)
+}
+
+// completeEnvironment adds the secrets to the fetched variables and updates
+// the offline cache, falling back to it if either fetch hit an unreachable API
+func (h *RunHandler) completeEnvironment(ctx context.Context, configData *domain.SyncConfig, includeSecrets bool, variables map[string]string, err error) (map[string]string, error) {
+	if err != nil {
+		if run.IsBackendUnavailable(err) {
+			return h.restoreEnvironment(ctx, configData, err)
+		}
+		return nil, err
+	}
+
+	var secrets map[string]string
+	if includeSecrets {
+		secrets, err = h.injectSecretUseCase.Execute(ctx)
+		if err != nil {
+			if run.IsBackendUnavailable(err) {
+				return h.restoreEnvironment(ctx, configData, err)
+			}
+			return nil, err
+		}
+	}
+
+	// The cache keeps the raw values so references are expanded the same way
+	// whether the environment was fetched or restored
+	if err := h.envCacheUseCase.Save(ctx, configData.AppID, configData.EnvTypeID, variables, secrets); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update offline cache: %v\n", err)
+	}
+
+	return h.interpolateEnvironment(ctx, configData, variables, secrets)
+}
+
+// interpolateEnvironment expands references between the variables and
+// secrets and injects the expanded values into the process environment
+func (h *RunHandler) interpolateEnvironment(ctx context.Context, configData *domain.SyncConfig, variables, secrets map[string]string) (map[string]string, error) {
+	if err := verifySnapshot(ctx, h.verifySnapshotUseCase, configData, variables); err != nil {
+		return nil, err
+	}
+
+	strict, _ := ctx.Value("strictReferences").(bool)
+	variables, secrets, unresolved, err := h.interpolateUseCase.Execute(ctx, configData.AppID, variables, secrets, strict)
+	if err != nil {
+		return nil, fmt.Errorf("failed to interpolate environment: %w", err)
+	}
+	warnUnresolved(unresolved)
+
+	envs := make(map[string]string, len(variables)+len(secrets))
+	for key, value := range variables {
+		envs[key] = value
+	}
+	for key, value := range secrets {
+		envs[key] = value
+	}
+
+	for key, value := range envs {
+		if err := os.Setenv(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return envs, nil
+}
+
+// warnUnresolved reports the references that were kept verbatim because they
+// could not be resolved.
+func warnUnresolved(unresolved []string) {
+	if len(unresolved) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: kept unresolved references verbatim (use --strict-references to fail instead):\n")
+	for _, reference := range unresolved {
+		fmt.Fprintf(os.Stderr, "  %s\n", reference)
+	}
+}
+
+// requireSignature makes verifySnapshot check the variables against the
+// snapshot signature and the pinned signer of the command flags
+func requireSignature(ctx context.Context, cmd *cli.Command) context.Context {
+	ctx = context.WithValue(ctx, "requireSignature", true)
+	ctx = context.WithValue(ctx, "snapshotSignature", cmd.String("signature"))
+	ctx = context.WithValue(ctx, "signerFingerprint", cmd.String("signer-fingerprint"))
+	return ctx
+}
+
+// verifySnapshot refuses variables that do not match the snapshot signed by
+// 'envsync pull --sign' when a signature is required. The variables are
+// checked as fetched, before references are expanded.
+func verifySnapshot(ctx context.Context, uc gpg_key.VerifySnapshotUseCase, configData *domain.SyncConfig, variables map[string]string) error {
+	if required, _ := ctx.Value("requireSignature").(bool); !required {
+		return nil
+	}
+	signaturePath, _ := ctx.Value("snapshotSignature").(string)
+	fingerprint, _ := ctx.Value("signerFingerprint").(string)
+
+	_, err := uc.Execute(ctx, domain.EnvSnapshot{
+		AppID:     configData.AppID,
+		EnvTypeID: configData.EnvTypeID,
+		Variables: variables,
+	}, signaturePath, fingerprint)
+	return err
+}
+
+// restoreEnvironment injects the cached environment. cause is the error that
+// prevented a fresh fetch, or nil when running with --offline.
+func (h *RunHandler) restoreEnvironment(ctx context.Context, configData *domain.SyncConfig, cause error) (map[string]string, error) {
+	entry, err := h.envCacheUseCase.Restore(ctx, configData.AppID, configData.EnvTypeID)
+	if err != nil {
+		if cause != nil {
+			return nil, fmt.Errorf("%w (offline cache not usable: %v)", cause, err)
+		}
+		return nil, err
+	}
+
+	fetchedAt := entry.OldestFetch()
+	age := time.Since(fetchedAt).Round(time.Second)
+	if cause != nil {
+		fmt.Fprintf(os.Stderr, "Warning: EnvSync API is unreachable (%v)\n", cause)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: using cached environment from %s (%s old)\n",
+		fetchedAt.Local().Format(time.RFC1123), age)
+
+	return h.interpolateEnvironment(ctx, configData, entry.Variables, entry.Secrets)
+}
+
+// forwardChanges re-fetches the environment whenever the watcher reports a
+// remote change and hands it to the supervised command.
+func (h *RunHandler) forwardChanges(
+	ctx context.Context,
+	changes <-chan struct{},
+	current map[string]string,
+	configData *domain.SyncConfig,
+	includeSecrets bool,
+	reloadSignal os.Signal,
+	reloads chan<- run.Reload,
+) {
+	for range changes {
+		envs, err := h.loadEnvironment(ctx, configData, includeSecrets)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Error reloading environment: %v\n", err)
+			}
+			continue
+		}
+
+		change := domain.DiffEnv(current, envs)
+		if !change.HasChanges() {
+			continue
+		}
+
+		for _, key := range change.Removed {
+			os.Unsetenv(key)
+		}
+		printEnvChange(change)
+		current = envs
+
+		select {
+		case reloads <- run.Reload{Env: envs, Signal: reloadSignal}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// printEnvChange writes the changed keys to stderr so they are not mixed into
+// the redacted output of the command. Values are never printed.
+func printEnvChange(change domain.EnvChange) {
+	fmt.Fprintf(os.Stderr, "\nRemote environment changed: %d added, %d updated, %d removed\n",
+		len(change.Added), len(change.Updated), len(change.Removed))
+
+	for _, key := range change.Added {
+		fmt.Fprintf(os.Stderr, "  + %s\n", key)
+	}
+	for _, key := range change.Updated {
+		fmt.Fprintf(os.Stderr, "  ~ %s\n", key)
+	}
+	for _, key := range change.Removed {
+		fmt.Fprintf(os.Stderr, "  - %s\n", key)
+	}
+}
@@ -2,40 +2,71 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
+	gpg_key "github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/gpg_key"
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/features/usecases/sync"
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/presentation/formatters"
 )
 
 type SyncHandler struct {
-	pullUseCase sync.PullUseCase
-	pushUseCase sync.PushUseCase
-	formatter   *formatters.SyncFormatter
+	pullUseCase         sync.PullUseCase
+	pushUseCase         sync.PushUseCase
+	signSnapshotUseCase gpg_key.SignSnapshotUseCase
+	formatter           *formatters.SyncFormatter
 }
 
 func NewSyncHandler(
 	pullUseCase sync.PullUseCase,
 	pushUseCase sync.PushUseCase,
+	signSnapshotUseCase gpg_key.SignSnapshotUseCase,
 	formatter *formatters.SyncFormatter,
 ) *SyncHandler {
 	return &SyncHandler{
-		pullUseCase: pullUseCase,
-		pushUseCase: pushUseCase,
-		formatter:   formatter,
+		pullUseCase:         pullUseCase,
+		pushUseCase:         pushUseCase,
+		signSnapshotUseCase: signSnapshotUseCase,
+		formatter:           formatter,
 	}
 }
 
 func (h *SyncHandler) Pull(ctx context.Context, cmd *cli.Command) error {
 	config := cmd.String("config")
 
-	diff, err := h.pullUseCase.Execute(ctx, config)
+	if cmd.Bool("sign") && cmd.Bool("offline") {
+		return errors.New("sign flag cannot be combined with --offline")
+	}
+
+	diff, err := h.pullUseCase.Execute(ctx, config, cmd.Bool("offline"))
 	if err != nil {
 		return err
 	}
 
+	if diff.CachedAt != nil {
+		fmt.Printf("Warning: using cached variables from %s (%s old)\n",
+			diff.CachedAt.Local().Format(time.RFC1123), time.Since(*diff.CachedAt).Round(time.Second))
+	}
+
+	if cmd.Bool("sign") {
+		// A signature over cached variables would vouch for data that may
+		// be stale, so only freshly fetched snapshots are signed
+		if diff.CachedAt != nil {
+			return errors.New("refusing to sign cached variables while the EnvSync API is unreachable")
+		}
+
+		signaturePath := cmd.String("signature")
+		key, err := h.signSnapshotUseCase.Execute(ctx, cmd.String("key-id"), diff.Snapshot, signaturePath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Signed environment snapshot with %s (%s), signature written to %s\n",
+			key.Name, key.Fingerprint, signaturePath)
+	}
+
 	if len(diff.Warnings) > 0 {
 		// Handle warnings, e.g., print or log them
 		for _, warning := range diff.Warnings {
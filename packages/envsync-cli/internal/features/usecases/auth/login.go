@@ -2,8 +2,9 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"os"
 
 	"github.com/pkg/browser"
 	"github.com/savioxavier/termlink"
@@ -15,13 +16,16 @@ import (
 )
 
 type loginUseCase struct {
-	authService services.AuthService
+	authService  services.AuthService
+	cacheService services.EnvCacheService
 }
 
 func NewLoginUseCase() LoginUseCase {
 	service := services.NewAuthService()
+	cacheService := services.NewEnvCacheService()
 	return &loginUseCase{
-		authService: service,
+		authService:  service,
+		cacheService: cacheService,
 	}
 }
 
@@ -59,7 +63,11 @@ func (uc *loginUseCase) Execute(ctx context.Context) (*LoginResponse, error) {
 		return nil, uc.handlePollingError(err)
 	}
 
-	// Step 3: Save the token
+	// Step 3: Keep the offline cache readable with the new token and save it
+	if err := uc.cacheService.RewrapKey(token.Token); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: the offline cache will be refilled on the next fetch: %v\n", err)
+	}
+
 	if err := uc.authService.SaveToken(token); err != nil {
 		return nil, NewServiceError("failed to save authentication token", err)
 	}
@@ -84,31 +92,21 @@ func (uc *loginUseCase) checkCurrentLoginStatus(ctx context.Context) (*domain.Us
 
 // handlePollingError processes errors that occur during the polling phase of authentication
 func (uc *loginUseCase) handlePollingError(err error) error {
-	errMsg := err.Error()
-
-	// Check for specific error types and provide better error messages
-	if strings.Contains(errMsg, "timeout") {
+	switch {
+	case errors.Is(err, services.ErrAuthorizationTimeout), errors.Is(err, context.DeadlineExceeded):
 		return NewTimeoutError("authentication timed out - please try again", err)
-	}
-
-	if strings.Contains(errMsg, "cancelled") || strings.Contains(errMsg, "canceled") {
+	case errors.Is(err, context.Canceled):
 		return NewCancelledError("authentication was cancelled", err)
-	}
-
-	if strings.Contains(errMsg, "device_code") {
-		return NewTokenInvalidError("device code expired or invalid - please try again", err)
-	}
-
-	if strings.Contains(errMsg, "network") || strings.Contains(errMsg, "connection") {
+	case services.IsLoginDenied(err):
+		return NewTokenInvalidError("login was denied or the device code expired - please try again", err)
+	case services.IsNetworkError(err):
 		return NewNetworkError("network error during authentication", err)
-	}
-
-	if strings.Contains(errMsg, "server") || strings.Contains(errMsg, "5") {
-		return NewServiceError("authentication service error", err)
+	case services.IsBackendUnavailable(err):
+		return NewServiceError("authentication service error: "+services.DescribeError(err), err)
 	}
 
 	// Default to login failed error
-	return NewLoginFailedError("authentication failed", err)
+	return NewLoginFailedError("authentication failed: "+services.DescribeError(err), err)
 }
 
 // displayLoginInstructions shows the user what they need to do to authenticate
@@ -0,0 +1,214 @@
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/utils"
+)
+
+// BundleFormat is a format 'envsync cert bundle' packages a certificate in
+type BundleFormat string
+
+const (
+	// BundlePKCS12 is a password protected PKCS#12 keystore with the key,
+	// the certificate and its chain, readable by Java as a PKCS12 keystore
+	BundlePKCS12 BundleFormat = "pkcs12"
+	// BundleFullChain is the certificate followed by its intermediates
+	BundleFullChain BundleFormat = "fullchain"
+	// BundleCombined is the full chain followed by the key, as used by
+	// HAProxy and nginx
+	BundleCombined BundleFormat = "combined"
+)
+
+var BundleFormats = []BundleFormat{BundlePKCS12, BundleFullChain, BundleCombined}
+
+var (
+	ErrUnsupportedBundleFormat = errors.New("unsupported bundle format")
+	ErrKeyMismatch             = errors.New("private key does not match the certificate")
+	ErrPasswordRequired        = errors.New("password is required")
+)
+
+type BundleRequest struct {
+	Format BundleFormat
+	Output string // If empty, the bundle is only returned
+	// CertPEM is the certificate, optionally followed by its chain
+	CertPEM string
+	KeyPEM  string
+	// ChainPEM holds the CA certificates; without it and without a chain in
+	// CertPEM, the organization and root CAs are fetched
+	ChainPEM string
+	Password string
+	// Legacy encrypts PKCS#12 files with the algorithms of older Java and
+	// OpenSSL versions
+	Legacy bool
+}
+
+type BundleResponse struct {
+	Format BundleFormat
+	Data   []byte
+	Path   string
+	// Chain is the number of CA certificates in the bundle
+	Chain int
+}
+
+type bundleCertUseCase struct {
+	service services.CertificateService
+}
+
+func NewBundleCertUseCase() BundleCertUseCase {
+	service := services.NewCertificateService()
+	return &bundleCertUseCase{service: service}
+}
+
+func (uc *bundleCertUseCase) Execute(ctx context.Context, req BundleRequest) (*BundleResponse, error) {
+	if !slices.Contains(BundleFormats, req.Format) {
+		return nil, NewValidationError("format must be one of "+bundleFormatList(), ErrUnsupportedBundleFormat)
+	}
+	if req.Format == BundlePKCS12 && req.Password == "" {
+		return nil, NewValidationError("a password is required for PKCS#12 bundles", ErrPasswordRequired)
+	}
+
+	certs, err := parseCertificates(req.CertPEM)
+	if err != nil || len(certs) == 0 {
+		return nil, NewValidationError("certificate must be a PEM-encoded X.509 certificate", ErrInvalidCertificate)
+	}
+	leaf := certs[0]
+
+	var key crypto.Signer
+	if req.Format != BundleFullChain {
+		if key, err = parsePrivateKey(req.KeyPEM); err != nil {
+			return nil, NewValidationError("key must be a PEM-encoded private key", err)
+		}
+		public, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+		if !ok || !public.Equal(leaf.PublicKey) {
+			return nil, NewValidationError("private key does not match the certificate", ErrKeyMismatch)
+		}
+	}
+
+	chain, err := uc.chain(ctx, req.ChainPEM, certs[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	perm := os.FileMode(0o600)
+	switch req.Format {
+	case BundlePKCS12:
+		encoder := pkcs12.Modern
+		if req.Legacy {
+			encoder = pkcs12.LegacyDES
+		}
+		if data, err = encoder.Encode(key, leaf, chain, req.Password); err != nil {
+			return nil, NewServiceError("failed to encode PKCS#12 bundle", err)
+		}
+	case BundleFullChain:
+		// A certificate chain holds no secrets
+		data, perm = encodeChain(leaf, chain), 0o644
+	case BundleCombined:
+		keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, NewServiceError("failed to encode private key", err)
+		}
+		data = append(encodeChain(leaf, chain), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})...)
+	}
+
+	res := &BundleResponse{Format: req.Format, Data: data, Chain: len(chain)}
+	if req.Output != "" {
+		if err := utils.WriteFileAtomic(req.Output, data, perm); err != nil {
+			return nil, NewIOError("failed to write bundle", err)
+		}
+		res.Path = req.Output
+	}
+	return res, nil
+}
+
+// chain returns the CA certificates of the bundle: the given chain, the one
+// following the certificate, or the organization and root CAs.
+func (uc *bundleCertUseCase) chain(ctx context.Context, chainPEM string, bundled []*x509.Certificate) ([]*x509.Certificate, error) {
+	if chainPEM != "" {
+		chain, err := parseCertificates(chainPEM)
+		if err != nil {
+			return nil, NewValidationError("chain must hold PEM-encoded X.509 certificates", ErrInvalidCertificate)
+		}
+		return chain, nil
+	}
+	if len(bundled) > 0 {
+		return bundled, nil
+	}
+
+	ca, err := uc.service.GetCA(ctx)
+	if err != nil {
+		return nil, NewServiceError("failed to get organization CA", err)
+	}
+	root, err := uc.service.GetRootCA(ctx)
+	if err != nil {
+		return nil, NewServiceError("failed to get root CA", err)
+	}
+	chain, err := parseCertificates(ca.CertPEM + "\n" + root)
+	if err != nil {
+		return nil, NewServiceError("failed to parse CA certificates", err)
+	}
+	return chain, nil
+}
+
+// encodeChain encodes the certificate and its intermediates as PEM. Root
+// certificates are left out, as TLS peers must already trust them.
+func encodeChain(leaf *x509.Certificate, chain []*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})
+	for _, cert := range chain {
+		if bytes.Equal(cert.RawSubject, cert.RawIssuer) && cert.CheckSignatureFrom(cert) == nil {
+			continue
+		}
+		pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	return buf.Bytes()
+}
+
+// parsePrivateKey parses a PKCS#8, PKCS#1 or SEC 1 PEM-encoded private key.
+func parsePrivateKey(keyPEM string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+func bundleFormatList() string {
+	names := make([]string, len(BundleFormats))
+	for i, format := range BundleFormats {
+		names[i] = string(format)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,138 @@
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func encodeTestKey(t *testing.T, ca *testCA) string {
+	t.Helper()
+	der, err := x509.MarshalECPrivateKey(ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+}
+
+func TestBundleCert(t *testing.T) {
+	root, org, member := newTestPKI(t, testNow.Add(365*24*time.Hour))
+	dir := t.TempDir()
+	uc := NewBundleCertUseCase()
+
+	t.Run("pkcs12", func(t *testing.T) {
+		output := filepath.Join(dir, "member.p12")
+		res, err := uc.Execute(context.Background(), BundleRequest{
+			Format:   BundlePKCS12,
+			Output:   output,
+			CertPEM:  member.pem,
+			KeyPEM:   encodeTestKey(t, member),
+			ChainPEM: org.pem + root.pem,
+			Password: "changeit",
+		})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if res.Chain != 2 {
+			t.Errorf("Chain = %d, want 2", res.Chain)
+		}
+
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, cert, chain, err := pkcs12.DecodeChain(data, "changeit")
+		if err != nil {
+			t.Fatalf("DecodeChain() error = %v", err)
+		}
+		if !cert.Equal(member.cert) || len(chain) != 2 || !member.key.Equal(key) {
+			t.Error("PKCS#12 bundle does not hold the key, certificate and chain")
+		}
+		if _, _, _, err := pkcs12.DecodeChain(data, "wrong"); err == nil {
+			t.Error("PKCS#12 bundle decoded with the wrong password")
+		}
+		assertPerm(t, output, 0o600)
+	})
+
+	t.Run("fullchain", func(t *testing.T) {
+		output := filepath.Join(dir, "fullchain.pem")
+		if _, err := uc.Execute(context.Background(), BundleRequest{
+			Format:   BundleFullChain,
+			Output:   output,
+			CertPEM:  member.pem,
+			ChainPEM: org.pem + root.pem,
+		}); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The root is left out
+		if string(data) != member.pem+org.pem {
+			t.Errorf("full chain = %q, want the certificate and the organization CA", data)
+		}
+		assertPerm(t, output, 0o644)
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		res, err := uc.Execute(context.Background(), BundleRequest{
+			Format:  BundleCombined,
+			CertPEM: member.pem + org.pem,
+			KeyPEM:  encodeTestKey(t, member),
+		})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !strings.HasPrefix(string(res.Data), member.pem+org.pem) {
+			t.Error("combined bundle does not start with the full chain")
+		}
+		if _, err := parsePrivateKey(strings.TrimPrefix(string(res.Data), member.pem+org.pem)); err != nil {
+			t.Errorf("combined bundle does not end with the key: %v", err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := map[string]struct {
+			req  BundleRequest
+			want error
+		}{
+			"unknown format":   {BundleRequest{Format: "jks"}, ErrUnsupportedBundleFormat},
+			"missing password": {BundleRequest{Format: BundlePKCS12, CertPEM: member.pem}, ErrPasswordRequired},
+			"invalid cert":     {BundleRequest{Format: BundleFullChain, CertPEM: "junk"}, ErrInvalidCertificate},
+			"key mismatch": {BundleRequest{
+				Format:  BundleCombined,
+				CertPEM: member.pem,
+				KeyPEM:  encodeTestKey(t, org),
+			}, ErrKeyMismatch},
+		}
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := uc.Execute(context.Background(), tt.req)
+				if !errors.Is(err, tt.want) {
+					t.Errorf("Execute() error = %v, want %v", err, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func assertPerm(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != want {
+		t.Errorf("%s permissions = %v, want %v", filepath.Base(path), info.Mode().Perm(), want)
+	}
+}
@@ -9,6 +9,7 @@ var (
 	ErrSerialRequired     = errors.New("certificate serial number is required")
 	ErrCANotInitialized   = errors.New("organization CA not initialized")
 	ErrCertNotFound       = errors.New("certificate not found")
+	ErrInvalidKeyType     = errors.New("unsupported key type")
 )
 
 type CertError struct {
@@ -0,0 +1,42 @@
+package certificate
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+type expiringCertsUseCase struct {
+	service services.CertificateService
+}
+
+func NewExpiringCertsUseCase() ExpiringCertsUseCase {
+	service := services.NewCertificateService()
+	return &expiringCertsUseCase{service: service}
+}
+
+// Execute returns the active certificates that expire within the given
+// duration, including those already expired, soonest first.
+func (uc *expiringCertsUseCase) Execute(ctx context.Context, within time.Duration) ([]domain.Certificate, error) {
+	certs, err := uc.service.ListCerts(ctx)
+	if err != nil {
+		return nil, NewServiceError("failed to list certificates", err)
+	}
+
+	deadline := time.Now().Add(within)
+	expiring := []domain.Certificate{}
+	for _, cert := range certs {
+		if cert.Status != "active" || cert.NotAfter == nil || cert.NotAfter.After(deadline) {
+			continue
+		}
+		expiring = append(expiring, cert)
+	}
+
+	sort.Slice(expiring, func(i, j int) bool {
+		return expiring[i].NotAfter.Before(*expiring[j].NotAfter)
+	})
+	return expiring, nil
+}
@@ -2,6 +2,8 @@ package certificate
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository/responses"
@@ -19,6 +21,10 @@ type IssueCertUseCase interface {
 	Execute(ctx context.Context, email, role, description string, metadata map[string]string) (*domain.Certificate, error)
 }
 
+type IssueCertFromCSRUseCase interface {
+	Execute(ctx context.Context, email, role, description, keyType string, metadata map[string]string) (*domain.Certificate, error)
+}
+
 type ListCertsUseCase interface {
 	Execute(ctx context.Context) ([]domain.Certificate, error)
 }
@@ -38,3 +44,19 @@ type GetCRLUseCase interface {
 type GetRootCAUseCase interface {
 	Execute(ctx context.Context) (string, error)
 }
+
+type VerifyCertUseCase interface {
+	Execute(ctx context.Context, certPEM string, opts VerifyCertOptions) (*domain.CertVerification, error)
+}
+
+type ExpiringCertsUseCase interface {
+	Execute(ctx context.Context, within time.Duration) ([]domain.Certificate, error)
+}
+
+type RenewAgentUseCase interface {
+	Execute(ctx context.Context, configPath string, once bool, out io.Writer) error
+}
+
+type BundleCertUseCase interface {
+	Execute(ctx context.Context, req BundleRequest) (*BundleResponse, error)
+}
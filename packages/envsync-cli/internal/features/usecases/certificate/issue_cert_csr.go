@@ -0,0 +1,90 @@
+package certificate
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository/requests"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/utils"
+)
+
+// ErrCSRUnsupported is returned when the server cannot issue certificates
+// from a CSR, because its KMS does not support it yet.
+var ErrCSRUnsupported = errors.New("the server does not support issuing certificates from a CSR")
+
+type issueCertFromCSRUseCase struct {
+	service services.CertificateService
+}
+
+func NewIssueCertFromCSRUseCase() IssueCertFromCSRUseCase {
+	service := services.NewCertificateService()
+	return &issueCertFromCSRUseCase{service: service}
+}
+
+// Execute generates the key pair locally and only sends a certificate signing
+// request to the server, so the private key never leaves the machine. The
+// returned certificate carries the local key in KeyPEM.
+func (uc *issueCertFromCSRUseCase) Execute(ctx context.Context, email, role, description, keyType string, metadata map[string]string) (*domain.Certificate, error) {
+	if email == "" {
+		return nil, NewValidationError("member email is required", ErrEmailRequired)
+	}
+	if role == "" {
+		return nil, NewValidationError("role is required", ErrRoleRequired)
+	}
+	return issueFromCSR(ctx, uc.service, email, role, description, keyType, metadata)
+}
+
+// issueFromCSR generates a key pair of keyType and has the server issue a
+// member certificate for it.
+func issueFromCSR(ctx context.Context, service services.CertificateService, email, role, description, keyType string, metadata map[string]string) (*domain.Certificate, error) {
+	if !slices.Contains(utils.KeyTypes, keyType) {
+		return nil, NewValidationError("key type must be one of "+strings.Join(utils.KeyTypes, ", "), ErrInvalidKeyType)
+	}
+
+	key, err := utils.GenerateSigningKey(keyType)
+	if err != nil {
+		return nil, NewServiceError("failed to generate private key", err)
+	}
+	csrPEM, err := utils.CreateCSR(key, email, role)
+	if err != nil {
+		return nil, NewServiceError("failed to create certificate request", err)
+	}
+	keyPEM, err := utils.EncodePrivateKey(key)
+	if err != nil {
+		return nil, NewServiceError("failed to encode private key", err)
+	}
+
+	req := requests.IssueMemberCertFromCSRRequest{
+		CSRPEM:      csrPEM,
+		MemberEmail: email,
+		Role:        role,
+		Description: description,
+		Metadata:    metadata,
+	}
+
+	cert, err := service.IssueMemberCertFromCSR(ctx, req)
+	if services.IsNotImplemented(err) {
+		return nil, NewServiceError(ErrCSRUnsupported.Error(), ErrCSRUnsupported)
+	}
+	if err != nil {
+		return nil, NewServiceError("failed to issue member certificate", err)
+	}
+
+	// The certificate is only of use if it certifies the key generated here
+	certs, err := parseCertificates(cert.CertPEM)
+	if err != nil || len(certs) == 0 {
+		return nil, NewServiceError("server returned an invalid certificate", ErrInvalidCertificate)
+	}
+	public, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !public.Equal(certs[0].PublicKey) {
+		return nil, NewServiceError("server returned a certificate for another key", ErrKeyMismatch)
+	}
+
+	cert.KeyPEM = strings.TrimSpace(keyPEM)
+	return &cert, nil
+}
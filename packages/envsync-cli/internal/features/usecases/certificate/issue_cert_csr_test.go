@@ -0,0 +1,45 @@
+package certificate
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository/requests"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/envsynctest"
+)
+
+// fixedCertService issues the same certificate for every CSR
+type fixedCertService struct {
+	services.CertificateService
+	cert domain.Certificate
+}
+
+func (s *fixedCertService) IssueMemberCertFromCSR(ctx context.Context, req requests.IssueMemberCertFromCSRRequest) (domain.Certificate, error) {
+	return s.cert, nil
+}
+
+func TestIssueFromCSRRejectsCertificateForAnotherKey(t *testing.T) {
+	other, err := NewIssueCertFromCSRUseCase().Execute(context.Background(), "other@example.com", "dev", "", "ecdsa-p256", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = issueFromCSR(context.Background(), &fixedCertService{cert: *other}, "csr@example.com", "dev", "", "ecdsa-p256", nil)
+	if !errors.Is(err, ErrKeyMismatch) {
+		t.Errorf("issueFromCSR() error = %v, want %v", err, ErrKeyMismatch)
+	}
+}
+
+func TestIssueFromCSRUnsupported(t *testing.T) {
+	server.Inject(envsynctest.Fault{Path: "/api/certificate/issue/csr", StatusCode: http.StatusNotImplemented})
+	defer server.ClearFaults()
+
+	_, err := NewIssueCertFromCSRUseCase().Execute(context.Background(), "csr@example.com", "dev", "", "ecdsa-p256", nil)
+	if !errors.Is(err, ErrCSRUnsupported) {
+		t.Errorf("Execute() error = %v, want %v", err, ErrCSRUnsupported)
+	}
+}
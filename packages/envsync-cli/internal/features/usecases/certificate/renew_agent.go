@@ -0,0 +1,256 @@
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/utils"
+)
+
+const (
+	// DefaultRenewCheckInterval is used when check_interval is not configured
+	DefaultRenewCheckInterval = time.Hour
+	// DefaultRenewBefore is used when renew_before is not configured
+	DefaultRenewBefore = 30 * 24 * time.Hour
+)
+
+var ErrInvalidRenewConfig = errors.New("invalid renew configuration")
+
+// renewTarget is a validated RenewTarget
+type renewTarget struct {
+	domain.RenewTarget
+	renewBefore  time.Duration
+	reloadSignal os.Signal
+}
+
+// renewState is what a failed check of a target leaves for the next one
+type renewState struct {
+	// issued is a renewed certificate and key that could not be written yet.
+	// Writing it is retried instead of issuing yet another certificate.
+	issued *domain.Certificate
+	// reloadPending is set when the files were replaced but the reload
+	// failed, so the service still uses the old certificate.
+	reloadPending bool
+}
+
+type renewAgentUseCase struct {
+	service services.CertificateService
+	now     func() time.Time
+}
+
+func NewRenewAgentUseCase() RenewAgentUseCase {
+	service := services.NewCertificateService()
+	return &renewAgentUseCase{service: service, now: time.Now}
+}
+
+// Execute checks the certificates of the configuration file every check
+// interval and re-issues those that expire within their renew_before window,
+// or are missing. The new key and certificate replace the old pair together
+// before the reload hook runs; if either step fails, the next check retries
+// it without issuing another certificate. With once, the certificates are checked a
+// single time and the errors are returned; otherwise errors are reported to
+// out and Execute runs until ctx is cancelled.
+func (uc *renewAgentUseCase) Execute(ctx context.Context, configPath string, once bool, out io.Writer) error {
+	interval, targets, err := loadRenewConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	states := make([]renewState, len(targets))
+	for {
+		var errs []error
+		for i, target := range targets {
+			if err := uc.check(ctx, target, &states[i], out); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				fmt.Fprintf(out, "%s: %v\n", target.CertFile, err)
+				errs = append(errs, fmt.Errorf("%s: %w", target.CertFile, err))
+			}
+		}
+		if once {
+			return errors.Join(errs...)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// check renews the certificate of target when it is due. A certificate that
+// was issued but not written, or a reload that failed, is retried from state
+// before anything else.
+func (uc *renewAgentUseCase) check(ctx context.Context, target renewTarget, state *renewState, out io.Writer) error {
+	if state.reloadPending {
+		if err := reload(ctx, target, out); err != nil {
+			return err
+		}
+		state.reloadPending = false
+		fmt.Fprintf(out, "%s: reloaded\n", target.CertFile)
+	}
+
+	if state.issued == nil {
+		if notAfter, ok := readNotAfter(target.CertFile); ok {
+			remaining := notAfter.Sub(uc.now())
+			if remaining > target.renewBefore {
+				fmt.Fprintf(out, "%s: valid until %s, renewing in %s\n", target.CertFile,
+					notAfter.UTC().Format(time.RFC3339), (remaining - target.renewBefore).Round(time.Minute))
+				return nil
+			}
+		}
+
+		cert, err := issueFromCSR(ctx, uc.service, target.Email, target.Role, target.Description, target.KeyType, target.Metadata)
+		if err != nil {
+			return err
+		}
+		state.issued = cert
+	}
+	cert := state.issued
+
+	// Both files are staged before either is replaced, and the old key is put
+	// back if the certificate cannot be, so a failed renewal does not leave a
+	// certificate next to a key it does not belong to
+	err := utils.WriteFilesAtomic(
+		utils.AtomicFile{Path: target.KeyFile, Data: []byte(cert.KeyPEM + "\n"), Perm: 0600},
+		utils.AtomicFile{Path: target.CertFile, Data: []byte(strings.TrimSpace(cert.CertPEM) + "\n"), Perm: 0644},
+	)
+	if err != nil {
+		return NewIOError("failed to write certificate and key", err)
+	}
+	state.issued = nil
+	fmt.Fprintf(out, "%s: renewed, new serial %s\n", target.CertFile, cert.SerialHex)
+
+	if err := reload(ctx, target, out); err != nil {
+		state.reloadPending = true
+		return err
+	}
+	return nil
+}
+
+// reload runs the reload command and sends the reload signal of target.
+func reload(ctx context.Context, target renewTarget, out io.Writer) error {
+	if target.ReloadCommand != "" {
+		args := utils.ShellArgs(target.ReloadCommand)
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stdout = out
+		cmd.Stderr = out
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("reload command failed: %w", err)
+		}
+	}
+
+	if target.reloadSignal != nil {
+		data, err := os.ReadFile(target.PIDFile)
+		if err != nil {
+			return NewIOError("failed to read PID file", err)
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil {
+			return fmt.Errorf("invalid PID file %s: %w", target.PIDFile, err)
+		}
+		process, err := os.FindProcess(pid)
+		if err == nil {
+			err = process.Signal(target.reloadSignal)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to signal process %d: %w", pid, err)
+		}
+	}
+	return nil
+}
+
+// readNotAfter returns the expiry of the certificate in path, if it can be
+// read.
+func readNotAfter(path string) (time.Time, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	certs, err := parseCertificates(string(data))
+	if err != nil || len(certs) == 0 {
+		return time.Time{}, false
+	}
+	return certs[0].NotAfter, true
+}
+
+// loadRenewConfig reads and validates the renew configuration file.
+func loadRenewConfig(path string) (time.Duration, []renewTarget, error) {
+	var cfg domain.RenewConfig
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return 0, nil, NewValidationError("failed to read renew configuration", err)
+	}
+	invalid := func(format string, args ...any) error {
+		return NewValidationError(fmt.Sprintf(format, args...), ErrInvalidRenewConfig)
+	}
+
+	interval := DefaultRenewCheckInterval
+	if cfg.CheckInterval != "" {
+		d, err := utils.ParseDuration(cfg.CheckInterval)
+		if err != nil || d <= 0 {
+			return 0, nil, invalid("check_interval must be a positive duration, got %q", cfg.CheckInterval)
+		}
+		interval = d
+	}
+	renewBefore := DefaultRenewBefore
+	if cfg.RenewBefore != "" {
+		d, err := utils.ParseDuration(cfg.RenewBefore)
+		if err != nil {
+			return 0, nil, invalid("renew_before must be a duration, got %q", cfg.RenewBefore)
+		}
+		renewBefore = d
+	}
+	if len(cfg.Certificates) == 0 {
+		return 0, nil, invalid("no [[certificate]] configured")
+	}
+
+	targets := make([]renewTarget, len(cfg.Certificates))
+	for i, c := range cfg.Certificates {
+		target := renewTarget{RenewTarget: c, renewBefore: renewBefore}
+		if c.Email == "" || c.Role == "" || c.CertFile == "" || c.KeyFile == "" {
+			return 0, nil, invalid("certificate %d: email, role, cert_file and key_file are required", i+1)
+		}
+		if target.KeyType == "" {
+			target.KeyType = utils.KeyTypeECDSAP256
+		}
+		if !slices.Contains(utils.KeyTypes, target.KeyType) {
+			return 0, nil, invalid("certificate %d: key_type must be one of %s", i+1, strings.Join(utils.KeyTypes, ", "))
+		}
+		if c.RenewBefore != "" {
+			d, err := utils.ParseDuration(c.RenewBefore)
+			if err != nil {
+				return 0, nil, invalid("certificate %d: renew_before must be a duration, got %q", i+1, c.RenewBefore)
+			}
+			target.renewBefore = d
+		}
+		if c.ReloadSignal != "" {
+			sig, err := utils.ParseSignal(c.ReloadSignal)
+			if err != nil {
+				return 0, nil, invalid("certificate %d: %v", i+1, err)
+			}
+			if c.PIDFile == "" {
+				return 0, nil, invalid("certificate %d: reload_signal requires pid_file", i+1)
+			}
+			target.reloadSignal = sig
+		}
+		targets[i] = target
+	}
+	return interval, targets, nil
+}
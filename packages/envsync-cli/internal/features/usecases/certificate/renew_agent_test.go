@@ -0,0 +1,314 @@
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/testutil"
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/envsynctest"
+)
+
+// server is shared by all tests in the package because the CLI configuration,
+// and with it the backend URL, is only loaded once per process.
+var server *envsynctest.Server
+
+func TestMain(m *testing.M) {
+	server = envsynctest.NewServer()
+
+	dir, err := testutil.UseServer(server.URL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to configure fake server: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := server.Client().Certificates.InitOrgCa(context.Background(), &sdk.InitOrgCaRequest{OrgName: "Acme"}); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize org CA: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	server.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// readCertAndKey parses the certificate and key files written by the agent
+// and checks that they belong together.
+func readCertAndKey(t *testing.T, certPath, keyPath string) *x509.Certificate {
+	t.Helper()
+
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		t.Fatalf("failed to read certificate: %v", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		t.Fatal("certificate file holds no PEM block")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		t.Fatalf("failed to read key: %v", err)
+	}
+	block, _ = pem.Decode(keyPEM)
+	if block == nil {
+		t.Fatal("key file holds no PEM block")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse key: %v", err)
+	}
+	public := key.(crypto.Signer).Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !public.Equal(cert.PublicKey) {
+		t.Error("certificate does not match the key")
+	}
+
+	info, err := os.Stat(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("key permissions = %v, want 0600", info.Mode().Perm())
+	}
+	return cert
+}
+
+func TestRenewAgent(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "gateway.crt")
+	keyPath := filepath.Join(dir, "gateway.key")
+	markerPath := filepath.Join(dir, "reloaded")
+	configPath := filepath.Join(dir, "renew.toml")
+
+	config := fmt.Sprintf(`renew_before = "30d"
+
+[[certificate]]
+email = "gateway@internal"
+role = "gateway"
+key_type = "ed25519"
+cert_file = %q
+key_file = %q
+reload_command = "echo reloaded >> %s"
+`, certPath, keyPath, filepath.ToSlash(markerPath))
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	uc := NewRenewAgentUseCase().(*renewAgentUseCase)
+	uc.now = func() time.Time { return now }
+	run := func() string {
+		t.Helper()
+		var out bytes.Buffer
+		if err := uc.Execute(context.Background(), configPath, true, &out); err != nil {
+			t.Fatalf("Execute() error = %v\n%s", err, out.String())
+		}
+		return out.String()
+	}
+
+	// A missing certificate is issued
+	if out := run(); !strings.Contains(out, "renewed") {
+		t.Errorf("output = %q, want a renewal", out)
+	}
+	first := readCertAndKey(t, certPath, keyPath)
+	if first.Subject.CommonName != "gateway@internal" {
+		t.Errorf("subject = %q, want gateway@internal", first.Subject.CommonName)
+	}
+
+	// A certificate outside the renewal window is kept
+	if out := run(); !strings.Contains(out, "valid until") {
+		t.Errorf("output = %q, want no renewal", out)
+	}
+	if cert := readCertAndKey(t, certPath, keyPath); cert.SerialNumber.Cmp(first.SerialNumber) != 0 {
+		t.Error("certificate was renewed before its renewal window")
+	}
+
+	// Within the renewal window it is replaced and the consumer reloaded
+	now = first.NotAfter.Add(-29 * 24 * time.Hour)
+	run()
+	if cert := readCertAndKey(t, certPath, keyPath); cert.SerialNumber.Cmp(first.SerialNumber) == 0 {
+		t.Error("certificate was not renewed within its renewal window")
+	}
+
+	marker, err := os.ReadFile(markerPath)
+	if err != nil {
+		t.Fatalf("reload command did not run: %v", err)
+	}
+	if got := strings.Count(string(marker), "reloaded"); got != 2 {
+		t.Errorf("reload command ran %d times, want 2", got)
+	}
+}
+
+func TestRenewAgentKeepsKeyWhenCertificateFails(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "gateway.key")
+	configPath := filepath.Join(dir, "renew.toml")
+
+	// A non-empty directory where the certificate should go makes its rename fail
+	certPath := filepath.Join(dir, "gateway.crt")
+	if err := os.MkdirAll(filepath.Join(certPath, "blocker"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, []byte("old key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := fmt.Sprintf("[[certificate]]\nemail = \"gateway@internal\"\nrole = \"gateway\"\ncert_file = %q\nkey_file = %q\n", certPath, keyPath)
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewRenewAgentUseCase().Execute(context.Background(), configPath, true, &bytes.Buffer{}); err == nil {
+		t.Fatal("Execute() succeeded, want the certificate write to fail")
+	}
+	key, err := os.ReadFile(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(key) != "old key\n" {
+		t.Errorf("key = %q, want the old key restored", key)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("temporary file %s left behind", entry.Name())
+		}
+	}
+}
+
+func TestRenewAgentRetriesWithoutReissuing(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "gateway.crt")
+	keyPath := filepath.Join(dir, "gateway.key")
+	readyPath := filepath.Join(dir, "ready")
+	configPath := filepath.Join(dir, "renew.toml")
+
+	// The certificate cannot be written and the reload fails at first
+	if err := os.MkdirAll(filepath.Join(certPath, "blocker"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf("[[certificate]]\nemail = \"gateway@internal\"\nrole = \"gateway\"\ncert_file = %q\nkey_file = %q\nreload_command = \"test -f %s\"\n",
+		certPath, keyPath, filepath.ToSlash(readyPath))
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, targets, err := loadRenewConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uc := NewRenewAgentUseCase().(*renewAgentUseCase)
+	var state renewState
+	issued := func() int {
+		n := 0
+		for _, request := range server.Requests() {
+			if strings.HasSuffix(request.Path, "/issue/csr") {
+				n++
+			}
+		}
+		return n
+	}
+	server.ResetRequests()
+
+	for i := 0; i < 2; i++ {
+		if err := uc.check(context.Background(), targets[0], &state, &bytes.Buffer{}); err == nil {
+			t.Fatal("check() succeeded, want the certificate write to fail")
+		}
+	}
+	if n := issued(); n != 1 {
+		t.Errorf("issued %d certificates, want the first one to be kept", n)
+	}
+
+	// The kept certificate is written, then the failed reload is retried
+	if err := os.RemoveAll(certPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.check(context.Background(), targets[0], &state, &bytes.Buffer{}); err == nil {
+		t.Fatal("check() succeeded, want the reload to fail")
+	}
+	readCertAndKey(t, certPath, keyPath)
+
+	if err := os.WriteFile(readyPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := uc.check(context.Background(), targets[0], &state, &out); err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "reloaded") || !strings.Contains(out.String(), "valid until") {
+		t.Errorf("output = %q, want the reload retried and no renewal", out.String())
+	}
+	if n := issued(); n != 1 {
+		t.Errorf("issued %d certificates, want 1", n)
+	}
+}
+
+func TestRenewAgentInvalidConfig(t *testing.T) {
+	tests := map[string]string{
+		"no certificates":    `check_interval = "1h"`,
+		"missing files":      "[[certificate]]\nemail = \"a@b.c\"\nrole = \"dev\"\n",
+		"bad key type":       "[[certificate]]\nemail = \"a@b.c\"\nrole = \"dev\"\ncert_file = \"c\"\nkey_file = \"k\"\nkey_type = \"dsa\"\n",
+		"signal without pid": "[[certificate]]\nemail = \"a@b.c\"\nrole = \"dev\"\ncert_file = \"c\"\nkey_file = \"k\"\nreload_signal = \"HUP\"\n",
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "renew.toml")
+			if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := NewRenewAgentUseCase().Execute(context.Background(), path, true, &bytes.Buffer{})
+			if _, ok := err.(*CertError); !ok {
+				t.Errorf("Execute() error = %v, want a *CertError", err)
+			}
+		})
+	}
+}
+
+func TestExpiringCerts(t *testing.T) {
+	if _, err := NewIssueCertFromCSRUseCase().Execute(context.Background(), "expiring@example.com", "dev", "", "ecdsa-p256", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	certs, err := NewExpiringCertsUseCase().Execute(context.Background(), 400*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, cert := range certs {
+		if cert.SubjectCN == "expiring@example.com" {
+			found = true
+		}
+		if cert.Status != "active" {
+			t.Errorf("certificate %s has status %s", cert.SerialHex, cert.Status)
+		}
+	}
+	if !found {
+		t.Error("certificate expiring within the window not listed")
+	}
+
+	certs, err = NewExpiringCertsUseCase().Execute(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 0 {
+		t.Errorf("got %d certificates expiring within a day, want none", len(certs))
+	}
+}
@@ -0,0 +1,428 @@
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+// Key usages a certificate can be verified for
+const (
+	UsageClient = "client"
+	UsageServer = "server"
+	UsageAny    = "any"
+)
+
+// Names of the checks of a certificate verification
+const (
+	CheckChain    = "chain"
+	CheckValidity = "validity"
+	CheckKeyUsage = "key_usage"
+	CheckCRL      = "crl"
+	CheckOCSP     = "ocsp"
+)
+
+// reasonRemoveFromCRL marks a delta CRL entry that lifts the hold of a
+// certificate listed in the base CRL.
+const reasonRemoveFromCRL = 8
+
+// oidDeltaCRLIndicator identifies the extension of delta CRLs.
+var oidDeltaCRLIndicator = asn1.ObjectIdentifier{2, 5, 29, 27}
+
+var ErrInvalidCertificate = errors.New("invalid certificate")
+
+// VerifyCertOptions holds the material to verify a certificate against.
+// Material that is not given is fetched from the server.
+type VerifyCertOptions struct {
+	RootCAPEM string
+	ChainPEM  string
+	CRLPEMs   []string
+	// Usage is the extended key usage the certificate must allow.
+	Usage string
+	// OCSP cross-checks the revocation status with the server.
+	OCSP bool
+}
+
+type verifyCertUseCase struct {
+	service services.CertificateService
+	now     func() time.Time
+}
+
+func NewVerifyCertUseCase() VerifyCertUseCase {
+	service := services.NewCertificateService()
+	return &verifyCertUseCase{service: service, now: time.Now}
+}
+
+// Execute verifies the chain, validity window, key usage and revocation of
+// the certificate locally. Additional certificates in certPEM are used as
+// intermediates.
+func (uc *verifyCertUseCase) Execute(ctx context.Context, certPEM string, opts VerifyCertOptions) (*domain.CertVerification, error) {
+	certs, err := parseCertificates(certPEM)
+	if err != nil || len(certs) == 0 {
+		return nil, NewValidationError("certificate must be a PEM-encoded X.509 certificate", ErrInvalidCertificate)
+	}
+	leaf := certs[0]
+	now := uc.now()
+
+	roots, err := uc.roots(ctx, opts.RootCAPEM)
+	if err != nil {
+		return nil, err
+	}
+	intermediates, err := uc.intermediates(ctx, opts.ChainPEM, certs[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.CertVerification{
+		SerialHex: hex.EncodeToString(leaf.SerialNumber.Bytes()),
+		SubjectCN: leaf.Subject.CommonName,
+		NotBefore: leaf.NotBefore,
+		NotAfter:  leaf.NotAfter,
+	}
+
+	chainCheck, issuer := checkChain(leaf, roots, intermediates, now)
+	result.Checks = append(result.Checks,
+		chainCheck,
+		checkValidity(leaf, now),
+		checkKeyUsage(leaf, opts.Usage),
+	)
+
+	crlCheck, revoked := domain.CertCheck{Name: CheckCRL, Status: domain.CertCheckSkip, Message: "issuer unknown"}, false
+	if issuer != nil {
+		crls, err := uc.crls(ctx, opts.CRLPEMs)
+		if err != nil {
+			return nil, err
+		}
+		crlCheck, revoked = checkCRL(leaf, issuer, crls, now)
+	}
+	result.Checks = append(result.Checks, crlCheck)
+
+	if opts.OCSP {
+		result.Checks = append(result.Checks, uc.checkOCSP(ctx, result.SerialHex, crlCheck.Status != domain.CertCheckSkip, revoked))
+	}
+
+	result.Valid = true
+	for _, check := range result.Checks {
+		if check.Status == domain.CertCheckFail {
+			result.Valid = false
+		}
+	}
+	return result, nil
+}
+
+func (uc *verifyCertUseCase) roots(ctx context.Context, rootPEM string) ([]*x509.Certificate, error) {
+	if rootPEM == "" {
+		var err error
+		if rootPEM, err = uc.service.GetRootCA(ctx); err != nil {
+			return nil, NewServiceError("failed to get root CA", err)
+		}
+	}
+	roots, err := parseCertificates(rootPEM)
+	if err != nil || len(roots) == 0 {
+		return nil, NewValidationError("root CA must be a PEM-encoded X.509 certificate", ErrInvalidCertificate)
+	}
+	return roots, nil
+}
+
+// intermediates returns the given chain, or the organization CA when the
+// certificate file holds no chain either.
+func (uc *verifyCertUseCase) intermediates(ctx context.Context, chainPEM string, bundled []*x509.Certificate) ([]*x509.Certificate, error) {
+	if chainPEM == "" && len(bundled) > 0 {
+		return bundled, nil
+	}
+	if chainPEM == "" {
+		ca, err := uc.service.GetCA(ctx)
+		if err != nil {
+			return nil, NewServiceError("failed to get organization CA", err)
+		}
+		chainPEM = ca.CertPEM
+	}
+	chain, err := parseCertificates(chainPEM)
+	if err != nil {
+		return nil, NewValidationError("chain must hold PEM-encoded X.509 certificates", ErrInvalidCertificate)
+	}
+	return append(chain, bundled...), nil
+}
+
+// revocationList is a parsed CRL. baseNumber is set for delta CRLs to the
+// number of the full CRL they build on, from the Delta CRL Indicator.
+type revocationList struct {
+	*x509.RevocationList
+	baseNumber *big.Int
+}
+
+func (l revocationList) delta() bool {
+	return l.baseNumber != nil
+}
+
+func (uc *verifyCertUseCase) crls(ctx context.Context, crlPEMs []string) ([]revocationList, error) {
+	var lists []revocationList
+	if len(crlPEMs) == 0 {
+		result, err := uc.service.GetCRL(ctx)
+		if err != nil {
+			return nil, NewServiceError("failed to get CRL", err)
+		}
+		list, err := parseCRL(result.CRLPEM)
+		if err != nil {
+			return nil, NewServiceError("failed to parse CRL", err)
+		}
+		if result.IsDelta && !list.delta() {
+			return nil, NewServiceError("failed to parse CRL", errors.New("delta CRL without a Delta CRL Indicator"))
+		}
+		return append(lists, list), nil
+	}
+
+	for _, crlPEM := range crlPEMs {
+		list, err := parseCRL(crlPEM)
+		if err != nil {
+			return nil, NewValidationError("failed to parse CRL", err)
+		}
+		lists = append(lists, list)
+	}
+	return lists, nil
+}
+
+func (uc *verifyCertUseCase) checkOCSP(ctx context.Context, serialHex string, crlChecked, crlRevoked bool) domain.CertCheck {
+	check := domain.CertCheck{Name: CheckOCSP}
+	result, err := uc.service.CheckOCSP(ctx, serialHex)
+	if err != nil {
+		check.Status = domain.CertCheckWarn
+		check.Message = "OCSP unavailable: " + err.Error()
+		return check
+	}
+
+	switch result.Status {
+	case "good":
+		check.Status = domain.CertCheckPass
+		check.Message = "good"
+	case "revoked":
+		check.Status = domain.CertCheckFail
+		check.Message = "revoked"
+		if result.RevokedAt != nil {
+			check.Message += " at " + *result.RevokedAt
+		}
+	default:
+		check.Status = domain.CertCheckWarn
+		check.Message = result.Status
+	}
+	if crlChecked && crlRevoked != (result.Status == "revoked") {
+		check.Message += " (disagrees with CRL)"
+	}
+	return check
+}
+
+func checkChain(leaf *x509.Certificate, roots, intermediates []*x509.Certificate, now time.Time) (domain.CertCheck, *x509.Certificate) {
+	rootPool := x509.NewCertPool()
+	for _, root := range roots {
+		rootPool.AddCert(root)
+	}
+	intermediatePool := x509.NewCertPool()
+	for _, intermediate := range intermediates {
+		intermediatePool.AddCert(intermediate)
+	}
+
+	chains, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         rootPool,
+		Intermediates: intermediatePool,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return domain.CertCheck{Name: CheckChain, Status: domain.CertCheckFail, Message: err.Error()}, nil
+	}
+
+	chain := chains[0]
+	subjects := make([]string, len(chain))
+	for i, cert := range chain {
+		subjects[i] = cert.Subject.CommonName
+	}
+	check := domain.CertCheck{Name: CheckChain, Status: domain.CertCheckPass, Message: strings.Join(subjects, " -> ")}
+	if len(chain) < 2 {
+		// A self-signed certificate has no issuer to check revocation with
+		return check, nil
+	}
+	return check, chain[1]
+}
+
+func checkValidity(leaf *x509.Certificate, now time.Time) domain.CertCheck {
+	check := domain.CertCheck{Name: CheckValidity}
+	switch {
+	case now.Before(leaf.NotBefore):
+		check.Status = domain.CertCheckFail
+		check.Message = "not valid before " + leaf.NotBefore.UTC().Format(time.RFC3339)
+	case now.After(leaf.NotAfter):
+		check.Status = domain.CertCheckFail
+		check.Message = "expired on " + leaf.NotAfter.UTC().Format(time.RFC3339)
+	default:
+		check.Status = domain.CertCheckPass
+		check.Message = fmt.Sprintf("valid until %s (%d days left)",
+			leaf.NotAfter.UTC().Format(time.RFC3339), int(leaf.NotAfter.Sub(now).Hours()/24))
+	}
+	return check
+}
+
+func checkKeyUsage(leaf *x509.Certificate, usage string) domain.CertCheck {
+	check := domain.CertCheck{Name: CheckKeyUsage}
+	var want x509.ExtKeyUsage
+	switch usage {
+	case UsageClient, "":
+		usage, want = UsageClient, x509.ExtKeyUsageClientAuth
+	case UsageServer:
+		want = x509.ExtKeyUsageServerAuth
+	case UsageAny:
+		check.Status = domain.CertCheckSkip
+		return check
+	default:
+		check.Status = domain.CertCheckFail
+		check.Message = "unknown usage " + usage
+		return check
+	}
+
+	if leaf.KeyUsage != 0 && leaf.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		check.Status = domain.CertCheckFail
+		check.Message = "digital signature key usage not allowed"
+		return check
+	}
+	for _, eku := range leaf.ExtKeyUsage {
+		if eku == want || eku == x509.ExtKeyUsageAny {
+			check.Status = domain.CertCheckPass
+			check.Message = usage + " authentication allowed"
+			return check
+		}
+	}
+	check.Status = domain.CertCheckFail
+	check.Message = usage + " authentication not allowed"
+	return check
+}
+
+// checkCRL checks the revocation of leaf with the CRLs of its issuer. The
+// latest full CRL is applied first, followed by the newer delta CRLs built on
+// it in order. A delta CRL whose base is not available fails the check, since
+// the revocations between that base and the full CRL are unknown.
+func checkCRL(leaf, issuer *x509.Certificate, lists []revocationList, now time.Time) (domain.CertCheck, bool) {
+	check := domain.CertCheck{Name: CheckCRL}
+	var base *revocationList
+	var deltas []revocationList
+	var stale bool
+	for i := range lists {
+		list := lists[i]
+		if err := list.CheckSignatureFrom(issuer); err != nil {
+			check.Status = domain.CertCheckFail
+			check.Message = "CRL not signed by the issuer: " + err.Error()
+			return check, false
+		}
+		if !list.NextUpdate.IsZero() && now.After(list.NextUpdate) {
+			stale = true
+		}
+		if list.delta() {
+			deltas = append(deltas, list)
+		} else if base == nil || list.Number.Cmp(base.Number) > 0 {
+			base = &lists[i]
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].Number.Cmp(deltas[j].Number) < 0
+	})
+
+	var revokedBy *revocationList
+	var entry *x509.RevocationListEntry
+	apply := func(list *revocationList) {
+		for i, e := range list.RevokedCertificateEntries {
+			if e.SerialNumber.Cmp(leaf.SerialNumber) != 0 {
+				continue
+			}
+			if e.ReasonCode == reasonRemoveFromCRL {
+				revokedBy, entry = nil, nil
+			} else {
+				revokedBy, entry = list, &list.RevokedCertificateEntries[i]
+			}
+		}
+	}
+	if base != nil {
+		apply(base)
+	}
+	var unmatched *revocationList
+	for i := range deltas {
+		switch {
+		case base == nil || deltas[i].baseNumber.Cmp(base.Number) > 0:
+			unmatched = &deltas[i]
+		case deltas[i].Number.Cmp(base.Number) > 0:
+			apply(&deltas[i])
+		}
+	}
+
+	switch {
+	case entry != nil:
+		check.Status = domain.CertCheckFail
+		check.Message = fmt.Sprintf("revoked at %s (reason %d, CRL #%s)",
+			entry.RevocationTime.UTC().Format(time.RFC3339), entry.ReasonCode, revokedBy.Number)
+		return check, true
+	case unmatched != nil:
+		check.Status = domain.CertCheckFail
+		check.Message = fmt.Sprintf("delta CRL #%s needs full CRL #%s or newer, which was not available (pass it with --crl)",
+			unmatched.Number, unmatched.baseNumber)
+	case stale:
+		check.Status = domain.CertCheckWarn
+		check.Message = fmt.Sprintf("not revoked, but CRL #%s is past its next update", base.Number)
+	default:
+		check.Status = domain.CertCheckPass
+		check.Message = fmt.Sprintf("not revoked (CRL #%s)", base.Number)
+	}
+	return check, false
+}
+
+func parseCertificates(data string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+}
+
+// parseCRL parses a PEM or DER encoded CRL.
+func parseCRL(data string) (revocationList, error) {
+	der := []byte(data)
+	if block, _ := pem.Decode(der); block != nil {
+		der = block.Bytes
+	}
+	list, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return revocationList{}, err
+	}
+	if list.Number == nil {
+		list.Number = new(big.Int)
+	}
+	var baseNumber *big.Int
+	for _, ext := range list.Extensions {
+		if ext.Id.Equal(oidDeltaCRLIndicator) {
+			baseNumber = new(big.Int)
+			if _, err := asn1.Unmarshal(ext.Value, &baseNumber); err != nil {
+				return revocationList{}, fmt.Errorf("invalid Delta CRL Indicator: %w", err)
+			}
+		}
+	}
+	return revocationList{RevocationList: list, baseNumber: baseNumber}, nil
+}
@@ -0,0 +1,255 @@
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+)
+
+var testNow = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+func newTestCert(t *testing.T, template *x509.Certificate, parent *testCA) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, key.Public(), parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+}
+
+// newTestPKI returns a root CA, an organization CA and a member certificate
+// issued by the organization CA.
+func newTestPKI(t *testing.T, notAfter time.Time) (root, org, member *testCA) {
+	t.Helper()
+	ca := func(serial int64, name string) *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             testNow.Add(-24 * time.Hour),
+			NotAfter:              testNow.Add(10 * 365 * 24 * time.Hour),
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+		}
+	}
+	root = newTestCert(t, ca(1, "Root CA"), nil)
+	org = newTestCert(t, ca(2, "Acme CA"), root)
+	member = newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "dev@example.com"},
+		NotBefore:    testNow.Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, org)
+	return root, org, member
+}
+
+// newTestCRL returns a CRL of issuer listing the serials with their reasons.
+// A non-zero base makes it a delta CRL on the full CRL with that number.
+func newTestCRL(t *testing.T, issuer *testCA, number, base int64, revoked map[int64]int) string {
+	t.Helper()
+	template := &x509.RevocationList{
+		Number:     big.NewInt(number),
+		ThisUpdate: testNow.Add(-time.Hour),
+		NextUpdate: testNow.Add(24 * time.Hour),
+	}
+	for serial, reason := range revoked {
+		template.RevokedCertificateEntries = append(template.RevokedCertificateEntries, x509.RevocationListEntry{
+			SerialNumber:   big.NewInt(serial),
+			RevocationTime: testNow.Add(-time.Minute),
+			ReasonCode:     reason,
+		})
+	}
+	if base != 0 {
+		value, _ := asn1.Marshal(big.NewInt(base))
+		template.ExtraExtensions = append(template.ExtraExtensions, pkix.Extension{Id: oidDeltaCRLIndicator, Critical: true, Value: value})
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, template, issuer.cert, issuer.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}))
+}
+
+func checkStatuses(result *domain.CertVerification) map[string]string {
+	statuses := make(map[string]string)
+	for _, check := range result.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestVerifyCert(t *testing.T) {
+	root, org, member := newTestPKI(t, testNow.Add(365*24*time.Hour))
+	_, _, expired := newTestPKI(t, testNow.Add(-time.Minute))
+	_, otherOrg, _ := newTestPKI(t, testNow.Add(time.Hour))
+
+	tests := []struct {
+		name   string
+		cert   string
+		chain  string
+		crls   []string
+		usage  string
+		valid  bool
+		checks map[string]string
+	}{
+		{
+			name:   "valid",
+			cert:   member.pem,
+			crls:   []string{newTestCRL(t, org, 1, 0, nil)},
+			valid:  true,
+			checks: map[string]string{CheckChain: "pass", CheckValidity: "pass", CheckKeyUsage: "pass", CheckCRL: "pass"},
+		},
+		{
+			name:   "chain bundled with the certificate",
+			cert:   member.pem + org.pem,
+			chain:  "-",
+			crls:   []string{newTestCRL(t, org, 1, 0, nil)},
+			valid:  true,
+			checks: map[string]string{CheckChain: "pass", CheckCRL: "pass"},
+		},
+		{
+			name:   "revoked in full CRL",
+			cert:   member.pem,
+			crls:   []string{newTestCRL(t, org, 1, 0, map[int64]int{42: 1})},
+			checks: map[string]string{CheckChain: "pass", CheckCRL: "fail"},
+		},
+		{
+			name: "revoked in delta CRL",
+			cert: member.pem,
+			crls: []string{
+				newTestCRL(t, org, 1, 0, nil),
+				newTestCRL(t, org, 2, 1, map[int64]int{42: 1}),
+			},
+			checks: map[string]string{CheckCRL: "fail"},
+		},
+		{
+			name: "hold removed by delta CRL",
+			cert: member.pem,
+			crls: []string{
+				newTestCRL(t, org, 1, 0, map[int64]int{42: 6}),
+				newTestCRL(t, org, 2, 1, map[int64]int{42: reasonRemoveFromCRL}),
+			},
+			valid:  true,
+			checks: map[string]string{CheckCRL: "pass"},
+		},
+		{
+			name:   "only a delta CRL",
+			cert:   member.pem,
+			crls:   []string{newTestCRL(t, org, 2, 1, nil)},
+			checks: map[string]string{CheckCRL: "fail"},
+		},
+		{
+			name: "delta CRL on a newer base",
+			cert: member.pem,
+			crls: []string{
+				newTestCRL(t, org, 1, 0, nil),
+				newTestCRL(t, org, 3, 2, nil),
+			},
+			checks: map[string]string{CheckCRL: "fail"},
+		},
+		{
+			name: "delta CRL matched by its base number",
+			cert: member.pem,
+			crls: []string{
+				newTestCRL(t, org, 3, 0, nil),
+				newTestCRL(t, org, 5, 2, map[int64]int{42: 1}),
+			},
+			checks: map[string]string{CheckCRL: "fail"},
+		},
+		{
+			name:   "CRL from another issuer",
+			cert:   member.pem,
+			crls:   []string{newTestCRL(t, otherOrg, 1, 0, nil)},
+			checks: map[string]string{CheckCRL: "fail"},
+		},
+		{
+			name:   "expired",
+			cert:   expired.pem,
+			crls:   []string{newTestCRL(t, org, 1, 0, nil)},
+			checks: map[string]string{CheckChain: "fail", CheckValidity: "fail", CheckCRL: "skip"},
+		},
+		{
+			name:   "wrong key usage",
+			cert:   member.pem,
+			crls:   []string{newTestCRL(t, org, 1, 0, nil)},
+			usage:  UsageServer,
+			checks: map[string]string{CheckChain: "pass", CheckKeyUsage: "fail"},
+		},
+		{
+			name:   "untrusted chain",
+			cert:   member.pem,
+			chain:  otherOrg.pem,
+			checks: map[string]string{CheckChain: "fail", CheckCRL: "skip"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &verifyCertUseCase{now: func() time.Time { return testNow }}
+			chain := tt.chain
+			switch chain {
+			case "":
+				chain = org.pem
+			case "-":
+				chain = ""
+			}
+
+			result, err := uc.Execute(context.Background(), tt.cert, VerifyCertOptions{
+				RootCAPEM: root.pem,
+				ChainPEM:  chain,
+				CRLPEMs:   tt.crls,
+				Usage:     tt.usage,
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Valid != tt.valid {
+				t.Errorf("Valid = %v, want %v (checks: %+v)", result.Valid, tt.valid, result.Checks)
+			}
+			statuses := checkStatuses(result)
+			for name, want := range tt.checks {
+				if statuses[name] != want {
+					t.Errorf("%s check = %q, want %q (checks: %+v)", name, statuses[name], want, result.Checks)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyCertInvalidPEM(t *testing.T) {
+	uc := &verifyCertUseCase{now: time.Now}
+	_, err := uc.Execute(context.Background(), "not a certificate", VerifyCertOptions{})
+	if err == nil {
+		t.Fatal("expected an error for an invalid certificate")
+	}
+}
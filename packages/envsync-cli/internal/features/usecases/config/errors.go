@@ -12,6 +12,10 @@ var (
 	ErrEmptyBackendURL   = errors.New("backend URL cannot be empty")
 	ErrInvalidBackendURL = errors.New("backend URL is invalid")
 
+	ErrInvalidCacheMaxStaleness = errors.New("cache max staleness must be a positive duration such as 72h")
+
+	ErrEmptyCertificatePath = errors.New("certificate path cannot be empty")
+
 	// File system errors
 	ErrConfigFileNotFound   = errors.New("configuration file not found")
 	ErrConfigFileRead       = errors.New("failed to read configuration file")
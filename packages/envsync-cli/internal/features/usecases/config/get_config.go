@@ -62,6 +62,14 @@ func (uc *getConfigUseCase) getConfigValue(cfg config.AppConfig, key string) (st
 	switch normalizedKey {
 	case "backend_url", "backendurl":
 		return cfg.BackendURL, cfg.BackendURL != ""
+	case "cache_max_staleness", "cachemaxstaleness":
+		return cfg.MaxStaleness().String(), true
+	case "client_cert", "clientcert":
+		return cfg.ClientCert, cfg.ClientCert != ""
+	case "client_key", "clientkey":
+		return cfg.ClientKey, cfg.ClientKey != ""
+	case "ca_cert", "cacert":
+		return cfg.CACert, cfg.CACert != ""
 	default:
 		return "", false
 	}
@@ -74,6 +82,16 @@ func (uc *getConfigUseCase) getAllConfigValues(cfg config.AppConfig) map[string]
 		values["backend_url"] = cfg.BackendURL
 	}
 
+	values["cache_max_staleness"] = cfg.MaxStaleness().String()
+
+	if cfg.ClientCert != "" {
+		values["client_cert"] = cfg.ClientCert
+		values["client_key"] = cfg.ClientKey
+	}
+	if cfg.CACert != "" {
+		values["ca_cert"] = cfg.CACert
+	}
+
 	return values
 }
 
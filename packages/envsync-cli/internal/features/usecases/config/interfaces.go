@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"time"
 
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/config"
 )
@@ -111,8 +112,16 @@ func (r ResetConfigRequest) Validate() error {
 // Helper functions for validation
 func isValidConfigKey(key string) bool {
 	validKeys := map[string]bool{
-		"backend_url": true,
-		"backendurl":  true,
+		"backend_url":         true,
+		"backendurl":          true,
+		"cache_max_staleness": true,
+		"cachemaxstaleness":   true,
+		"client_cert":         true,
+		"clientcert":          true,
+		"client_key":          true,
+		"clientkey":           true,
+		"ca_cert":             true,
+		"cacert":              true,
 	}
 
 	return validKeys[key]
@@ -127,6 +136,14 @@ func validateConfigValue(key, value string) error {
 		if !isValidURL(value) {
 			return ErrInvalidBackendURL
 		}
+	case "cache_max_staleness", "cachemaxstaleness":
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return ErrInvalidCacheMaxStaleness
+		}
+	case "client_cert", "clientcert", "client_key", "clientkey", "ca_cert", "cacert":
+		if value == "" {
+			return ErrEmptyCertificatePath
+		}
 	}
 
 	return nil
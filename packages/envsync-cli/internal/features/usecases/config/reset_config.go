@@ -77,8 +77,16 @@ func (uc *resetConfigUseCase) resetConfigKey(cfg *config.AppConfig, key string)
 	switch normalizedKey {
 	case "backend_url", "backendurl":
 		cfg.BackendURL = "https://api.envsync.dev"
+	case "cache_max_staleness", "cachemaxstaleness":
+		cfg.CacheMaxStaleness = ""
+	case "client_cert", "clientcert", "client_key", "clientkey":
+		// The certificate is useless without its key
+		cfg.ClientCert = ""
+		cfg.ClientKey = ""
+	case "ca_cert", "cacert":
+		cfg.CACert = ""
 	default:
-		return fmt.Errorf("unknown configuration key: '%s'. Valid keys are: backend_url", key)
+		return fmt.Errorf("unknown configuration key: '%s'. Valid keys are: backend_url, cache_max_staleness, client_cert, client_key, ca_cert", key)
 	}
 
 	return nil
@@ -2,8 +2,13 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/config"
 )
@@ -54,13 +59,32 @@ func (uc *setConfigUseCase) setConfigValue(cfg *config.AppConfig, key, value str
 	switch normalizedKey {
 	case "backend_url", "backendurl":
 		cfg.BackendURL = value
+	case "cache_max_staleness", "cachemaxstaleness":
+		cfg.CacheMaxStaleness = value
+	case "client_cert", "clientcert":
+		return setPath(&cfg.ClientCert, value)
+	case "client_key", "clientkey":
+		return setPath(&cfg.ClientKey, value)
+	case "ca_cert", "cacert":
+		return setPath(&cfg.CACert, value)
 	default:
-		return fmt.Errorf("unknown configuration key: '%s'. Valid keys are: backend_url", key)
+		return fmt.Errorf("unknown configuration key: '%s'. Valid keys are: backend_url, cache_max_staleness, client_cert, client_key, ca_cert", key)
 	}
 
 	return nil
 }
 
+// setPath stores value as an absolute path, so the configuration works from
+// any directory.
+func setPath(field *string, value string) error {
+	path, err := filepath.Abs(value)
+	if err != nil {
+		return err
+	}
+	*field = path
+	return nil
+}
+
 func (uc *setConfigUseCase) validateConfiguration(cfg config.AppConfig) error {
 	var issues []string
 
@@ -74,6 +98,28 @@ func (uc *setConfigUseCase) validateConfiguration(cfg config.AppConfig) error {
 		}
 	}
 
+	// Validate cache staleness
+	if cfg.CacheMaxStaleness != "" {
+		if d, err := time.ParseDuration(cfg.CacheMaxStaleness); err != nil || d <= 0 {
+			issues = append(issues, "cache max staleness must be a positive duration such as 72h")
+		}
+	}
+
+	// Validate client certificate
+	if (cfg.ClientCert == "") != (cfg.ClientKey == "") {
+		issues = append(issues, "client_cert and client_key must be set together")
+	} else if cfg.ClientCert != "" {
+		if _, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey); err != nil {
+			issues = append(issues, fmt.Sprintf("client certificate cannot be loaded: %v", err))
+		}
+	}
+	if cfg.CACert != "" {
+		data, err := os.ReadFile(cfg.CACert)
+		if err != nil || !x509.NewCertPool().AppendCertsFromPEM(data) {
+			issues = append(issues, "ca_cert must be a PEM file with CA certificates")
+		}
+	}
+
 	if len(issues) > 0 {
 		return fmt.Errorf("configuration validation failed: %s", strings.Join(issues, "; "))
 	}
@@ -0,0 +1,12 @@
+package export
+
+import "errors"
+
+// Export use case errors
+var (
+	ErrUnsupportedFormat  = errors.New("unsupported export format")
+	ErrInvalidKey         = errors.New("key is not valid for this format")
+	ErrInvalidValue       = errors.New("value cannot be represented in this format")
+	ErrMissingName        = errors.New("a resource name is required for Kubernetes formats")
+	ErrSecretsInConfigMap = errors.New("a ConfigMap cannot hold secrets, use the k8s or k8s-secret format")
+)
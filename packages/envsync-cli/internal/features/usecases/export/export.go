@@ -0,0 +1,42 @@
+package export
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/utils"
+)
+
+type exportUseCase struct{}
+
+func NewExportUseCase() ExportUseCase {
+	return &exportUseCase{}
+}
+
+func (uc *exportUseCase) Execute(ctx context.Context, req ExportRequest) (*ExportResponse, error) {
+	render, ok := renderers[req.Format]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s' (supported: %s)", ErrUnsupportedFormat, req.Format, supportedFormatList())
+	}
+
+	data, err := render(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ExportResponse{
+		Format: req.Format,
+		Data:   data,
+		Count:  len(mergeEnv(req.Variables, req.Secrets)),
+	}
+
+	if req.Output != "" {
+		// Exports contain decrypted secrets, so keep them private to the user
+		if err := utils.WriteFileAtomic(req.Output, data, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write export file: %w", err)
+		}
+		res.Path = req.Output
+	}
+
+	return res, nil
+}
@@ -0,0 +1,332 @@
+package export
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Format identifies a deployment format the environment can be rendered to
+type Format string
+
+const (
+	FormatKubernetes          Format = "k8s"
+	FormatKubernetesSecret    Format = "k8s-secret"
+	FormatKubernetesConfigMap Format = "k8s-configmap"
+	FormatDocker              Format = "docker"
+	FormatSystemd             Format = "systemd"
+	FormatJSON                Format = "json"
+	FormatShell               Format = "shell"
+	FormatGitHub              Format = "github"
+	FormatTerraform           Format = "tfvars"
+)
+
+type renderer func(ExportRequest) ([]byte, error)
+
+var renderers = map[Format]renderer{
+	FormatKubernetes:          renderKubernetes,
+	FormatKubernetesSecret:    renderKubernetesSecret,
+	FormatKubernetesConfigMap: renderKubernetesConfigMap,
+	FormatDocker:              renderDocker,
+	FormatSystemd:             renderSystemd,
+	FormatJSON:                renderJSON,
+	FormatShell:               renderShell,
+	FormatGitHub:              renderGitHub,
+	FormatTerraform:           renderTerraform,
+}
+
+var (
+	envNamePattern       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	kubernetesKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+	hclNamePattern       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	dnsInvalidChars      = regexp.MustCompile(`[^a-z0-9.-]+`)
+)
+
+// newDelimiter returns the heredoc delimiter for multiline $GITHUB_ENV values
+var newDelimiter = func() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "ghadelimiter_" + hex.EncodeToString(b)
+}
+
+// SupportedFormats returns the names of all export formats
+func SupportedFormats() []string {
+	formats := make([]string, 0, len(renderers))
+	for format := range renderers {
+		formats = append(formats, string(format))
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+func supportedFormatList() string {
+	return strings.Join(SupportedFormats(), ", ")
+}
+
+// renderKubernetes emits a ConfigMap with the variables and a Secret with the
+// secrets as a multi-document YAML file.
+func renderKubernetes(req ExportRequest) ([]byte, error) {
+	variables := make(map[string]string, len(req.Variables))
+	for key, value := range req.Variables {
+		if _, isSecret := req.Secrets[key]; !isSecret {
+			variables[key] = value
+		}
+	}
+
+	configMap, err := kubernetesManifest(req, "ConfigMap", variables)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := kubernetesManifest(req, "Secret", req.Secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(configMap, []byte("---\n")...), secret...), nil
+}
+
+func renderKubernetesSecret(req ExportRequest) ([]byte, error) {
+	return kubernetesManifest(req, "Secret", mergeEnv(req.Variables, req.Secrets))
+}
+
+// renderKubernetesConfigMap refuses secrets, which a ConfigMap would store in
+// plaintext.
+func renderKubernetesConfigMap(req ExportRequest) ([]byte, error) {
+	if len(req.Secrets) > 0 {
+		return nil, ErrSecretsInConfigMap
+	}
+	return kubernetesManifest(req, "ConfigMap", req.Variables)
+}
+
+func kubernetesManifest(req ExportRequest, kind string, env map[string]string) ([]byte, error) {
+	name := kubernetesName(req.Name)
+	if name == "" {
+		return nil, ErrMissingName
+	}
+
+	var b strings.Builder
+	b.WriteString("apiVersion: v1\n")
+	b.WriteString("kind: " + kind + "\n")
+	b.WriteString("metadata:\n")
+	b.WriteString("  name: " + quoteJSON(name) + "\n")
+	if req.Namespace != "" {
+		b.WriteString("  namespace: " + quoteJSON(req.Namespace) + "\n")
+	}
+	if kind == "Secret" {
+		b.WriteString("type: Opaque\n")
+	}
+
+	keys := sortedKeys(env)
+	if len(keys) == 0 {
+		b.WriteString("data: {}\n")
+		return []byte(b.String()), nil
+	}
+
+	b.WriteString("data:\n")
+	for _, key := range keys {
+		if !kubernetesKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("%w: '%s' (Kubernetes keys may only contain alphanumerics, '-', '_' and '.')", ErrInvalidKey, key)
+		}
+
+		value := env[key]
+		if kind == "Secret" {
+			value = base64.StdEncoding.EncodeToString([]byte(value))
+		}
+
+		// JSON strings are valid YAML double-quoted scalars
+		b.WriteString("  " + quoteJSON(key) + ": " + quoteJSON(value) + "\n")
+	}
+
+	return []byte(b.String()), nil
+}
+
+// renderDocker emits a file for 'docker run --env-file'. Docker reads values
+// literally without any quoting, so multiline values cannot be represented.
+func renderDocker(req ExportRequest) ([]byte, error) {
+	env := mergeEnv(req.Variables, req.Secrets)
+
+	var b strings.Builder
+	for _, key := range sortedKeys(env) {
+		if key == "" || strings.ContainsAny(key, "= \t\r\n") {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidKey, key)
+		}
+
+		value := env[key]
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("%w: '%s' contains a newline, which Docker env files do not support", ErrInvalidValue, key)
+		}
+
+		b.WriteString(key + "=" + value + "\n")
+	}
+
+	return []byte(b.String()), nil
+}
+
+// renderSystemd emits a file for systemd's EnvironmentFile=. Inside double
+// quotes systemd unescapes \", \\, \$ and \` and keeps newlines verbatim.
+func renderSystemd(req ExportRequest) ([]byte, error) {
+	env := mergeEnv(req.Variables, req.Secrets)
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`")
+
+	var b strings.Builder
+	for _, key := range sortedKeys(env) {
+		if !envNamePattern.MatchString(key) {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidKey, key)
+		}
+
+		b.WriteString(key + `="` + escaper.Replace(env[key]) + "\"\n")
+	}
+
+	return []byte(b.String()), nil
+}
+
+func renderJSON(req ExportRequest) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	// encoding/json sorts map keys, keeping the output stable
+	if err := encoder.Encode(mergeEnv(req.Variables, req.Secrets)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// renderShell emits a POSIX shell script of export statements. Values are
+// single-quoted, so nothing inside them is expanded.
+func renderShell(req ExportRequest) ([]byte, error) {
+	env := mergeEnv(req.Variables, req.Secrets)
+
+	var b strings.Builder
+	for _, key := range sortedKeys(env) {
+		if !envNamePattern.MatchString(key) {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidKey, key)
+		}
+
+		value := strings.ReplaceAll(env[key], `'`, `'\''`)
+		b.WriteString("export " + key + "='" + value + "'\n")
+	}
+
+	return []byte(b.String()), nil
+}
+
+// renderGitHub emits lines for the $GITHUB_ENV file of GitHub Actions.
+// Multiline values use the heredoc syntax with a random delimiter.
+func renderGitHub(req ExportRequest) ([]byte, error) {
+	env := mergeEnv(req.Variables, req.Secrets)
+
+	var b strings.Builder
+	for _, key := range sortedKeys(env) {
+		if !envNamePattern.MatchString(key) {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidKey, key)
+		}
+
+		value := env[key]
+		if !strings.ContainsAny(value, "\r\n") {
+			b.WriteString(key + "=" + value + "\n")
+			continue
+		}
+
+		delimiter := newDelimiter()
+		for strings.Contains(value, delimiter) {
+			delimiter = newDelimiter()
+		}
+		b.WriteString(key + "<<" + delimiter + "\n" + value + "\n" + delimiter + "\n")
+	}
+
+	return []byte(b.String()), nil
+}
+
+// renderTerraform emits a .tfvars file with HCL string literals. Template
+// sequences are escaped so values are never interpolated by Terraform.
+func renderTerraform(req ExportRequest) ([]byte, error) {
+	env := mergeEnv(req.Variables, req.Secrets)
+
+	var b strings.Builder
+	for _, key := range sortedKeys(env) {
+		if !hclNamePattern.MatchString(key) {
+			return nil, fmt.Errorf("%w: '%s' (Terraform variable names must start with a letter or underscore)", ErrInvalidKey, key)
+		}
+
+		b.WriteString(key + " = " + quoteHCL(env[key]) + "\n")
+	}
+
+	return []byte(b.String()), nil
+}
+
+func quoteHCL(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			// ${ and %{ start template sequences; doubling the sigil escapes them
+			b.WriteRune(r)
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func quoteJSON(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// kubernetesName turns an application name into a valid resource name
+func kubernetesName(name string) string {
+	name = dnsInvalidChars.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-.")
+	if len(name) > 253 {
+		name = strings.Trim(name[:253], "-.")
+	}
+	return name
+}
+
+// mergeEnv combines variables and secrets, with secrets taking precedence
+func mergeEnv(variables, secrets map[string]string) map[string]string {
+	env := make(map[string]string, len(variables)+len(secrets))
+	for key, value := range variables {
+		env[key] = value
+	}
+	for key, value := range secrets {
+		env[key] = value
+	}
+	return env
+}
+
+func sortedKeys(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,134 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderQuoting(t *testing.T) {
+	newDelimiter = func() string { return "EOF" }
+
+	req := ExportRequest{
+		Name:      "My App",
+		Variables: map[string]string{"PORT": "8080", "GREETING": `it's "${HOME}"`},
+		Secrets:   map[string]string{"CERT": "line1\nline2"},
+	}
+
+	tests := []struct {
+		format Format
+		expect string
+	}{
+		{
+			format: FormatShell,
+			expect: "export CERT='line1\nline2'\nexport GREETING='it'\\''s \"${HOME}\"'\nexport PORT='8080'\n",
+		},
+		{
+			format: FormatSystemd,
+			expect: "CERT=\"line1\nline2\"\nGREETING=\"it's \\\"\\${HOME}\\\"\"\nPORT=\"8080\"\n",
+		},
+		{
+			format: FormatGitHub,
+			expect: "CERT<<EOF\nline1\nline2\nEOF\nGREETING=it's \"${HOME}\"\nPORT=8080\n",
+		},
+		{
+			format: FormatTerraform,
+			expect: "CERT = \"line1\\nline2\"\nGREETING = \"it's \\\"$${HOME}\\\"\"\nPORT = \"8080\"\n",
+		},
+		{
+			format: FormatJSON,
+			expect: "{\n  \"CERT\": \"line1\\nline2\",\n  \"GREETING\": \"it's \\\"${HOME}\\\"\",\n  \"PORT\": \"8080\"\n}\n",
+		},
+		{
+			format: FormatKubernetesSecret,
+			expect: "apiVersion: v1\nkind: Secret\nmetadata:\n  name: \"my-app\"\ntype: Opaque\ndata:\n" +
+				"  \"CERT\": \"bGluZTEKbGluZTI=\"\n  \"GREETING\": \"aXQncyAiJHtIT01FfSI=\"\n  \"PORT\": \"ODA4MA==\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			data, err := renderers[tt.format](req)
+			if err != nil {
+				t.Fatalf("render returned error: %v", err)
+			}
+			if string(data) != tt.expect {
+				t.Errorf("unexpected output:\n%s\nexpected:\n%s", data, tt.expect)
+			}
+		})
+	}
+}
+
+func TestRenderKubernetesSplitsSecrets(t *testing.T) {
+	data, err := renderKubernetes(ExportRequest{
+		Name:      "app",
+		Namespace: "prod",
+		Variables: map[string]string{"PORT": "8080"},
+		Secrets:   map[string]string{"TOKEN": "abc"},
+	})
+	if err != nil {
+		t.Fatalf("render returned error: %v", err)
+	}
+
+	expect := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: \"app\"\n  namespace: \"prod\"\ndata:\n  \"PORT\": \"8080\"\n" +
+		"---\n" +
+		"apiVersion: v1\nkind: Secret\nmetadata:\n  name: \"app\"\n  namespace: \"prod\"\ntype: Opaque\ndata:\n  \"TOKEN\": \"YWJj\"\n"
+	if string(data) != expect {
+		t.Errorf("unexpected output:\n%s", data)
+	}
+}
+
+func TestRenderRejectsUnrepresentableEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    Format
+		env       map[string]string
+		secrets   map[string]string
+		expectErr error
+	}{
+		{"docker multiline", FormatDocker, map[string]string{"CERT": "a\nb"}, nil, ErrInvalidValue},
+		{"shell key", FormatShell, map[string]string{"BAD-KEY": "x"}, nil, ErrInvalidKey},
+		{"kubernetes key", FormatKubernetesConfigMap, map[string]string{"BAD KEY": "x"}, nil, ErrInvalidKey},
+		{"configmap secrets", FormatKubernetesConfigMap, map[string]string{"PORT": "8080"}, map[string]string{"TOKEN": "abc"}, ErrSecretsInConfigMap},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := renderers[tt.format](ExportRequest{Name: "app", Variables: tt.env, Secrets: tt.secrets})
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("expected %v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestExportWritesPrivateFile(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "app.env")
+	uc := NewExportUseCase()
+
+	res, err := uc.Execute(context.Background(), ExportRequest{
+		Format:    FormatDocker,
+		Output:    output,
+		Variables: map[string]string{"PORT": "8080"},
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if res.Path != output || res.Count != 1 {
+		t.Errorf("unexpected response: %+v", res)
+	}
+
+	info, err := os.Stat(output)
+	if err != nil {
+		t.Fatalf("export file not written: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected mode 0600, got %o", info.Mode().Perm())
+	}
+
+	if _, err := uc.Execute(context.Background(), ExportRequest{Format: "yaml"}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package export
+
+import "context"
+
+type ReadEnvUseCase interface {
+	Execute(context.Context) (map[string]string, error)
+}
+
+type ExportUseCase interface {
+	Execute(context.Context, ExportRequest) (*ExportResponse, error)
+}
+
+type ExportRequest struct {
+	Format    Format
+	Output    string // If empty, the rendered data is only returned
+	Name      string // Resource name for Kubernetes formats
+	Namespace string // Optional namespace for Kubernetes formats
+	Variables map[string]string
+	Secrets   map[string]string
+}
+
+type ExportResponse struct {
+	Format Format
+	Data   []byte
+	Path   string
+	Count  int
+}
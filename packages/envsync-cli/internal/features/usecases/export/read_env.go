@@ -0,0 +1,32 @@
+package export
+
+import (
+	"context"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+type readEnvUseCase struct {
+	syncService services.SyncService
+}
+
+func NewReadEnvUseCase() ReadEnvUseCase {
+	syncService := services.NewSyncService()
+	return &readEnvUseCase{
+		syncService: syncService,
+	}
+}
+
+func (uc *readEnvUseCase) Execute(ctx context.Context) (map[string]string, error) {
+	remoteEnv, err := uc.syncService.ReadRemoteEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]string, len(remoteEnv))
+	for _, e := range remoteEnv {
+		env[e.Key] = e.Value
+	}
+
+	return env, nil
+}
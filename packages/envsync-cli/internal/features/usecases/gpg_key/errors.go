@@ -11,6 +11,9 @@ var (
 	ErrNameRequired     = errors.New("name is required")
 	ErrEmailRequired    = errors.New("email is required")
 	ErrNoInputProvided  = errors.New("no input provided (use --file or pipe via stdin)")
+	ErrInvalidKey       = errors.New("invalid PGP key")
+	ErrPassphraseRequired = errors.New("passphrase is required (use --passphrase-file or ENVSYNC_GPG_PASSPHRASE)")
+	ErrInvalidTrustLevel = errors.New("trust level must be one of unknown, never, marginal, full or ultimate")
 )
 
 type GpgKeyError struct {
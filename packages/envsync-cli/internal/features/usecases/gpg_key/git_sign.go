@@ -0,0 +1,216 @@
+package gpg_key
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository/requests"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+type gitSignUseCase struct {
+	service  services.GpgKeyService
+	keyring  services.KeyringService
+	verifier *verifyUseCase
+}
+
+func NewGitSignUseCase() GitSignUseCase {
+	service := services.NewGpgKeyService()
+	keyring := services.NewKeyringService()
+	return &gitSignUseCase{
+		service:  service,
+		keyring:  keyring,
+		verifier: &verifyUseCase{service: service, keyring: keyring},
+	}
+}
+
+// Sign creates a detached signature over data with the organization key
+// named by keyID, which git takes from user.signingkey. An empty keyID
+// selects the default key.
+func (uc *gitSignUseCase) Sign(ctx context.Context, keyID string, data []byte) (*domain.GitSignResult, error) {
+	key, err := signingKey(ctx, uc.service, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := uc.service.Sign(ctx, requests.SignDataRequest{
+		GpgKeyID: key.ID,
+		Data:     base64.StdEncoding.EncodeToString(data),
+		Mode:     "binary",
+		Detached: true,
+	})
+	if err != nil {
+		return nil, NewServiceError("failed to sign data", err)
+	}
+
+	sig, err := parseSignature([]byte(result.Signature))
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := strings.ToUpper(key.Fingerprint)
+	return &domain.GitSignResult{
+		Signature: strings.TrimRight(result.Signature, "\n") + "\n",
+		Status: []string{
+			"KEY_CONSIDERED " + fingerprint + " 2",
+			fmt.Sprintf("BEGIN_SIGNING H%d", hashID(sig.packet.Hash)),
+			fmt.Sprintf("SIG_CREATED D %d %d %02x %d %s",
+				sig.packet.PubKeyAlgo, hashID(sig.packet.Hash), sig.packet.SigType,
+				sig.packet.CreationTime.Unix(), fingerprint),
+		},
+		OK: true,
+	}, nil
+}
+
+// signingKey returns the active key matching keyID by ID, fingerprint, key
+// ID or email address, or the default key when keyID is empty.
+func signingKey(ctx context.Context, service services.GpgKeyService, keyID string) (*domain.GpgKey, error) {
+	keys, err := service.ListKeys(ctx)
+	if err != nil {
+		return nil, NewServiceError("failed to list GPG keys", err)
+	}
+
+	email := keyID
+	if start, end := strings.LastIndex(keyID, "<"), strings.LastIndex(keyID, ">"); start >= 0 && end > start {
+		email = keyID[start+1 : end]
+	}
+	for _, key := range keys {
+		if key.RevokedAt != nil {
+			continue
+		}
+		if keyID == "" && key.IsDefault || keyID != "" && (matchesKey(key, keyID) || strings.EqualFold(key.Email, email)) {
+			return &key, nil
+		}
+	}
+
+	if keyID == "" {
+		return nil, NewValidationError("no signing key given and the organization has no default GPG key", ErrKeyIDRequired)
+	}
+	return nil, NewNotFoundError("no active GPG key matches "+keyID, ErrKeyNotFound)
+}
+
+// Verify checks a detached signature over data against the local keyring and
+// reports the outcome with the status lines git parses for %G? and
+// log --show-signature.
+func (uc *gitSignUseCase) Verify(ctx context.Context, data, signature []byte) (*domain.GitSignResult, error) {
+	sig, err := parseSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	issuer := sig.issuer()
+	if len(issuer) > 16 {
+		issuer = issuer[len(issuer)-16:]
+	}
+
+	result, err := uc.verifier.verify(ctx, data, signature, "", false)
+	var keyErr *GpgKeyError
+	if errors.As(err, &keyErr) && keyErr.Code == GpgKeyErrorCodeNotFound {
+		return &domain.GitSignResult{
+			Status: []string{
+				"NEWSIG",
+				fmt.Sprintf("ERRSIG %s %d %d %02x %d 9 -", issuer,
+					sig.packet.PubKeyAlgo, hashID(sig.packet.Hash), sig.packet.SigType, sig.packet.CreationTime.Unix()),
+				"NO_PUBKEY " + issuer,
+			},
+			Message: "Can't check signature: No public key " + issuer,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if result.SignerFingerprint == nil {
+		return &domain.GitSignResult{
+			Status:  []string{"NEWSIG", "BADSIG " + issuer + " (unknown)"},
+			Message: "BAD signature: " + result.Reason,
+		}, nil
+	}
+
+	entry, err := uc.keyringEntry(*result.SignerFingerprint)
+	if err != nil {
+		return nil, err
+	}
+	keyID := strings.ToUpper(entry.KeyID)
+	uid := entry.Name + " <" + entry.Email + ">"
+	status := []string{"NEWSIG " + entry.Email}
+	out := &domain.GitSignResult{}
+
+	switch {
+	case entry.RevokedAt != nil:
+		status = append(status, "REVKEYSIG "+keyID+" "+uid)
+		out.Message = fmt.Sprintf("Good signature from %q, but the key was revoked", uid)
+	case entry.ExpiresAt != nil && !result.Valid:
+		status = append(status, "EXPKEYSIG "+keyID+" "+uid)
+		out.Message = fmt.Sprintf("Good signature from %q, made with an expired key", uid)
+	default:
+		fingerprint := strings.ToUpper(entry.Fingerprint)
+		status = append(status,
+			"GOODSIG "+keyID+" "+uid,
+			fmt.Sprintf("VALIDSIG %s %s %d 0 4 0 %d %d %02x %s", fingerprint,
+				result.SignedAt.UTC().Format("2006-01-02"), result.SignedAt.Unix(),
+				sig.packet.PubKeyAlgo, hashID(sig.packet.Hash), sig.packet.SigType, fingerprint),
+			trustStatus(entry.TrustLevel),
+		)
+		out.Message = fmt.Sprintf("Good signature from %q", uid)
+		out.OK = true
+	}
+	out.Status = status
+	return out, nil
+}
+
+func (uc *gitSignUseCase) keyringEntry(fingerprint string) (*domain.KeyringEntry, error) {
+	entries, err := uc.keyring.List()
+	if err != nil {
+		return nil, NewIOError("failed to read local keyring", err)
+	}
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Fingerprint, fingerprint) {
+			return &entry, nil
+		}
+	}
+	return nil, NewNotFoundError("signing key "+fingerprint+" is not in the local keyring", ErrKeyNotFound)
+}
+
+// trustStatus maps the trust level of an organization key to the GnuPG trust
+// status line.
+func trustStatus(level string) string {
+	switch level {
+	case "ultimate":
+		return "TRUST_ULTIMATE 0 pgp"
+	case "full":
+		return "TRUST_FULLY 0 pgp"
+	case "marginal":
+		return "TRUST_MARGINAL 0 pgp"
+	case "never":
+		return "TRUST_NEVER 0 pgp"
+	default:
+		return "TRUST_UNDEFINED 0 pgp"
+	}
+}
+
+// hashID returns the OpenPGP identifier of a hash algorithm (RFC 9580,
+// section 9.5).
+func hashID(h crypto.Hash) int {
+	switch h {
+	case crypto.SHA1:
+		return 2
+	case crypto.SHA256:
+		return 8
+	case crypto.SHA384:
+		return 9
+	case crypto.SHA512:
+		return 10
+	case crypto.SHA224:
+		return 11
+	case crypto.SHA3_256:
+		return 12
+	case crypto.SHA3_512:
+		return 14
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,52 @@
+package gpg_key
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGitSign(t *testing.T) {
+	ctx := context.Background()
+	key, err := NewGenerateKeyUseCase().Execute(ctx, "Dev", "dev@example.com", "ecc-curve25519", nil, nil, []string{"sign"}, false)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	commit := []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\nauthor Dev <dev@example.com> 1700000000 +0000\n\nInitial commit\n")
+	uc := NewGitSignUseCase()
+
+	signed, err := uc.Sign(ctx, "Dev <dev@example.com>", commit)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if !strings.HasPrefix(signed.Signature, "-----BEGIN PGP SIGNATURE-----") {
+		t.Errorf("signature is not armored: %q", signed.Signature)
+	}
+	// git checks for SIG_CREATED to tell that signing succeeded
+	created := signed.Status[len(signed.Status)-1]
+	if !strings.HasPrefix(created, "SIG_CREATED D ") || !strings.HasSuffix(created, " "+key.Fingerprint) {
+		t.Errorf("status = %q, want SIG_CREATED with the key fingerprint", created)
+	}
+
+	verified, err := uc.Verify(ctx, commit, []byte(signed.Signature))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	status := strings.Join(verified.Status, "\n")
+	if !verified.OK || !strings.Contains(status, "GOODSIG "+key.KeyID+" Dev <dev@example.com>") ||
+		!strings.Contains(status, "VALIDSIG "+key.Fingerprint) {
+		t.Errorf("Verify() = %+v, want a good signature", verified)
+	}
+
+	verified, err = uc.Verify(ctx, append(commit, "tampered"...), []byte(signed.Signature))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if verified.OK || !strings.Contains(strings.Join(verified.Status, "\n"), "BADSIG ") {
+		t.Errorf("Verify() of tampered commit = %+v, want a bad signature", verified)
+	}
+
+	if _, err := uc.Sign(ctx, "nobody@example.com", commit); err == nil {
+		t.Error("Sign() with an unknown key succeeded")
+	}
+}
@@ -0,0 +1,182 @@
+package gpg_key
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/presentation/tui/factory"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository/requests"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+type importKeyUseCase struct {
+	service services.GpgKeyService
+}
+
+func NewImportKeyUseCase() ImportKeyUseCase {
+	service := services.NewGpgKeyService()
+	return &importKeyUseCase{service: service}
+}
+
+// Execute imports an armored public key, a private key or both, as exported
+// by gpg --export --armor and gpg --export-secret-keys --armor. The public
+// key is derived from the private key when only that is given. An encrypted
+// private key is checked against the passphrase before upload; without one,
+// it is prompted for when interactive is set.
+func (uc *importKeyUseCase) Execute(ctx context.Context, name string, armored []byte, passphrase string, interactive bool) (*domain.GpgKey, error) {
+	publicArmor, privateArmor := splitKeyBlocks(armored)
+	if publicArmor == "" && privateArmor == "" {
+		return nil, NewValidationError("no armored PGP key block found", ErrInvalidKey)
+	}
+
+	var entity *openpgp.Entity
+	if privateArmor != "" {
+		private, err := readSingleKey(privateArmor)
+		if err != nil {
+			return nil, err
+		}
+		if private.PrivateKey == nil {
+			return nil, NewValidationError("private key block holds no private key", ErrInvalidKey)
+		}
+		entity = private
+	}
+	if publicArmor != "" {
+		public, err := readSingleKey(publicArmor)
+		if err != nil {
+			return nil, err
+		}
+		if entity != nil && !bytes.Equal(public.PrimaryKey.Fingerprint, entity.PrimaryKey.Fingerprint) {
+			return nil, NewValidationError("public and private key blocks belong to different keys", ErrInvalidKey)
+		}
+		entity = public
+	} else {
+		var err error
+		if publicArmor, err = armorPublicKey(entity); err != nil {
+			return nil, NewValidationError("failed to derive public key", err)
+		}
+	}
+
+	userID := ""
+	if _, identity := entity.PrimarySelfSignature(); identity != nil {
+		userID = identity.Name
+		if name == "" {
+			name = identity.UserId.Name
+		}
+	}
+	if name == "" {
+		return nil, NewValidationError("name is required when the key has no user ID", ErrNameRequired)
+	}
+
+	req := requests.ImportGpgKeyRequest{
+		Name:             name,
+		ArmoredPublicKey: publicArmor,
+	}
+	if privateArmor != "" {
+		if err := uc.checkPassphrase(ctx, privateArmor, userID, &passphrase, interactive); err != nil {
+			return nil, err
+		}
+		req.ArmoredPrivateKey = &privateArmor
+		if passphrase != "" {
+			req.Passphrase = &passphrase
+		}
+	}
+
+	key, err := uc.service.ImportKey(ctx, req)
+	if err != nil {
+		return nil, NewServiceError("failed to import GPG key", err)
+	}
+
+	return &key, nil
+}
+
+// checkPassphrase decrypts a copy of the private key, prompting for the
+// passphrase if needed and allowed.
+func (uc *importKeyUseCase) checkPassphrase(ctx context.Context, privateArmor, userID string, passphrase *string, interactive bool) error {
+	private, err := readSingleKey(privateArmor)
+	if err != nil {
+		return err
+	}
+	if !private.PrivateKey.Encrypted {
+		return nil
+	}
+
+	if *passphrase == "" {
+		if !interactive {
+			return NewValidationError("private key is encrypted and no passphrase was given", ErrPassphraseRequired)
+		}
+		if *passphrase, err = factory.NewGpgKeyFactory().PromptPassphraseTUI(ctx, userID); err != nil {
+			return NewValidationError("passphrase prompt failed", err)
+		}
+	}
+
+	if err := private.DecryptPrivateKeys([]byte(*passphrase)); err != nil {
+		return NewValidationError("incorrect passphrase for the private key", err)
+	}
+	return nil
+}
+
+// splitKeyBlocks returns the armored public and private key blocks of data.
+func splitKeyBlocks(data []byte) (public, private string) {
+	const begin = "-----BEGIN PGP "
+	text := string(data)
+	for {
+		start := strings.Index(text, begin)
+		if start < 0 {
+			return public, private
+		}
+		text = text[start:]
+		header, _, _ := strings.Cut(text, "\n")
+		kind := strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(header, begin)), "-----")
+
+		end := strings.Index(text, "-----END PGP "+kind+"-----")
+		if end < 0 {
+			return public, private
+		}
+		end += len("-----END PGP " + kind + "-----")
+		block := text[:end] + "\n"
+		text = text[end:]
+
+		switch kind {
+		case "PUBLIC KEY BLOCK":
+			if public == "" {
+				public = block
+			}
+		case "PRIVATE KEY BLOCK":
+			if private == "" {
+				private = block
+			}
+		}
+	}
+}
+
+func readSingleKey(armored string) (*openpgp.Entity, error) {
+	keys, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+	if err != nil {
+		return nil, NewValidationError("invalid armored key", err)
+	}
+	if len(keys) != 1 {
+		return nil, NewValidationError("expected exactly one key per block", ErrInvalidKey)
+	}
+	return keys[0], nil
+}
+
+func armorPublicKey(entity *openpgp.Entity) (string, error) {
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := entity.Serialize(w); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	buf.WriteString("\n")
+	return buf.String(), nil
+}
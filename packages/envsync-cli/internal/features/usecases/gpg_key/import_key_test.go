@@ -0,0 +1,108 @@
+package gpg_key
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// newArmoredPrivateKey returns a new private key, encrypted with passphrase
+// unless it is empty, as exported by gpg --export-secret-keys --armor.
+func newArmoredPrivateKey(t *testing.T, name, email, passphrase string) (*openpgp.Entity, []byte) {
+	t.Helper()
+	entity, err := openpgp.NewEntity(name, "", email, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passphrase != "" {
+		if err := entity.EncryptPrivateKeys([]byte(passphrase), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.SerializePrivateWithoutSigning(w, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return entity, buf.Bytes()
+}
+
+func TestImportKey(t *testing.T) {
+	ctx := context.Background()
+	entity, armored := newArmoredPrivateKey(t, "Release Signing", "release@example.com", "s3cret")
+	uc := NewImportKeyUseCase()
+
+	_, err := uc.Execute(ctx, "", armored, "", false)
+	if !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("Execute() without passphrase error = %v, want %v", err, ErrPassphraseRequired)
+	}
+	var keyErr *GpgKeyError
+	if _, err := uc.Execute(ctx, "", armored, "wrong", false); !errors.As(err, &keyErr) || keyErr.Code != GpgKeyErrorCodeValidation {
+		t.Errorf("Execute() with a wrong passphrase error = %v, want a validation error", err)
+	}
+
+	key, err := uc.Execute(ctx, "", armored, "s3cret", false)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if want := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint); key.Fingerprint != want {
+		t.Errorf("fingerprint = %s, want %s", key.Fingerprint, want)
+	}
+	if key.Name != "Release Signing" || key.Email != "release@example.com" {
+		t.Errorf("key = %s <%s>, want the primary user ID", key.Name, key.Email)
+	}
+
+	updated, err := NewTrustKeyUseCase().Execute(ctx, key.ID, "full")
+	if err != nil {
+		t.Fatalf("trust Execute() error = %v", err)
+	}
+	if updated.TrustLevel != "full" {
+		t.Errorf("trust level = %s, want full", updated.TrustLevel)
+	}
+	if _, err := NewTrustKeyUseCase().Execute(ctx, key.ID, "complete"); !errors.Is(err, ErrInvalidTrustLevel) {
+		t.Errorf("trust Execute() with an invalid level error = %v", err)
+	}
+
+	shown, err := NewShowKeyUseCase().Execute(ctx, key.ID)
+	if err != nil {
+		t.Fatalf("show Execute() error = %v", err)
+	}
+	if shown.TrustLevel != "full" || shown.Fingerprint != key.Fingerprint {
+		t.Errorf("show Execute() = %+v, want the updated key", shown)
+	}
+}
+
+func TestImportPublicKey(t *testing.T) {
+	entity, _ := newArmoredPrivateKey(t, "Partner", "partner@example.com", "")
+	public, err := armorPublicKey(entity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The key block may be surrounded by other text, as in a mail
+	key, err := NewImportKeyUseCase().Execute(context.Background(), "Partner releases", []byte("Our key:\n\n"+public), "", false)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if key.Name != "Partner releases" {
+		t.Errorf("name = %s, want the given name", key.Name)
+	}
+
+	// Mismatching blocks are rejected
+	_, other := newArmoredPrivateKey(t, "Other", "other@example.com", "")
+	if _, err := NewImportKeyUseCase().Execute(context.Background(), "", append([]byte(public), other...), "", false); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Execute() with mismatching blocks error = %v, want %v", err, ErrInvalidKey)
+	}
+}
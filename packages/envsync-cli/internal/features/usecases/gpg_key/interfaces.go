@@ -19,7 +19,7 @@ type SignUseCase interface {
 }
 
 type VerifyUseCase interface {
-	Execute(ctx context.Context, filePath, signaturePath, keyID string) (*domain.GpgVerifyResult, error)
+	Execute(ctx context.Context, filePath, signaturePath, keyID string, refresh bool) (*domain.GpgVerifyResult, error)
 }
 
 type ExportUseCase interface {
@@ -33,3 +33,44 @@ type RevokeUseCase interface {
 type DeleteKeyUseCase interface {
 	Execute(ctx context.Context, keyID string) error
 }
+
+type RefreshKeyringUseCase interface {
+	Execute(ctx context.Context, keyID string) ([]domain.KeyringEntry, error)
+}
+
+type ListKeyringUseCase interface {
+	Execute(ctx context.Context) ([]domain.KeyringEntry, error)
+}
+
+type GitSignUseCase interface {
+	Sign(ctx context.Context, keyID string, data []byte) (*domain.GitSignResult, error)
+	Verify(ctx context.Context, data, signature []byte) (*domain.GitSignResult, error)
+}
+
+type ImportKeyUseCase interface {
+	Execute(ctx context.Context, name string, armored []byte, passphrase string, interactive bool) (*domain.GpgKey, error)
+}
+
+type ShowKeyUseCase interface {
+	Execute(ctx context.Context, keyID string) (*domain.GpgKey, error)
+}
+
+type TrustKeyUseCase interface {
+	Execute(ctx context.Context, keyID, level string) (*domain.GpgKey, error)
+}
+
+type SignManifestUseCase interface {
+	Execute(ctx context.Context, req SignManifestRequest) (*domain.ManifestResult, error)
+}
+
+type VerifyManifestUseCase interface {
+	Execute(ctx context.Context, manifestPath, signaturePath, keyID string, refresh bool) (*domain.ManifestResult, error)
+}
+
+type SignSnapshotUseCase interface {
+	Execute(ctx context.Context, keyID string, snapshot domain.EnvSnapshot, signaturePath string) (*domain.GpgKey, error)
+}
+
+type VerifySnapshotUseCase interface {
+	Execute(ctx context.Context, snapshot domain.EnvSnapshot, signaturePath, fingerprint string) (*domain.GpgVerifyResult, error)
+}
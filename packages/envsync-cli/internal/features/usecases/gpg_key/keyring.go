@@ -0,0 +1,86 @@
+package gpg_key
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+type refreshKeyringUseCase struct {
+	service services.GpgKeyService
+	keyring services.KeyringService
+}
+
+func NewRefreshKeyringUseCase() RefreshKeyringUseCase {
+	return &refreshKeyringUseCase{
+		service: services.NewGpgKeyService(),
+		keyring: services.NewKeyringService(),
+	}
+}
+
+// Execute fetches the public keys of the organization, with their current
+// revocation and expiry, into the local keyring.
+func (uc *refreshKeyringUseCase) Execute(ctx context.Context, keyID string) ([]domain.KeyringEntry, error) {
+	return refreshKeyring(ctx, uc.service, uc.keyring, keyID)
+}
+
+type listKeyringUseCase struct {
+	keyring services.KeyringService
+}
+
+func NewListKeyringUseCase() ListKeyringUseCase {
+	return &listKeyringUseCase{keyring: services.NewKeyringService()}
+}
+
+func (uc *listKeyringUseCase) Execute(ctx context.Context) ([]domain.KeyringEntry, error) {
+	entries, err := uc.keyring.List()
+	if err != nil {
+		return nil, NewIOError("failed to read local keyring", err)
+	}
+	return entries, nil
+}
+
+// refreshKeyring stores the public keys matching keyID, or all keys of the
+// organization, in keyring.
+func refreshKeyring(ctx context.Context, service services.GpgKeyService, keyring services.KeyringService, keyID string) ([]domain.KeyringEntry, error) {
+	keys, err := service.ListKeys(ctx)
+	if err != nil {
+		return nil, NewServiceError("failed to list GPG keys", err)
+	}
+
+	var entries []domain.KeyringEntry
+	for _, key := range keys {
+		if keyID != "" && !matchesKey(key, keyID) {
+			continue
+		}
+		publicKey, _, err := service.ExportKey(ctx, key.ID)
+		if err != nil {
+			return nil, NewServiceError("failed to export GPG key "+key.ID, err)
+		}
+		entry := domain.KeyringEntry{GpgKey: key, PublicKey: publicKey, FetchedAt: time.Now().UTC()}
+		if err := keyring.Save(entry); err != nil {
+			return nil, NewIOError("failed to update local keyring", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if keyID != "" && len(entries) == 0 {
+		return nil, NewNotFoundError("no GPG key matches "+keyID, ErrKeyNotFound)
+	}
+	return entries, nil
+}
+
+// matchesKey reports whether keyID is the ID, fingerprint or OpenPGP key ID
+// of key.
+func matchesKey(key domain.GpgKey, keyID string) bool {
+	if key.ID == keyID {
+		return true
+	}
+	keyID = strings.ToUpper(strings.TrimPrefix(keyID, "0x"))
+	fingerprint := strings.ToUpper(key.Fingerprint)
+	return keyID != "" && (fingerprint == keyID || strings.EqualFold(key.KeyID, keyID) ||
+		(len(keyID) >= 8 && strings.HasSuffix(fingerprint, keyID)))
+}
@@ -0,0 +1,116 @@
+package gpg_key
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+)
+
+// ManifestAlgorithm is a digest algorithm of checksum manifests.
+type ManifestAlgorithm string
+
+const (
+	ManifestSHA256 ManifestAlgorithm = "sha256"
+	ManifestSHA512 ManifestAlgorithm = "sha512"
+)
+
+// manifestName returns the conventional file name of a manifest, such as
+// SHA256SUMS.
+func (a ManifestAlgorithm) manifestName() string {
+	return strings.ToUpper(string(a)) + "SUMS"
+}
+
+func (a ManifestAlgorithm) newHash() (hash.Hash, error) {
+	switch a {
+	case ManifestSHA256:
+		return sha256.New(), nil
+	case ManifestSHA512:
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported digest algorithm %q (use sha256 or sha512)", a)
+	}
+}
+
+// digestFile streams the file at path through the hash of algorithm.
+func digestFile(path string, algorithm ManifestAlgorithm) (string, error) {
+	h, err := algorithm.newHash()
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// formatManifest writes entries in the format of sha256sum and sha512sum, so
+// the manifest can also be checked with sha256sum -c.
+func formatManifest(entries []domain.ManifestEntry) []byte {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		fmt.Fprintf(&buf, "%s  %s\n", entry.Digest, entry.Name)
+	}
+	return buf.Bytes()
+}
+
+// parseManifest reads the entries of a manifest written by formatManifest or
+// by sha256sum and sha512sum, and detects the algorithm from the digest
+// length.
+func parseManifest(data []byte) (ManifestAlgorithm, []domain.ManifestEntry, error) {
+	var algorithm ManifestAlgorithm
+	var entries []domain.ManifestEntry
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		digest, name, ok := strings.Cut(text, " ")
+		// A '*' marks files hashed in binary mode, which is the same on Unix
+		name = strings.TrimPrefix(strings.TrimPrefix(name, " "), "*")
+		if _, err := hex.DecodeString(digest); !ok || err != nil || name == "" {
+			return "", nil, fmt.Errorf("line %d: expected \"<digest>  <file>\"", line)
+		}
+
+		var lineAlgorithm ManifestAlgorithm
+		switch len(digest) {
+		case sha256.Size * 2:
+			lineAlgorithm = ManifestSHA256
+		case sha512.Size * 2:
+			lineAlgorithm = ManifestSHA512
+		default:
+			return "", nil, fmt.Errorf("line %d: unsupported digest length %d", line, len(digest))
+		}
+		if algorithm != "" && algorithm != lineAlgorithm {
+			return "", nil, fmt.Errorf("line %d: manifest mixes digest algorithms", line)
+		}
+		algorithm = lineAlgorithm
+
+		entries = append(entries, domain.ManifestEntry{Name: name, Digest: strings.ToLower(digest)})
+	}
+	if err := scanner.Err(); err != nil {
+		return "", nil, err
+	}
+	if len(entries) == 0 {
+		return "", nil, fmt.Errorf("manifest lists no files")
+	}
+	return algorithm, entries, nil
+}
@@ -0,0 +1,121 @@
+package gpg_key
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSignAndVerifyManifest(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := NewGenerateKeyUseCase().Execute(ctx, "Manifest", "manifest@example.com", "ecc-curve25519", nil, nil, []string{"sign"}, false)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	for _, clearsign := range []bool{false, true} {
+		dir := t.TempDir()
+		var files []string
+		for _, name := range []string{"app-linux.tar.gz", "app-darwin.tar.gz"} {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(name+" contents\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			files = append(files, path)
+		}
+
+		signed, err := NewSignManifestUseCase().Execute(ctx, SignManifestRequest{
+			KeyID:     key.ID,
+			Files:     append(files, filepath.Join(dir, "SHA256SUMS")),
+			Clearsign: clearsign,
+		})
+		if err != nil {
+			t.Fatalf("clearsign=%v: sign failed: %v", clearsign, err)
+		}
+		if len(signed.Entries) != 2 {
+			t.Fatalf("clearsign=%v: expected the manifest itself to be skipped, got %d entries", clearsign, len(signed.Entries))
+		}
+
+		manifest := signed.Manifest
+		if clearsign {
+			// A clearsigned manifest verifies on its own
+			manifest = signed.Signature
+		}
+		verifier := NewVerifyManifestUseCase()
+		result, err := verifier.Execute(ctx, manifest, "", "", false)
+		if err != nil {
+			t.Fatalf("clearsign=%v: verify failed: %v", clearsign, err)
+		}
+		if !result.Valid {
+			t.Fatalf("clearsign=%v: expected a valid manifest, got %+v", clearsign, result)
+		}
+
+		if err := os.WriteFile(files[0], []byte("tampered\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Remove(files[1]); err != nil {
+			t.Fatal(err)
+		}
+		result, err = verifier.Execute(ctx, manifest, "", "", false)
+		if err != nil {
+			t.Fatalf("clearsign=%v: verify failed: %v", clearsign, err)
+		}
+		if result.Valid || !result.Verification.Valid {
+			t.Fatalf("clearsign=%v: expected a valid signature over mismatching files, got %+v", clearsign, result)
+		}
+		statuses := map[string]string{}
+		for _, entry := range result.Entries {
+			statuses[entry.Name] = entry.Status
+		}
+		if statuses["app-linux.tar.gz"] != ManifestStatusFailed || statuses["app-darwin.tar.gz"] != ManifestStatusMissing {
+			t.Fatalf("clearsign=%v: unexpected statuses %v", clearsign, statuses)
+		}
+	}
+}
+
+func TestVerifyManifestRejectsTamperedManifest(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.tar.gz")
+	if err := os.WriteFile(file, []byte("contents\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := NewGenerateKeyUseCase().Execute(ctx, "Manifest", "manifest@example.com", "ecc-curve25519", nil, nil, []string{"sign"}, false)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	signed, err := NewSignManifestUseCase().Execute(ctx, SignManifestRequest{
+		KeyID:     key.ID,
+		Files:     []string{file},
+		Algorithm: ManifestSHA512,
+	})
+	if err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	if filepath.Base(signed.Manifest) != "SHA512SUMS" {
+		t.Fatalf("expected SHA512SUMS, got %s", signed.Manifest)
+	}
+
+	// Listing the digest of a different file must invalidate the signature
+	if err := os.WriteFile(file, []byte("replaced\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	digest, err := digestFile(file, ManifestSHA512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(signed.Manifest, []byte(digest+"  app.tar.gz\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewVerifyManifestUseCase().Execute(ctx, signed.Manifest, "", "", false)
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if result.Valid || result.Verification.Valid || len(result.Entries) != 0 {
+		t.Fatalf("expected the signature to be rejected before checking files, got %+v", result)
+	}
+}
@@ -0,0 +1,30 @@
+package gpg_key
+
+import (
+	"context"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+type showKeyUseCase struct {
+	service services.GpgKeyService
+}
+
+func NewShowKeyUseCase() ShowKeyUseCase {
+	service := services.NewGpgKeyService()
+	return &showKeyUseCase{service: service}
+}
+
+func (uc *showKeyUseCase) Execute(ctx context.Context, keyID string) (*domain.GpgKey, error) {
+	if keyID == "" {
+		return nil, NewValidationError("key ID is required", ErrKeyIDRequired)
+	}
+
+	key, err := uc.service.GetKey(ctx, keyID)
+	if err != nil {
+		return nil, NewServiceError("failed to get GPG key", err)
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,120 @@
+package gpg_key
+
+import (
+	"context"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository/requests"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/utils"
+)
+
+// SignManifestRequest describes the artifacts to list in a signed manifest.
+type SignManifestRequest struct {
+	KeyID     string
+	Files     []string
+	Algorithm ManifestAlgorithm
+	// Output is the manifest path, by default SHA256SUMS or SHA512SUMS next
+	// to the first file. The signature is written to Output + ".asc".
+	Output    string
+	Clearsign bool
+}
+
+type signManifestUseCase struct {
+	service services.GpgKeyService
+}
+
+func NewSignManifestUseCase() SignManifestUseCase {
+	service := services.NewGpgKeyService()
+	return &signManifestUseCase{service: service}
+}
+
+// Execute hashes every file without loading it into memory, writes the
+// manifest and signs only the manifest, so the artifacts never leave the
+// machine.
+func (uc *signManifestUseCase) Execute(ctx context.Context, req SignManifestRequest) (*domain.ManifestResult, error) {
+	if len(req.Files) == 0 {
+		return nil, NewValidationError("at least one file is required", ErrNoInputProvided)
+	}
+	if req.Algorithm == "" {
+		req.Algorithm = ManifestSHA256
+	}
+	if _, err := req.Algorithm.newHash(); err != nil {
+		return nil, NewValidationError(err.Error(), nil)
+	}
+	if req.Output == "" {
+		req.Output = filepath.Join(filepath.Dir(req.Files[0]), req.Algorithm.manifestName())
+	}
+
+	manifestPath, err := filepath.Abs(req.Output)
+	if err != nil {
+		return nil, NewIOError("invalid manifest path", err)
+	}
+	signaturePath := manifestPath + ".asc"
+	dir := filepath.Dir(manifestPath)
+
+	var entries []domain.ManifestEntry
+	seen := map[string]bool{manifestPath: true, signaturePath: true}
+	for _, file := range req.Files {
+		path, err := filepath.Abs(file)
+		if err != nil {
+			return nil, NewIOError("invalid file path "+file, err)
+		}
+		// Globs like dist/* match the manifest of a previous run
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil, NewIOError("invalid file path "+file, err)
+		}
+		digest, err := digestFile(path, req.Algorithm)
+		if err != nil {
+			return nil, NewIOError("failed to hash "+file, err)
+		}
+		entries = append(entries, domain.ManifestEntry{Name: filepath.ToSlash(name), Digest: digest})
+	}
+	if len(entries) == 0 {
+		return nil, NewValidationError("no files to list besides the manifest", ErrNoInputProvided)
+	}
+
+	key, err := signingKey(ctx, uc.service, req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := formatManifest(entries)
+	mode := "binary"
+	if req.Clearsign {
+		mode = "clearsign"
+	}
+	signature, err := uc.service.Sign(ctx, requests.SignDataRequest{
+		GpgKeyID: key.ID,
+		Data:     base64.StdEncoding.EncodeToString(manifest),
+		Mode:     mode,
+		Detached: !req.Clearsign,
+	})
+	if err != nil {
+		return nil, NewServiceError("failed to sign manifest", err)
+	}
+
+	if err := utils.WriteFileAtomic(manifestPath, manifest, 0o644); err != nil {
+		return nil, NewIOError("failed to write manifest", err)
+	}
+	if err := utils.WriteFileAtomic(signaturePath, []byte(strings.TrimRight(signature.Signature, "\n")+"\n"), 0o644); err != nil {
+		return nil, NewIOError("failed to write manifest signature", err)
+	}
+
+	return &domain.ManifestResult{
+		Manifest:  manifestPath,
+		Signature: signaturePath,
+		Algorithm: string(req.Algorithm),
+		Entries:   entries,
+		Valid:     true,
+	}, nil
+}
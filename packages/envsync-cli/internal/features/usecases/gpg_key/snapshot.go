@@ -0,0 +1,95 @@
+package gpg_key
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository/requests"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/utils"
+)
+
+type signSnapshotUseCase struct {
+	service services.GpgKeyService
+}
+
+func NewSignSnapshotUseCase() SignSnapshotUseCase {
+	service := services.NewGpgKeyService()
+	return &signSnapshotUseCase{service: service}
+}
+
+// Execute writes a detached signature over the canonical form of snapshot to
+// signaturePath, made with the key matching keyID or the default key.
+func (uc *signSnapshotUseCase) Execute(ctx context.Context, keyID string, snapshot domain.EnvSnapshot, signaturePath string) (*domain.GpgKey, error) {
+	key, err := signingKey(ctx, uc.service, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := uc.service.Sign(ctx, requests.SignDataRequest{
+		GpgKeyID: key.ID,
+		Data:     base64.StdEncoding.EncodeToString(snapshot.Canonical()),
+		Mode:     "binary",
+		Detached: true,
+	})
+	if err != nil {
+		return nil, NewServiceError("failed to sign environment snapshot", err)
+	}
+
+	if err := utils.WriteFileAtomic(signaturePath, []byte(strings.TrimRight(result.Signature, "\n")+"\n"), 0o644); err != nil {
+		return nil, NewIOError("failed to write snapshot signature", err)
+	}
+
+	return key, nil
+}
+
+type verifySnapshotUseCase struct {
+	verifier *verifyUseCase
+}
+
+func NewVerifySnapshotUseCase() VerifySnapshotUseCase {
+	return &verifySnapshotUseCase{
+		verifier: &verifyUseCase{
+			service: services.NewGpgKeyService(),
+			keyring: services.NewKeyringService(),
+		},
+	}
+}
+
+// Execute checks the signature at signaturePath over snapshot against the key
+// with the pinned fingerprint. Any outcome other than a valid signature by
+// that key is returned as an error, so callers can refuse to continue.
+func (uc *verifySnapshotUseCase) Execute(ctx context.Context, snapshot domain.EnvSnapshot, signaturePath, fingerprint string) (*domain.GpgVerifyResult, error) {
+	fingerprint = strings.ToUpper(strings.ReplaceAll(fingerprint, " ", ""))
+	if _, err := hex.DecodeString(fingerprint); err != nil || len(fingerprint) != 40 && len(fingerprint) != 64 {
+		return nil, NewValidationError("a full signer fingerprint is required to verify environment snapshots", ErrKeyIDRequired)
+	}
+
+	signature, err := os.ReadFile(signaturePath)
+	if err != nil {
+		return nil, NewIOError("failed to read snapshot signature", err)
+	}
+
+	result, err := uc.verifier.verify(ctx, snapshot.Canonical(), signature, fingerprint, false)
+	var keyErr *GpgKeyError
+	if errors.As(err, &keyErr) && keyErr.Code == GpgKeyErrorCodeNotFound {
+		return nil, NewValidationError("environment snapshot was not signed by the pinned key "+fingerprint, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if result.Valid && !strings.EqualFold(*result.SignerFingerprint, fingerprint) {
+		result.Valid = false
+		result.Reason = "signed by " + *result.SignerFingerprint + " instead of the pinned key"
+	}
+	if !result.Valid {
+		return nil, NewValidationError("environment snapshot signature is invalid: "+result.Reason, ErrVerifyFailed)
+	}
+
+	return result, nil
+}
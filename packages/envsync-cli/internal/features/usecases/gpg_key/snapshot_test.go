@@ -0,0 +1,69 @@
+package gpg_key
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+)
+
+func TestSignAndVerifySnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	generate := NewGenerateKeyUseCase()
+	key, err := generate.Execute(ctx, "Deploy", "deploy@example.com", "ecc-curve25519", nil, nil, []string{"sign"}, false)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	other, err := generate.Execute(ctx, "Other", "other@example.com", "ecc-curve25519", nil, nil, []string{"sign"}, false)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	snapshot := domain.EnvSnapshot{
+		AppID:     "app-1",
+		EnvTypeID: "production",
+		Variables: map[string]string{"DATABASE_URL": "postgres://db/app", "GREETING": "hello\nworld"},
+	}
+	signature := filepath.Join(t.TempDir(), ".env.sig")
+	if _, err := NewSignSnapshotUseCase().Execute(ctx, key.ID, snapshot, signature); err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+
+	verifier := NewVerifySnapshotUseCase()
+	result, err := verifier.Execute(ctx, snapshot, signature, key.Fingerprint)
+	if err != nil {
+		t.Fatalf("expected the snapshot to verify, got %v", err)
+	}
+	if *result.SignerFingerprint != key.Fingerprint {
+		t.Fatalf("expected signer %s, got %s", key.Fingerprint, *result.SignerFingerprint)
+	}
+
+	tampered := snapshot
+	tampered.Variables = map[string]string{"DATABASE_URL": "postgres://attacker/app", "GREETING": "hello\nworld"}
+	otherTarget := snapshot
+	otherTarget.EnvTypeID = "staging"
+
+	tests := []struct {
+		name        string
+		snapshot    domain.EnvSnapshot
+		fingerprint string
+		code        string
+	}{
+		{"tampered value", tampered, key.Fingerprint, GpgKeyErrorCodeValidation},
+		{"other environment", otherTarget, key.Fingerprint, GpgKeyErrorCodeValidation},
+		{"other pinned key", snapshot, other.Fingerprint, GpgKeyErrorCodeValidation},
+		{"short fingerprint", snapshot, key.KeyID, GpgKeyErrorCodeValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Execute(ctx, tt.snapshot, signature, tt.fingerprint)
+			var keyErr *GpgKeyError
+			if !errors.As(err, &keyErr) || keyErr.Code != tt.code {
+				t.Fatalf("expected a %s error, got %v", tt.code, err)
+			}
+		})
+	}
+}
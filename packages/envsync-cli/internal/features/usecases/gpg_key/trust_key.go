@@ -0,0 +1,38 @@
+package gpg_key
+
+import (
+	"context"
+	"slices"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+// trustLevels are the trust levels of organization keys, from lowest to
+// highest
+var trustLevels = []string{"unknown", "never", "marginal", "full", "ultimate"}
+
+type trustKeyUseCase struct {
+	service services.GpgKeyService
+}
+
+func NewTrustKeyUseCase() TrustKeyUseCase {
+	service := services.NewGpgKeyService()
+	return &trustKeyUseCase{service: service}
+}
+
+func (uc *trustKeyUseCase) Execute(ctx context.Context, keyID, level string) (*domain.GpgKey, error) {
+	if keyID == "" {
+		return nil, NewValidationError("key ID is required", ErrKeyIDRequired)
+	}
+	if !slices.Contains(trustLevels, level) {
+		return nil, NewValidationError("invalid trust level "+level, ErrInvalidTrustLevel)
+	}
+
+	key, err := uc.service.UpdateTrust(ctx, keyID, level)
+	if err != nil {
+		return nil, NewServiceError("failed to update GPG key trust level", err)
+	}
+
+	return &key, nil
+}
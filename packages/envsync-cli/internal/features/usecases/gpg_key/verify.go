@@ -1,26 +1,42 @@
 package gpg_key
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
 
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
-	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository/requests"
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
 )
 
 type verifyUseCase struct {
 	service services.GpgKeyService
+	keyring services.KeyringService
 }
 
 func NewVerifyUseCase() VerifyUseCase {
-	service := services.NewGpgKeyService()
-	return &verifyUseCase{service: service}
+	return &verifyUseCase{
+		service: services.NewGpgKeyService(),
+		keyring: services.NewKeyringService(),
+	}
 }
 
-func (uc *verifyUseCase) Execute(ctx context.Context, filePath, signaturePath, keyID string) (*domain.GpgVerifyResult, error) {
+// Execute verifies a detached signature over the file, or a clearsigned
+// file when signaturePath is empty, against the local keyring. Neither the
+// data nor the signature is sent to the API; the public key of the signer is
+// only fetched when it is missing from the keyring, or for every key when
+// refresh is set.
+func (uc *verifyUseCase) Execute(ctx context.Context, filePath, signaturePath, keyID string, refresh bool) (*domain.GpgVerifyResult, error) {
 	if filePath == "" {
 		return nil, NewValidationError("file path is required for verification", ErrFileNotFound)
 	}
@@ -30,32 +46,186 @@ func (uc *verifyUseCase) Execute(ctx context.Context, filePath, signaturePath, k
 		return nil, NewIOError("failed to read data file", err)
 	}
 
-	var signature string
+	signature := data
 	if signaturePath != "" {
-		sigData, err := os.ReadFile(signaturePath)
+		signature, err = os.ReadFile(signaturePath)
 		if err != nil {
 			return nil, NewIOError("failed to read signature file", err)
 		}
-		signature = strings.TrimSpace(string(sigData))
+	} else {
+		data = nil
 	}
 
-	encodedData := base64.StdEncoding.EncodeToString(data)
+	return uc.verify(ctx, data, signature, keyID, refresh)
+}
 
-	var keyIDPtr *string
-	if keyID != "" {
-		keyIDPtr = &keyID
+// verify checks signature over data, which is nil for a clearsigned message.
+func (uc *verifyUseCase) verify(ctx context.Context, data, signature []byte, keyID string, refresh bool) (*domain.GpgVerifyResult, error) {
+	sig, err := parseSignature(signature)
+	if err != nil {
+		return nil, err
 	}
 
-	req := requests.VerifySignatureRequest{
-		Data:      encodedData,
-		Signature: signature,
-		GpgKeyID:  keyIDPtr,
+	if refresh {
+		if _, err := refreshKeyring(ctx, uc.service, uc.keyring, keyID); err != nil {
+			return nil, err
+		}
 	}
 
-	result, err := uc.service.Verify(ctx, req)
+	entries, err := uc.keyring.List()
 	if err != nil {
-		return nil, NewServiceError("failed to verify signature", err)
+		return nil, NewIOError("failed to read local keyring", err)
+	}
+
+	if !refresh && len(sig.signers(entries, keyID)) == 0 {
+		// Unknown signers are looked up once; offline, the keyring must
+		// already contain the key.
+		if _, err := refreshKeyring(ctx, uc.service, uc.keyring, keyID); err != nil {
+			return nil, NewNotFoundError(fmt.Sprintf(
+				"signing key %s is not in the local keyring at %s and could not be fetched",
+				sig.issuer(), uc.keyring.Dir()), err)
+		}
+		if entries, err = uc.keyring.List(); err != nil {
+			return nil, NewIOError("failed to read local keyring", err)
+		}
 	}
 
-	return &result, nil
+	return verifySignature(entries, data, sig, keyID)
+}
+
+// signature is a parsed detached or clearsigned OpenPGP signature.
+type signature struct {
+	packet *packet.Signature
+	// raw holds the binary signature packets of a detached signature
+	raw []byte
+	// clear is set for clearsigned messages
+	clear *clearsign.Block
+}
+
+func parseSignature(data []byte) (*signature, error) {
+	sig := &signature{}
+	raw := data
+	if block, _ := clearsign.Decode(data); block != nil {
+		body, err := io.ReadAll(block.ArmoredSignature.Body)
+		if err != nil {
+			return nil, NewValidationError("invalid clearsigned message", err)
+		}
+		sig.clear, raw = block, body
+	} else if bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN ")) {
+		block, err := armor.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, NewValidationError("invalid armored signature", err)
+		}
+		if block.Type != openpgp.SignatureType {
+			return nil, NewValidationError("expected a detached or clearsigned signature, got "+block.Type, ErrVerifyFailed)
+		}
+		if raw, err = io.ReadAll(block.Body); err != nil {
+			return nil, NewValidationError("invalid armored signature", err)
+		}
+	}
+
+	p, err := packet.Read(bytes.NewReader(raw))
+	if err != nil {
+		return nil, NewValidationError("invalid signature", err)
+	}
+	s, ok := p.(*packet.Signature)
+	if !ok {
+		return nil, NewValidationError("expected a detached or clearsigned signature", ErrVerifyFailed)
+	}
+	sig.packet, sig.raw = s, raw
+	return sig, nil
+}
+
+// issuer returns the key ID the signature claims to be made by.
+func (s *signature) issuer() string {
+	if s.packet.IssuerKeyId != nil {
+		return fmt.Sprintf("%016X", *s.packet.IssuerKeyId)
+	}
+	if s.packet.IssuerFingerprint != nil {
+		return fmt.Sprintf("%X", s.packet.IssuerFingerprint)
+	}
+	return "(unknown)"
+}
+
+// signers returns the entries with a key that may have made the signature,
+// restricted to keyID when set.
+func (s *signature) signers(entries []domain.KeyringEntry, keyID string) []domain.KeyringEntry {
+	var signers []domain.KeyringEntry
+	for _, entry := range entries {
+		if keyID != "" && !matchesKey(entry.GpgKey, keyID) {
+			continue
+		}
+		keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(entry.PublicKey))
+		if err != nil {
+			continue
+		}
+		if s.packet.IssuerKeyId == nil || len(keyring.KeysById(*s.packet.IssuerKeyId)) > 0 {
+			signers = append(signers, entry)
+		}
+	}
+	return signers
+}
+
+// verifySignature checks sig over data against the public keys of entries and
+// their revocation and expiry at the time of signing.
+func verifySignature(entries []domain.KeyringEntry, data []byte, sig *signature, keyID string) (*domain.GpgVerifyResult, error) {
+	signed := data
+	if sig.clear != nil {
+		if data != nil && !bytes.Equal(normalizeText(data), normalizeText(sig.clear.Plaintext)) {
+			return &domain.GpgVerifyResult{Valid: false, Reason: "file does not match the clearsigned message"}, nil
+		}
+		signed = sig.clear.Bytes
+	} else if data == nil {
+		return nil, NewValidationError("a signature file is required unless the file is clearsigned", ErrVerifyFailed)
+	}
+
+	signers := sig.signers(entries, keyID)
+	if len(signers) == 0 {
+		return nil, NewNotFoundError("signing key "+sig.issuer()+" is not in the local keyring", ErrKeyNotFound)
+	}
+
+	signedAt := sig.packet.CreationTime
+	config := &packet.Config{Time: func() time.Time { return signedAt }}
+
+	var lastErr error
+	for _, entry := range signers {
+		keyring, _ := openpgp.ReadArmoredKeyRing(strings.NewReader(entry.PublicKey))
+		signer, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(signed), bytes.NewReader(sig.raw), config)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		// Callers pin keys by the reported fingerprint, so it must be the one
+		// of the key that checked the signature and not only what the API says
+		if !strings.EqualFold(hex.EncodeToString(signer.PrimaryKey.Fingerprint), entry.Fingerprint) {
+			lastErr = fmt.Errorf("public key of %s does not match its fingerprint", entry.Fingerprint)
+			continue
+		}
+
+		result := &domain.GpgVerifyResult{
+			Valid:             true,
+			SignerFingerprint: &entry.Fingerprint,
+			SignerKeyID:       &entry.KeyID,
+			SignerName:        entry.Name,
+			SignedAt:          &signedAt,
+		}
+		switch {
+		case entry.RevokedAt != nil:
+			result.Valid = false
+			result.Reason = "signing key was revoked at " + entry.RevokedAt.Format(time.RFC3339)
+		case entry.ExpiresAt != nil && signedAt.After(*entry.ExpiresAt):
+			result.Valid = false
+			result.Reason = "signing key expired at " + entry.ExpiresAt.Format(time.RFC3339)
+		}
+		return result, nil
+	}
+
+	return &domain.GpgVerifyResult{Valid: false, Reason: lastErr.Error()}, nil
+}
+
+// normalizeText makes clearsigned plaintext comparable with the file it was
+// made from, whose line endings and trailing newline are not preserved.
+func normalizeText(data []byte) []byte {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	return bytes.TrimRight(data, "\n")
 }
@@ -0,0 +1,96 @@
+package gpg_key
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+const (
+	ManifestStatusOK      = "OK"
+	ManifestStatusFailed  = "FAILED"
+	ManifestStatusMissing = "MISSING"
+)
+
+type verifyManifestUseCase struct {
+	verifier *verifyUseCase
+}
+
+func NewVerifyManifestUseCase() VerifyManifestUseCase {
+	return &verifyManifestUseCase{
+		verifier: &verifyUseCase{
+			service: services.NewGpgKeyService(),
+			keyring: services.NewKeyringService(),
+		},
+	}
+}
+
+// Execute verifies the signature of a manifest against the local keyring and
+// then the digest of every file it lists, relative to the manifest. A
+// clearsigned manifest needs no signature file; otherwise signaturePath
+// defaults to the manifest path with ".asc" appended. Digests are only
+// checked once the signature is valid.
+func (uc *verifyManifestUseCase) Execute(ctx context.Context, manifestPath, signaturePath, keyID string, refresh bool) (*domain.ManifestResult, error) {
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, NewIOError("failed to read manifest", err)
+	}
+
+	manifest, data, signature := content, content, content
+	if sig, _ := parseSignature(content); sig != nil && sig.clear != nil {
+		manifest, data, signaturePath = sig.clear.Plaintext, nil, ""
+	} else {
+		if signaturePath == "" {
+			signaturePath = manifestPath + ".asc"
+		}
+		if signature, err = os.ReadFile(signaturePath); err != nil {
+			return nil, NewIOError("failed to read manifest signature", err)
+		}
+	}
+
+	verification, err := uc.verifier.verify(ctx, data, signature, keyID, refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ManifestResult{
+		Manifest:     manifestPath,
+		Signature:    signaturePath,
+		Verification: verification,
+	}
+	if !verification.Valid {
+		return result, nil
+	}
+
+	algorithm, entries, err := parseManifest(manifest)
+	if err != nil {
+		return nil, NewValidationError("invalid manifest", err)
+	}
+	result.Algorithm = string(algorithm)
+	result.Valid = true
+
+	dir := filepath.Dir(manifestPath)
+	for i, entry := range entries {
+		digest, err := digestFile(filepath.Join(dir, filepath.FromSlash(entry.Name)), algorithm)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			entries[i].Status = ManifestStatusMissing
+		case err != nil:
+			return nil, NewIOError("failed to hash "+entry.Name, err)
+		case digest != entry.Digest:
+			entries[i].Status = ManifestStatusFailed
+		default:
+			entries[i].Status = ManifestStatusOK
+		}
+		if entries[i].Status != ManifestStatusOK {
+			result.Valid = false
+		}
+	}
+	result.Entries = entries
+
+	return result, nil
+}
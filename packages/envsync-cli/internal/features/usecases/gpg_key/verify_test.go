@@ -0,0 +1,152 @@
+package gpg_key
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/testutil"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/envsynctest"
+)
+
+// server is shared by all tests in the package because the CLI configuration,
+// and with it the backend URL, is only loaded once per process.
+var server *envsynctest.Server
+
+func TestMain(m *testing.M) {
+	server = envsynctest.NewServer()
+
+	dir, err := testutil.UseServer(server.URL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to configure fake server: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	server.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// signTestFile generates a key and signs a new file with it through the API,
+// returning the key, the file and the signature paths.
+func signTestFile(t *testing.T, mode string) (*domain.GpgKey, string, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	key, err := NewGenerateKeyUseCase().Execute(ctx, "Release", "release@example.com", "ecc-curve25519", nil, nil, []string{"sign"}, false)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "release.tar.gz")
+	if err := os.WriteFile(file, []byte("release contents\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewSignUseCase().Execute(ctx, key.ID, file, mode, mode != "clearsign", false)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	signature := filepath.Join(dir, "release.tar.gz.asc")
+	if err := os.WriteFile(signature, []byte(result.Signature), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return key, file, signature
+}
+
+func TestVerifyLocally(t *testing.T) {
+	key, file, signature := signTestFile(t, "binary")
+	server.ResetRequests()
+
+	result, err := NewVerifyUseCase().Execute(context.Background(), file, signature, "", false)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("signature is invalid: %s", result.Reason)
+	}
+	if result.SignerFingerprint == nil || *result.SignerFingerprint != key.Fingerprint {
+		t.Errorf("signer = %v, want %s", result.SignerFingerprint, key.Fingerprint)
+	}
+	if result.SignedAt == nil {
+		t.Error("signing time is not set")
+	}
+
+	for _, req := range server.Requests() {
+		if strings.HasSuffix(req.Path, "/verify") {
+			t.Errorf("signature was sent to the API: %s %s", req.Method, req.Path)
+		}
+	}
+
+	// The key is cached now, so tampering is detected without the API
+	server.Inject(envsynctest.Fault{StatusCode: http.StatusServiceUnavailable})
+	t.Cleanup(server.ClearFaults)
+
+	if err := os.WriteFile(file, []byte("tampered contents\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err = NewVerifyUseCase().Execute(context.Background(), file, signature, key.Fingerprint, false)
+	if err != nil {
+		t.Fatalf("Execute() offline error = %v", err)
+	}
+	if result.Valid {
+		t.Error("tampered file has a valid signature")
+	}
+}
+
+func TestVerifyClearsigned(t *testing.T) {
+	_, file, signature := signTestFile(t, "clearsign")
+
+	// A clearsigned file verifies on its own and against the original
+	for _, sig := range []string{"", signature} {
+		path := file
+		if sig == "" {
+			path = signature
+		}
+		result, err := NewVerifyUseCase().Execute(context.Background(), path, sig, "", false)
+		if err != nil {
+			t.Fatalf("Execute(%q, %q) error = %v", path, sig, err)
+		}
+		if !result.Valid {
+			t.Errorf("Execute(%q, %q) is invalid: %s", path, sig, result.Reason)
+		}
+	}
+}
+
+func TestVerifyRevokedKey(t *testing.T) {
+	key, file, signature := signTestFile(t, "binary")
+	ctx := context.Background()
+
+	if _, err := NewVerifyUseCase().Execute(ctx, file, signature, "", false); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if _, err := NewRevokeUseCase().Execute(ctx, key.ID, "compromised"); err != nil {
+		t.Fatalf("failed to revoke key: %v", err)
+	}
+
+	// The cached key does not know about the revocation until refreshed
+	result, err := NewVerifyUseCase().Execute(ctx, file, signature, "", true)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Valid || !strings.Contains(result.Reason, "revoked") {
+		t.Errorf("result = %+v, want invalid because of the revocation", result)
+	}
+
+	entries, err := NewListKeyringUseCase().Execute(ctx)
+	if err != nil {
+		t.Fatalf("failed to list keyring: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.ID == key.ID && entry.RevokedAt == nil {
+			t.Error("keyring entry is not marked as revoked")
+		}
+	}
+}
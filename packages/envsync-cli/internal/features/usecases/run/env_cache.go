@@ -0,0 +1,53 @@
+package run
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+type envCacheUseCase struct {
+	cacheService services.EnvCacheService
+}
+
+func NewEnvCacheUseCase() EnvCacheUseCase {
+	cacheService := services.NewEnvCacheService()
+	return &envCacheUseCase{
+		cacheService: cacheService,
+	}
+}
+
+func (uc *envCacheUseCase) Save(ctx context.Context, appID, envTypeID string, variables, secrets map[string]string) error {
+	now := time.Now()
+
+	entry := domain.EnvCache{
+		AppID:     appID,
+		EnvTypeID: envTypeID,
+		Variables: variables,
+		Secrets:   secrets,
+		FetchedAt: now,
+	}
+	if secrets != nil {
+		entry.SecretsFetchedAt = now
+	}
+
+	return uc.cacheService.Save(entry)
+}
+
+func (uc *envCacheUseCase) Restore(ctx context.Context, appID, envTypeID string) (*domain.EnvCache, error) {
+	entry, err := uc.cacheService.Load(appID, envTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range entry.Merged() {
+		if err := os.Setenv(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,22 @@
+package run
+
+import (
+	"errors"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+// IsBackendUnavailable reports whether err means the EnvSync API could not be
+// reached, so the cached environment may be used instead.
+func IsBackendUnavailable(err error) bool {
+	return services.IsBackendUnavailable(err)
+}
+
+// Process configuration errors
+var (
+	ErrNoProcesses          = errors.New("no processes defined")
+	ErrInvalidProcfileEntry = errors.New("invalid Procfile entry")
+	ErrDuplicateProcess     = errors.New("process is defined more than once")
+	ErrEmptyCommand         = errors.New("process has no command")
+	ErrInvalidRestartPolicy = errors.New("invalid restart policy (supported: no, on-failure, always)")
+)
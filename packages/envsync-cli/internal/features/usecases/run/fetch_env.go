@@ -0,0 +1,29 @@
+package run
+
+import (
+	"context"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+type fetchEnvUseCase struct{}
+
+func NewFetchEnvUseCase() FetchEnvUseCase {
+	return &fetchEnvUseCase{}
+}
+
+// Execute reads the variables of any environment type of the app. Unlike
+// InjectEnvUseCase it leaves the process environment untouched.
+func (uc *fetchEnvUseCase) Execute(ctx context.Context, appID, envTypeID string) (map[string]string, error) {
+	remoteEnv, err := services.NewSyncServiceForTarget(appID, envTypeID).ReadRemoteEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]string, len(remoteEnv))
+	for _, e := range remoteEnv {
+		env[e.Key] = e.Value
+	}
+
+	return env, nil
+}
@@ -2,6 +2,8 @@ package run
 
 import (
 	"context"
+	"os"
+	"time"
 
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
 )
@@ -24,4 +26,60 @@ type InjectSecretsUseCase interface {
 
 type RedactUseCase interface {
 	Execute(context.Context, []string, map[string]string) int
+	Supervise(context.Context, []string, map[string]string, <-chan Reload) int
+}
+
+type InterpolateEnvUseCase interface {
+	Execute(context.Context, string, map[string]string, map[string]string, bool) (map[string]string, map[string]string, []string, error)
+}
+
+type FetchEnvUseCase interface {
+	Execute(context.Context, string, string) (map[string]string, error)
+}
+
+type LoadProcessesUseCase interface {
+	Execute(context.Context, string, map[string]domain.Process) ([]domain.Process, error)
+}
+
+type SuperviseProcessesUseCase interface {
+	Execute(context.Context, []ProcessSpec) int
+}
+
+type EnvCacheUseCase interface {
+	Save(context.Context, string, string, map[string]string, map[string]string) error
+	Restore(context.Context, string, string) (*domain.EnvCache, error)
+}
+
+type WatchChangesUseCase interface {
+	Execute(context.Context, WatchOptions) (<-chan struct{}, error)
+}
+
+// WatchOptions configures how the remote environment is polled for changes
+type WatchOptions struct {
+	AppID          string
+	EnvTypeID      string
+	IncludeSecrets bool
+	Interval       time.Duration
+	Debounce       time.Duration
+}
+
+// Reload asks a supervised command to pick up a new environment. When Signal
+// is nil the command is restarted, otherwise Signal is delivered to it and
+// only the redaction set is refreshed.
+type Reload struct {
+	Env    map[string]string
+	Signal os.Signal
+}
+
+// ProcessSpec describes one process supervised alongside others. Env is the
+// complete environment of the process and Redact the values hidden from its
+// output.
+type ProcessSpec struct {
+	Name        string
+	Command     string
+	Dir         string
+	Env         []string
+	Redact      map[string]string
+	Restart     domain.RestartPolicy
+	MaxRestarts int
 }
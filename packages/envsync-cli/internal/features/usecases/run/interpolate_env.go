@@ -0,0 +1,26 @@
+package run
+
+import (
+	"context"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+type interpolateEnvUseCase struct {
+	interpolationService services.InterpolationService
+}
+
+func NewInterpolateEnvUseCase() InterpolateEnvUseCase {
+	interpolationService := services.NewInterpolationService()
+	return &interpolateEnvUseCase{
+		interpolationService: interpolationService,
+	}
+}
+
+// Execute expands references between the variables and secrets. Variables
+// built from a secret are returned with the secrets so they stay protected.
+// References that cannot be resolved are kept verbatim and returned, unless
+// strict makes them errors.
+func (uc *interpolateEnvUseCase) Execute(ctx context.Context, appID string, variables, secrets map[string]string, strict bool) (map[string]string, map[string]string, []string, error) {
+	return uc.interpolationService.Resolve(ctx, appID, variables, secrets, strict)
+}
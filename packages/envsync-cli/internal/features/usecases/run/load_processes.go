@@ -0,0 +1,112 @@
+package run
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+)
+
+var procfileLinePattern = regexp.MustCompile(`^([A-Za-z0-9_-]+):\s*(.+)$`)
+
+type loadProcessesUseCase struct{}
+
+func NewLoadProcessesUseCase() LoadProcessesUseCase {
+	return &loadProcessesUseCase{}
+}
+
+// Execute returns the processes to run. Entries of the Procfile keep their
+// order and take their settings from the [processes] entry of the same name;
+// without a Procfile the [processes] table is used on its own.
+func (uc *loadProcessesUseCase) Execute(ctx context.Context, procfilePath string, configured map[string]domain.Process) ([]domain.Process, error) {
+	var processes []domain.Process
+	if procfilePath != "" {
+		file, err := os.Open(procfilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Procfile: %w", err)
+		}
+		defer file.Close()
+
+		processes, err = parseProcfile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, p := range processes {
+			if settings, ok := configured[p.Name]; ok {
+				settings.Name = p.Name
+				settings.Command = p.Command
+				processes[i] = settings
+			}
+		}
+	} else {
+		names := make([]string, 0, len(configured))
+		for name := range configured {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			p := configured[name]
+			p.Name = name
+			processes = append(processes, p)
+		}
+	}
+
+	if len(processes) == 0 {
+		return nil, ErrNoProcesses
+	}
+
+	for _, p := range processes {
+		if strings.TrimSpace(p.Command) == "" {
+			return nil, fmt.Errorf("%w: '%s'", ErrEmptyCommand, p.Name)
+		}
+		if !p.Restart.Valid() {
+			return nil, fmt.Errorf("%w: '%s' for '%s'", ErrInvalidRestartPolicy, p.Restart, p.Name)
+		}
+	}
+
+	return processes, nil
+}
+
+// parseProcfile reads "name: command" lines, skipping blank lines and comments
+func parseProcfile(r io.Reader) ([]domain.Process, error) {
+	var processes []domain.Process
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		match := procfileLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("%w on line %d: expected 'name: command'", ErrInvalidProcfileEntry, lineNo)
+		}
+
+		name := match[1]
+		if seen[name] {
+			return nil, fmt.Errorf("%w: '%s'", ErrDuplicateProcess, name)
+		}
+		seen[name] = true
+
+		processes = append(processes, domain.Process{
+			Name:    name,
+			Command: strings.TrimSpace(match[2]),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Procfile: %w", err)
+	}
+
+	return processes, nil
+}
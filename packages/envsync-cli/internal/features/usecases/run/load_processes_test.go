@@ -0,0 +1,120 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+)
+
+func TestParseProcfile(t *testing.T) {
+	procfile := `
+# Local stack
+api: npm run api -- --port $PORT
+worker:   bundle exec sidekiq
+
+web: npm run dev
+`
+
+	processes, err := parseProcfile(strings.NewReader(procfile))
+	if err != nil {
+		t.Fatalf("parseProcfile returned error: %v", err)
+	}
+
+	expect := []domain.Process{
+		{Name: "api", Command: "npm run api -- --port $PORT"},
+		{Name: "worker", Command: "bundle exec sidekiq"},
+		{Name: "web", Command: "npm run dev"},
+	}
+	if len(processes) != len(expect) {
+		t.Fatalf("expected %d processes, got %d", len(expect), len(processes))
+	}
+	for i, p := range processes {
+		if p.Name != expect[i].Name || p.Command != expect[i].Command {
+			t.Errorf("process %d: expected %+v, got %+v", i, expect[i], p)
+		}
+	}
+}
+
+func TestParseProcfileErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		procfile  string
+		expectErr error
+	}{
+		{"missing command", "api:\n", ErrInvalidProcfileEntry},
+		{"invalid name", "my api: npm start\n", ErrInvalidProcfileEntry},
+		{"duplicate", "api: npm start\napi: npm run dev\n", ErrDuplicateProcess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseProcfile(strings.NewReader(tt.procfile)); !errors.Is(err, tt.expectErr) {
+				t.Errorf("expected %v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadProcesses(t *testing.T) {
+	procfile := filepath.Join(t.TempDir(), "Procfile.dev")
+	if err := os.WriteFile(procfile, []byte("web: npm run dev\napi: npm run api\n"), 0o644); err != nil {
+		t.Fatalf("failed to write Procfile: %v", err)
+	}
+
+	configured := map[string]domain.Process{
+		"api": {
+			Command:   "ignored",
+			EnvTypeID: "staging",
+			Env:       map[string]string{"PORT": "3000"},
+			Restart:   domain.RestartOnFailure,
+		},
+		"worker": {Command: "node worker.js", Restart: domain.RestartAlways},
+	}
+
+	uc := NewLoadProcessesUseCase()
+
+	t.Run("procfile with settings", func(t *testing.T) {
+		processes, err := uc.Execute(context.Background(), procfile, configured)
+		if err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+		if len(processes) != 2 || processes[0].Name != "web" || processes[1].Name != "api" {
+			t.Fatalf("unexpected processes: %+v", processes)
+		}
+
+		api := processes[1]
+		if api.Command != "npm run api" || api.EnvTypeID != "staging" || api.Env["PORT"] != "3000" || api.Restart != domain.RestartOnFailure {
+			t.Errorf("Procfile entry did not pick up its settings: %+v", api)
+		}
+	})
+
+	t.Run("config table only", func(t *testing.T) {
+		processes, err := uc.Execute(context.Background(), "", configured)
+		if err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+		if len(processes) != 2 || processes[0].Name != "api" || processes[1].Name != "worker" {
+			t.Errorf("expected processes sorted by name, got %+v", processes)
+		}
+	})
+
+	t.Run("invalid restart policy", func(t *testing.T) {
+		_, err := uc.Execute(context.Background(), "", map[string]domain.Process{
+			"api": {Command: "npm start", Restart: "sometimes"},
+		})
+		if !errors.Is(err, ErrInvalidRestartPolicy) {
+			t.Errorf("expected ErrInvalidRestartPolicy, got %v", err)
+		}
+	})
+
+	t.Run("no processes", func(t *testing.T) {
+		if _, err := uc.Execute(context.Background(), "", nil); !errors.Is(err, ErrNoProcesses) {
+			t.Errorf("expected ErrNoProcesses, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/presentation/style"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/utils"
+)
+
+const (
+	// restartDelay is the initial wait before a process is restarted; it
+	// doubles on every restart up to maxRestartDelay
+	restartDelay    = time.Second
+	maxRestartDelay = 30 * time.Second
+
+	// restartResetAfter is how long a process must stay up for the restart
+	// delay to start over
+	restartResetAfter = 10 * time.Second
+)
+
+// processColors are cycled through to tell process output apart
+var processColors = []lipgloss.Color{
+	style.InfoColor,
+	style.PrimaryColor,
+	style.AccentColor,
+	style.SecondaryColor,
+	style.SuccessColor,
+	style.ErrorColor,
+}
+
+type superviseProcessesUseCase struct {
+	redactor *redactUseCase
+}
+
+func NewSuperviseProcessesUseCase() SuperviseProcessesUseCase {
+	return &superviseProcessesUseCase{
+		redactor: &redactUseCase{},
+	}
+}
+
+type processExit struct {
+	name     string
+	exitCode int
+}
+
+// Execute runs all processes side by side with prefixed output. Once any
+// process exits for good, or on an interrupt, the others are stopped. It
+// returns the exit code of the process that ended the run.
+func (uc *superviseProcessesUseCase) Execute(ctx context.Context, specs []ProcessSpec) int {
+	if len(specs) == 0 {
+		fmt.Fprintf(os.Stderr, "No processes provided\n")
+		return 1
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	width := 0
+	for _, spec := range specs {
+		width = max(width, len(spec.Name))
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	exited := make(chan processExit, len(specs))
+	for i, spec := range specs {
+		label := lipgloss.NewStyle().
+			Foreground(processColors[i%len(processColors)]).
+			Render(fmt.Sprintf("%-*s |", width, spec.Name))
+		out := &prefixWriter{mu: &mu, out: os.Stdout, prefix: label + " "}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			exited <- uc.superviseProcess(runCtx, spec, out)
+		}()
+	}
+
+	exitCode := 0
+	select {
+	case exit := <-exited:
+		exitCode = exit.exitCode
+		fmt.Fprintf(os.Stderr, "\n%s has exited, stopping all processes...\n", exit.name)
+	case <-sigChan:
+		fmt.Fprintf(os.Stderr, "\nReceived interrupt signal, stopping all processes...\n")
+	case <-ctx.Done():
+		fmt.Fprintf(os.Stderr, "\nStopping all processes...\n")
+	}
+
+	cancel()
+	wg.Wait()
+
+	return exitCode
+}
+
+// superviseProcess runs a single process, restarting it as its policy allows,
+// until it exits for good or ctx is cancelled
+func (uc *superviseProcessesUseCase) superviseProcess(ctx context.Context, spec ProcessSpec, out *prefixWriter) processExit {
+	args := utils.ShellArgs(spec.Command)
+	delay := restartDelay
+	restarts := 0
+
+	for {
+		// Output is read until the child is stopped, not until ctx is
+		// cancelled, so nothing printed while shutting down is lost
+		started := time.Now()
+		child, err := uc.redactor.startChild(context.Background(), childSpec{
+			args:   args,
+			env:    spec.Env,
+			dir:    spec.Dir,
+			redact: spec.Redact,
+			out:    out,
+		}, nil)
+		if err != nil {
+			out.Printf("failed to start: %v", err)
+			return processExit{name: spec.Name, exitCode: 1}
+		}
+		out.Printf("started with pid %d", child.cmd.Process.Pid)
+
+		var exitCode int
+		select {
+		case exitCode = <-child.done:
+			child.close()
+			out.Flush()
+		case <-ctx.Done():
+			exitCode = child.stop(syscall.SIGTERM, restartGracePeriod)
+			out.Flush()
+			out.Printf("stopped")
+			return processExit{name: spec.Name, exitCode: exitCode}
+		}
+
+		if !spec.Restart.ShouldRestart(exitCode) || (spec.MaxRestarts > 0 && restarts >= spec.MaxRestarts) {
+			out.Printf("exited with code %d", exitCode)
+			return processExit{name: spec.Name, exitCode: exitCode}
+		}
+
+		if time.Since(started) >= restartResetAfter {
+			delay = restartDelay
+		}
+		restarts++
+		out.Printf("exited with code %d, restarting in %s", exitCode, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return processExit{name: spec.Name, exitCode: exitCode}
+		}
+		delay = min(delay*2, maxRestartDelay)
+	}
+}
+
+// prefixWriter writes complete lines prefixed with the process label. Writers
+// of different processes share mu so their lines never interleave.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush writes a trailing line that was not terminated by a newline
+func (w *prefixWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		_ = w.writeLine(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+// Printf writes a status message about the process
+func (w *prefixWriter) Printf(format string, args ...any) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	message := lipgloss.NewStyle().Foreground(style.MutedColor).Render(fmt.Sprintf(format, args...))
+	_ = w.writeLine([]byte(message + "\n"))
+}
+
+// writeLine must be called with mu held
+func (w *prefixWriter) writeLine(line []byte) error {
+	_, err := io.WriteString(w.out, w.prefix+string(line))
+	return err
+}
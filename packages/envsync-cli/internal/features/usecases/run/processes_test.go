@@ -0,0 +1,95 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &prefixWriter{mu: &sync.Mutex{}, out: &buf, prefix: "api | "}
+
+	w.Write([]byte("first\nsec"))
+	w.Write([]byte("ond\r\nthird"))
+	w.Flush()
+
+	expect := "api | first\napi | second\r\napi | third\n"
+	if buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+}
+
+func TestSuperviseProcessRestartsWithPolicy(t *testing.T) {
+	var buf bytes.Buffer
+	out := &prefixWriter{mu: &sync.Mutex{}, out: &buf, prefix: "job | "}
+
+	uc := &superviseProcessesUseCase{redactor: &redactUseCase{}}
+	exit := uc.superviseProcess(context.Background(), ProcessSpec{
+		Name:        "job",
+		Command:     `echo "token=$TOKEN"; exit 3`,
+		Env:         []string{"PATH=/usr/bin:/bin", "TOKEN=s3cr3t-value"},
+		Redact:      map[string]string{"TOKEN": "s3cr3t-value"},
+		Restart:     domain.RestartOnFailure,
+		MaxRestarts: 1,
+	}, out)
+
+	if exit.exitCode != 3 {
+		t.Errorf("expected exit code 3, got %d", exit.exitCode)
+	}
+
+	output := buf.String()
+	if strings.Contains(output, "s3cr3t-value") {
+		t.Errorf("secret leaked into output: %q", output)
+	}
+	if n := strings.Count(output, "token=[REDACTED]"); n != 2 {
+		t.Errorf("expected the process to run twice, ran %d times: %q", n, output)
+	}
+}
+
+func TestSuperviseProcessStopsOnCancel(t *testing.T) {
+	var buf bytes.Buffer
+	out := &prefixWriter{mu: &sync.Mutex{}, out: &buf, prefix: "web | "}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	uc := &superviseProcessesUseCase{redactor: &redactUseCase{}}
+	start := time.Now()
+	uc.superviseProcess(ctx, ProcessSpec{
+		Name:    "web",
+		Command: "exec sleep 30",
+		Env:     []string{"PATH=/usr/bin:/bin"},
+		Restart: domain.RestartAlways,
+	}, out)
+
+	if elapsed := time.Since(start); elapsed > restartGracePeriod {
+		t.Errorf("process was not stopped promptly (took %s)", elapsed)
+	}
+	if !strings.Contains(buf.String(), "stopped") {
+		t.Errorf("expected a stop message, got %q", buf.String())
+	}
+}
+
+func TestSuperviseProcessesStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	uc := NewSuperviseProcessesUseCase()
+	start := time.Now()
+	uc.Execute(ctx, []ProcessSpec{{
+		Name:    "web",
+		Command: "exec sleep 30",
+		Env:     []string{"PATH=/usr/bin:/bin"},
+		Restart: domain.RestartAlways,
+	}})
+
+	if elapsed := time.Since(start); elapsed > restartGracePeriod {
+		t.Errorf("processes were not stopped with the context (took %s)", elapsed)
+	}
+}
@@ -9,37 +9,125 @@ import (
 	"os/signal"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/aymanbagabas/go-pty"
 )
 
+// restartGracePeriod is how long a command is given to exit after SIGTERM
+// before it is killed during a restart
+const restartGracePeriod = 5 * time.Second
+
 type redactUseCase struct{}
 
 func NewRedactor() RedactUseCase {
 	return &redactUseCase{}
 }
 
+// childSpec describes how to start a command. A nil env starts it with the
+// environment of the current process.
+type childSpec struct {
+	args   []string
+	env    []string
+	dir    string
+	redact map[string]string
+	out    io.Writer
+}
+
+// childProcess is a single run of the supervised command attached to its own PTY
+type childProcess struct {
+	cmd        *pty.Cmd
+	ptyMaster  pty.Pty
+	cancel     context.CancelFunc
+	done       chan int
+	outputDone chan int
+	redactData atomic.Pointer[map[string]string]
+}
+
 func (uc *redactUseCase) Execute(ctx context.Context, args []string, envData map[string]string) int {
+	return uc.Supervise(ctx, args, envData, nil)
+}
+
+// Supervise runs the command and keeps it running with the latest environment
+// received on reloads. It returns the exit code of the command once it exits on
+// its own or is interrupted.
+func (uc *redactUseCase) Supervise(ctx context.Context, args []string, envData map[string]string, reloads <-chan Reload) int {
 	if len(args) == 0 {
 		fmt.Fprintf(os.Stderr, "No command provided\n")
 		return 1
 	}
 
+	// Handle interrupt signals
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	// Stdin is read once for the lifetime of the supervisor and forwarded to
+	// whichever command is currently running
+	stdinChan := make(chan []byte, 1)
+	go uc.readStdin(cancelCtx, stdinChan)
+
+	for {
+		child, err := uc.startChild(cancelCtx, childSpec{args: args, redact: envData, out: os.Stdout}, stdinChan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error starting command: %v\n", err)
+			return 1
+		}
+
+		restart := false
+		for !restart {
+			select {
+			case exitCode := <-child.done:
+				child.close()
+				return exitCode
+			case <-sigChan:
+				fmt.Fprintf(os.Stderr, "\nReceived interrupt signal, terminating command...\n")
+				return child.stop(os.Interrupt, 100*time.Millisecond)
+			case reload, ok := <-reloads:
+				if !ok {
+					reloads = nil
+					continue
+				}
+				envData = reload.Env
+
+				if reload.Signal != nil {
+					child.redactData.Store(&envData)
+					if err := child.cmd.Process.Signal(reload.Signal); err != nil {
+						fmt.Fprintf(os.Stderr, "Error sending %v to command: %v\n", reload.Signal, err)
+					}
+					continue
+				}
+
+				fmt.Fprintf(os.Stderr, "Restarting command...\n")
+				child.stop(syscall.SIGTERM, restartGracePeriod)
+				restart = true
+			}
+		}
+	}
+}
+
+func (uc *redactUseCase) startChild(ctx context.Context, spec childSpec, stdinChan <-chan []byte) (*childProcess, error) {
 	// Create a new PTY
 	ptyMaster, err := pty.New()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating PTY: %v\n", err)
-		return 1
+		return nil, fmt.Errorf("failed to create PTY: %w", err)
 	}
-	defer ptyMaster.Close()
 
 	// Create the command using PTY
-	cmd := ptyMaster.Command(args[0], args[1:]...)
+	cmd := ptyMaster.Command(spec.args[0], spec.args[1:]...)
+	cmd.Dir = spec.dir
+
+	env := spec.env
+	if env == nil {
+		env = os.Environ()
+	}
 
 	// Set environment variables to force color output
-	cmd.Env = append(os.Environ(),
+	cmd.Env = append(env,
 		"FORCE_COLOR=1",
 		"CLICOLOR_FORCE=1",
 		"TERM=xterm-256color",
@@ -47,43 +135,29 @@ func (uc *redactUseCase) Execute(ctx context.Context, args []string, envData map
 
 	// Start the command
 	if err := cmd.Start(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error starting command: %v\n", err)
-		return 1
+		ptyMaster.Close()
+		return nil, err
 	}
 
-	// Handle interrupt signals
-	cancelCtx, cancel := context.WithCancel(context.Background())
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	// Handle signals in a goroutine
-	go func() {
-		<-sigChan
-		fmt.Fprintf(os.Stderr, "\nReceived interrupt signal, terminating command...\n")
-		cancel()
-		// Forward signal to the child process
-		if cmd.Process != nil {
-			cmd.Process.Signal(os.Interrupt)
-			// Give the process a chance to handle the signal gracefully
-			time.Sleep(100 * time.Millisecond)
-			// If still running, force kill
-			if cmd.ProcessState == nil || !cmd.ProcessState.Exited() {
-				cmd.Process.Kill()
-			}
-		}
-	}()
-
-	// Channels to signal completion
-	outputDone := make(chan int, 1)
-	cmdDone := make(chan int, 1)
+	childCtx, cancel := context.WithCancel(ctx)
+	child := &childProcess{
+		cmd:        cmd,
+		ptyMaster:  ptyMaster,
+		cancel:     cancel,
+		done:       make(chan int, 1),
+		outputDone: make(chan int, 1),
+	}
+	child.redactData.Store(&spec.redact)
 
-	// Handle stdin in a separate goroutine
-	go uc.handleStdin(cancelCtx, ptyMaster)
+	// Forward stdin to this command's PTY
+	if stdinChan != nil {
+		go uc.handleStdin(childCtx, ptyMaster, stdinChan)
+	}
 
 	// Handle stdout/stderr processing
-	go uc.handleOutput(cancelCtx, ptyMaster, outputDone, envData)
+	go uc.handleOutput(childCtx, ptyMaster, spec.out, child.outputDone, &child.redactData)
 
-	// Wait for command completion or context cancellation
+	// Wait for command completion
 	go func() {
 		exitCode := 0
 		if err := cmd.Wait(); err != nil {
@@ -93,61 +167,78 @@ func (uc *redactUseCase) Execute(ctx context.Context, args []string, envData map
 				exitCode = 1
 			}
 		}
-		cmdDone <- exitCode
+		child.done <- exitCode
 	}()
 
-	// Wait for completion or cancellation
-	select {
-	case exitCode := <-cmdDone:
-		// Wait for output processing to finish with timeout
-		select {
-		case <-outputDone:
-		case <-time.After(1 * time.Second):
-			// Timeout waiting for output processing
+	return child, nil
+}
+
+// stop delivers sig to the command and waits up to grace for it to exit before
+// killing it. It returns the exit code of the command.
+func (c *childProcess) stop(sig os.Signal, grace time.Duration) int {
+	defer c.cancel()
+	defer c.ptyMaster.Close()
+
+	if c.cmd.Process != nil {
+		if err := c.cmd.Process.Signal(sig); err != nil {
+			c.cmd.Process.Kill()
 		}
-		cancel()
+	}
+
+	select {
+	case exitCode := <-c.done:
 		return exitCode
-	case <-cancelCtx.Done():
-		// Wait for command to finish with timeout
-		select {
-		case exitCode := <-cmdDone:
-			return exitCode
-		case <-time.After(2 * time.Second):
-			// Force kill if still running
-			if cmd.Process != nil {
-				cmd.Process.Kill()
-			}
-			return 1
+	case <-time.After(grace):
+		// Force kill if still running
+		if c.cmd.Process != nil {
+			c.cmd.Process.Kill()
 		}
 	}
+
+	select {
+	case exitCode := <-c.done:
+		return exitCode
+	case <-time.After(2 * time.Second):
+		return 1
+	}
 }
 
-func (uc *redactUseCase) handleStdin(ctx context.Context, ptyMaster pty.Pty) {
-	// Use a goroutine to handle stdin reading without blocking
-	stdinChan := make(chan []byte, 1)
-	go func() {
-		buffer := make([]byte, 1024)
-		for {
-			n, err := os.Stdin.Read(buffer)
-			if err != nil {
-				if err != io.EOF {
-					fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
-				}
-				return
+// close releases the PTY of a command that has already exited
+func (c *childProcess) close() {
+	// Wait for output processing to finish with timeout
+	select {
+	case <-c.outputDone:
+	case <-time.After(1 * time.Second):
+		// Timeout waiting for output processing
+	}
+	c.cancel()
+	c.ptyMaster.Close()
+}
+
+func (uc *redactUseCase) readStdin(ctx context.Context, stdinChan chan<- []byte) {
+	buffer := make([]byte, 1024)
+	for {
+		n, err := os.Stdin.Read(buffer)
+		if err != nil {
+			if err != io.EOF {
+				fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
 			}
-			if n > 0 {
-				// Make a copy of the buffer to send through channel
-				data := make([]byte, n)
-				copy(data, buffer[:n])
-				select {
-				case stdinChan <- data:
-				case <-ctx.Done():
-					return
-				}
+			return
+		}
+		if n > 0 {
+			// Make a copy of the buffer to send through channel
+			data := make([]byte, n)
+			copy(data, buffer[:n])
+			select {
+			case stdinChan <- data:
+			case <-ctx.Done():
+				return
 			}
 		}
-	}()
+	}
+}
 
+func (uc *redactUseCase) handleStdin(ctx context.Context, ptyMaster pty.Pty, stdinChan <-chan []byte) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -162,7 +253,7 @@ func (uc *redactUseCase) handleStdin(ctx context.Context, ptyMaster pty.Pty) {
 	}
 }
 
-func (uc *redactUseCase) handleOutput(ctx context.Context, ptyMaster pty.Pty, done chan<- int, envData map[string]string) {
+func (uc *redactUseCase) handleOutput(ctx context.Context, ptyMaster pty.Pty, out io.Writer, done chan<- int, redactData *atomic.Pointer[map[string]string]) {
 	buffer := make([]byte, 4096)
 	defer func() {
 		done <- 0
@@ -202,10 +293,10 @@ func (uc *redactUseCase) handleOutput(ctx context.Context, ptyMaster pty.Pty, do
 		case data := <-outputChan:
 			// Process and redact the output
 			text := string(data)
-			redactedText := uc.processAndRedactText(text, envData)
+			redactedText := uc.processAndRedactText(text, *redactData.Load())
 
-			// Write the redacted content to stdout
-			_, writeErr := os.Stdout.Write([]byte(redactedText))
+			// Write the redacted content to the output
+			_, writeErr := out.Write([]byte(redactedText))
 			if writeErr != nil {
 				fmt.Fprintf(os.Stderr, "Error writing output: %v\n", writeErr)
 				return
@@ -0,0 +1,83 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+type watchChangesUseCase struct {
+	changeService services.ChangeService
+}
+
+func NewWatchChangesUseCase() WatchChangesUseCase {
+	changeService := services.NewChangeService()
+	return &watchChangesUseCase{
+		changeService: changeService,
+	}
+}
+
+// Execute records the current revision of the environment and then polls for
+// new revisions in the background. A notification is sent once no further
+// change has been observed for opts.Debounce, so a burst of edits results in a
+// single reload. The channel is closed when ctx is cancelled.
+func (w *watchChangesUseCase) Execute(ctx context.Context, opts WatchOptions) (<-chan struct{}, error) {
+	if opts.Interval <= 0 {
+		return nil, fmt.Errorf("watch interval must be positive, got %s", opts.Interval)
+	}
+
+	baseline, err := w.changeService.GetRevision(ctx, opts.AppID, opts.EnvTypeID, opts.IncludeSecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan struct{}, 1)
+	go w.poll(ctx, opts, baseline, changes)
+
+	return changes, nil
+}
+
+func (w *watchChangesUseCase) poll(ctx context.Context, opts WatchOptions, revision string, changes chan<- struct{}) {
+	defer close(changes)
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	// The debounce timer is only armed while a change is pending
+	debounce := time.NewTimer(opts.Debounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current, err := w.changeService.GetRevision(ctx, opts.AppID, opts.EnvTypeID, opts.IncludeSecrets)
+			if err != nil {
+				if ctx.Err() == nil {
+					fmt.Fprintf(os.Stderr, "Error checking for remote changes: %v\n", err)
+				}
+				continue
+			}
+
+			if current == revision {
+				continue
+			}
+			revision = current
+
+			// Restart the quiet period on every new revision
+			debounce.Reset(opts.Debounce)
+		case <-debounce.C:
+			// Drop the notification if the previous one hasn't been consumed yet;
+			// the consumer re-reads the full environment either way.
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/testutil"
+)
+
+// standInServer serves the PIT history endpoints polled by the watcher and
+// lets tests bump the latest revision.
+type standInServer struct {
+	mu        sync.Mutex
+	envRev    int
+	secretRev int
+	requests  int
+}
+
+func (s *standInServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	var rev string
+	switch r.URL.Path {
+	case "/api/env/history":
+		rev = fmt.Sprintf("env-pit-%d", s.envRev)
+	case "/api/secret/history":
+		rev = fmt.Sprintf("secret-pit-%d", s.secretRev)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"pits":       []map[string]string{{"id": rev}},
+		"totalPages": 1,
+	})
+}
+
+func (s *standInServer) bumpEnv() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.envRev++
+}
+
+func (s *standInServer) bumpSecret() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secretRev++
+}
+
+func (s *standInServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// standIn is shared by all tests in the package because the CLI configuration,
+// and with it the backend URL, is only loaded once per process.
+var standIn = &standInServer{}
+
+func TestMain(m *testing.M) {
+	ts := httptest.NewServer(standIn)
+
+	dir, err := testutil.UseServer(ts.URL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to configure stand-in server: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	ts.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func waitForChange(changes <-chan struct{}, timeout time.Duration) bool {
+	select {
+	case _, ok := <-changes:
+		return ok
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func TestWatchChangesUseCase(t *testing.T) {
+	server := standIn
+	uc := NewWatchChangesUseCase()
+
+	t.Run("no notification without changes", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		changes, err := uc.Execute(ctx, WatchOptions{
+			AppID:     "app",
+			EnvTypeID: "dev",
+			Interval:  10 * time.Millisecond,
+			Debounce:  10 * time.Millisecond,
+		})
+		if err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+
+		if waitForChange(changes, 150*time.Millisecond) {
+			t.Error("expected no notification when the revision is unchanged")
+		}
+		if server.requestCount() < 2 {
+			t.Errorf("expected the watcher to poll the server, got %d requests", server.requestCount())
+		}
+	})
+
+	t.Run("burst of changes is debounced", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		changes, err := uc.Execute(ctx, WatchOptions{
+			AppID:     "app",
+			EnvTypeID: "dev",
+			Interval:  10 * time.Millisecond,
+			Debounce:  100 * time.Millisecond,
+		})
+		if err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+
+		for i := 0; i < 3; i++ {
+			server.bumpEnv()
+			time.Sleep(25 * time.Millisecond)
+		}
+
+		if !waitForChange(changes, time.Second) {
+			t.Fatal("expected a notification after the environment changed")
+		}
+		if waitForChange(changes, 200*time.Millisecond) {
+			t.Error("expected a single notification for a burst of changes")
+		}
+	})
+
+	t.Run("secret changes are ignored unless requested", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		envOnly, err := uc.Execute(ctx, WatchOptions{
+			AppID:     "app",
+			EnvTypeID: "dev",
+			Interval:  10 * time.Millisecond,
+		})
+		if err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+
+		withSecrets, err := uc.Execute(ctx, WatchOptions{
+			AppID:          "app",
+			EnvTypeID:      "dev",
+			IncludeSecrets: true,
+			Interval:       10 * time.Millisecond,
+		})
+		if err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+
+		server.bumpSecret()
+
+		if !waitForChange(withSecrets, time.Second) {
+			t.Error("expected a notification when secrets are watched")
+		}
+		if waitForChange(envOnly, 100*time.Millisecond) {
+			t.Error("expected no notification when secrets are not watched")
+		}
+	})
+
+	t.Run("channel closes on cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		changes, err := uc.Execute(ctx, WatchOptions{
+			AppID:     "app",
+			EnvTypeID: "dev",
+			Interval:  10 * time.Millisecond,
+		})
+		if err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+
+		cancel()
+
+		select {
+		case _, ok := <-changes:
+			if ok {
+				t.Error("expected the channel to be closed")
+			}
+		case <-time.After(time.Second):
+			t.Error("timed out waiting for the channel to close")
+		}
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		if _, err := uc.Execute(context.Background(), WatchOptions{}); err == nil {
+			t.Error("expected an error for a zero interval")
+		}
+	})
+}
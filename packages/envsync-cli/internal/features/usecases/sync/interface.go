@@ -2,6 +2,7 @@ package sync
 
 import (
 	"context"
+	"time"
 
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
 )
@@ -11,7 +12,7 @@ type PushUseCase interface {
 }
 
 type PullUseCase interface {
-	Execute(context.Context, string) (SyncResponse, error)
+	Execute(context.Context, string, bool) (SyncResponse, error)
 }
 
 type SyncResponse struct {
@@ -20,4 +21,8 @@ type SyncResponse struct {
 	Deleted   []domain.EnvironmentVariable `json:"deleted"`
 	Conflicts []domain.EnvironmentVariable `json:"conflicts"`
 	Warnings  []string                     `json:"warnings,omitempty"`
+	// CachedAt is set when the variables came from the offline cache
+	CachedAt *time.Time `json:"cached_at,omitempty"`
+	// Snapshot holds the variables as fetched, before references are expanded
+	Snapshot domain.EnvSnapshot `json:"-"`
 }
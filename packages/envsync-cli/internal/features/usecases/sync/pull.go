@@ -2,8 +2,10 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
@@ -12,17 +14,23 @@ import (
 )
 
 type pullUseCase struct {
-	syncService services.SyncService
+	syncService          services.SyncService
+	cacheService         services.EnvCacheService
+	interpolationService services.InterpolationService
 }
 
 func NewPullUseCase() PullUseCase {
 	service := services.NewSyncService()
+	cacheService := services.NewEnvCacheService()
+	interpolationService := services.NewInterpolationService()
 	return &pullUseCase{
-		syncService: service,
+		syncService:          service,
+		cacheService:         cacheService,
+		interpolationService: interpolationService,
 	}
 }
 
-func (uc *pullUseCase) Execute(ctx context.Context, configPath string) (SyncResponse, error) {
+func (uc *pullUseCase) Execute(ctx context.Context, configPath string, offline bool) (SyncResponse, error) {
 	ctx, span := telemetry.Tracer().Start(ctx, "sync.pull")
 	defer span.End()
 
@@ -31,16 +39,29 @@ func (uc *pullUseCase) Execute(ctx context.Context, configPath string) (SyncResp
 		return SyncResponse{}, NewFileSystemError("configuration file check failed", err)
 	}
 
-	// Read remote remote environment variables
-	remoteEnv, err := uc.syncService.ReadRemoteEnv(ctx)
+	projectCfg, err := uc.syncService.ReadConfigData()
 	if err != nil {
-		return SyncResponse{}, NewServiceError("failed to read remote environment variables", err)
+		return SyncResponse{}, NewFileSystemError("failed to read project configuration", err)
 	}
 
-	// Convert remote env variables to map for processing
-	remoteEnvMap := make(map[string]string)
-	for _, env := range remoteEnv {
-		remoteEnvMap[env.Key] = env.Value
+	// Read remote environment variables, falling back to the offline cache
+	remoteEnvMap, cachedAt, err := uc.readRemoteEnv(ctx, projectCfg, offline)
+	if err != nil {
+		return SyncResponse{}, err
+	}
+
+	// The snapshot keeps the fetched values, which is what 'envsync run' and
+	// 'envsync export' see before expanding references
+	snapshot := domain.EnvSnapshot{
+		AppID:     projectCfg.AppID,
+		EnvTypeID: projectCfg.EnvTypeID,
+		Variables: remoteEnvMap,
+	}
+
+	// Secrets are not pulled, so references to them are written unexpanded
+	remoteEnvMap, err = uc.interpolationService.ResolveKnown(ctx, projectCfg.AppID, remoteEnvMap)
+	if err != nil {
+		return SyncResponse{}, NewValidationError("failed to interpolate remote environment variables", "", err)
 	}
 
 	// Read local environment variables from the specified config file
@@ -62,9 +83,73 @@ func (uc *pullUseCase) Execute(ctx context.Context, configPath string) (SyncResp
 		}
 	}
 
+	diff.CachedAt = cachedAt
+	diff.Snapshot = snapshot
+
 	return diff, nil
 }
 
+// readRemoteEnv returns the remote variables as a map. When offline is set or
+// the API is unreachable the cached variables are returned together with the
+// time they were fetched.
+func (uc *pullUseCase) readRemoteEnv(ctx context.Context, projectCfg domain.SyncConfig, offline bool) (map[string]string, *time.Time, error) {
+	if offline {
+		return uc.readCachedEnv(projectCfg, nil)
+	}
+
+	remoteEnv, err := uc.syncService.ReadRemoteEnv(ctx)
+	if err != nil {
+		if services.IsBackendUnavailable(err) {
+			return uc.readCachedEnv(projectCfg, err)
+		}
+		return nil, nil, NewServiceError("failed to read remote environment variables", err)
+	}
+
+	// Convert remote env variables to map for processing
+	remoteEnvMap := make(map[string]string)
+	for _, env := range remoteEnv {
+		remoteEnvMap[env.Key] = env.Value
+	}
+
+	uc.updateCache(projectCfg, remoteEnvMap)
+
+	return remoteEnvMap, nil, nil
+}
+
+func (uc *pullUseCase) readCachedEnv(projectCfg domain.SyncConfig, cause error) (map[string]string, *time.Time, error) {
+	entry, err := uc.cacheService.Load(projectCfg.AppID, projectCfg.EnvTypeID)
+	if err != nil {
+		if cause != nil {
+			return nil, nil, NewServiceError("failed to read remote environment variables", fmt.Errorf("%w (offline cache not usable: %v)", cause, err))
+		}
+		return nil, nil, NewNotFoundError("failed to read cached environment variables", err)
+	}
+
+	return entry.Variables, &entry.FetchedAt, nil
+}
+
+// updateCache refreshes the cached variables while keeping any cached secrets
+// stored by 'envsync run'. Secrets of a stale entry are kept too; they keep
+// their fetch time, so they are still refused until 'envsync run' refreshes
+// them.
+func (uc *pullUseCase) updateCache(projectCfg domain.SyncConfig, variables map[string]string) {
+	entry := domain.EnvCache{
+		AppID:     projectCfg.AppID,
+		EnvTypeID: projectCfg.EnvTypeID,
+		Variables: variables,
+		FetchedAt: time.Now(),
+	}
+
+	existing, err := uc.cacheService.Load(projectCfg.AppID, projectCfg.EnvTypeID)
+	if err == nil || errors.Is(err, services.ErrEnvCacheStale) {
+		entry.Secrets = existing.Secrets
+		entry.SecretsFetchedAt = existing.SecretsFetchedAt
+	}
+
+	// The cache is a convenience; a failure to write it must not fail the pull
+	_ = uc.cacheService.Save(entry)
+}
+
 func (uc *pullUseCase) checkConfigFileExists(configPath string) error {
 	// Check if the configuration file exists at the specified path
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/services"
+)
+
+// staleCache holds one entry that is past its maximum staleness
+type staleCache struct {
+	entry domain.EnvCache
+	saved *domain.EnvCache
+}
+
+func (c *staleCache) Save(entry domain.EnvCache) error {
+	c.saved = &entry
+	return nil
+}
+
+func (c *staleCache) RewrapKey(accessToken string) error {
+	return nil
+}
+
+func (c *staleCache) Load(appID, envTypeID string) (*domain.EnvCache, error) {
+	entry := c.entry
+	return &entry, services.ErrEnvCacheStale
+}
+
+func TestPullUpdateCacheKeepsStaleSecrets(t *testing.T) {
+	fetched := time.Now().Add(-30 * 24 * time.Hour)
+	cache := &staleCache{entry: domain.EnvCache{
+		AppID:            "app-1",
+		EnvTypeID:        "dev",
+		Variables:        map[string]string{"PORT": "80"},
+		Secrets:          map[string]string{"DB_PASSWORD": "hunter2"},
+		FetchedAt:        fetched,
+		SecretsFetchedAt: fetched,
+	}}
+
+	uc := &pullUseCase{cacheService: cache}
+	uc.updateCache(domain.SyncConfig{AppID: "app-1", EnvTypeID: "dev"}, map[string]string{"PORT": "8080"})
+
+	if cache.saved == nil {
+		t.Fatal("cache was not updated")
+	}
+	if cache.saved.Variables["PORT"] != "8080" {
+		t.Errorf("variables not refreshed: %v", cache.saved.Variables)
+	}
+	if cache.saved.Secrets["DB_PASSWORD"] != "hunter2" {
+		t.Errorf("stale secrets were dropped: %v", cache.saved.Secrets)
+	}
+	if !cache.saved.SecretsFetchedAt.Equal(fetched) {
+		t.Errorf("secrets fetch time changed to %v", cache.saved.SecretsFetchedAt)
+	}
+}
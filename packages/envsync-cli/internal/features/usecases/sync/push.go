@@ -11,13 +11,16 @@ import (
 )
 
 type pushUseCase struct {
-	syncService services.SyncService
+	syncService          services.SyncService
+	interpolationService services.InterpolationService
 }
 
 func NewPushUseCase() PushUseCase {
 	service := services.NewSyncService()
+	interpolationService := services.NewInterpolationService()
 	return &pushUseCase{
-		syncService: service,
+		syncService:          service,
+		interpolationService: interpolationService,
 	}
 }
 
@@ -48,6 +51,8 @@ func (uc *pushUseCase) Execute(ctx context.Context, configPath string) (SyncResp
 		return SyncResponse{}, NewFileSystemError("failed to read local environment variables", err)
 	}
 
+	uc.restoreReferences(ctx, remoteEnvMap, localEnv)
+
 	// Calculate the differences between remote and local environment variables
 	diff, err := uc.calculateEnvDiff(remoteEnvMap, localEnv)
 	if err != nil {
@@ -71,6 +76,27 @@ func (uc *pushUseCase) Execute(ctx context.Context, configPath string) (SyncResp
 	return diff, nil
 }
 
+// restoreReferences puts back the remote value of local variables that only
+// differ because pull wrote them expanded, so pushing never replaces a
+// ${KEY} reference with its expansion
+func (uc *pushUseCase) restoreReferences(ctx context.Context, remoteEnv, localEnv map[string]string) {
+	projectCfg, err := uc.syncService.ReadConfigData()
+	if err != nil {
+		return
+	}
+
+	resolved, err := uc.interpolationService.ResolveKnown(ctx, projectCfg.AppID, remoteEnv)
+	if err != nil {
+		return
+	}
+
+	for key, localVal := range localEnv {
+		if remoteVal, ok := remoteEnv[key]; ok && localVal != remoteVal && localVal == resolved[key] {
+			localEnv[key] = remoteVal
+		}
+	}
+}
+
 func (uc *pushUseCase) checkConfigFileExists(configPath string) error {
 	// Check if the configuration file exists at the specified path
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
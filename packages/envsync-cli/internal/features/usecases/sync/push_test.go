@@ -0,0 +1,130 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/constants"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/testutil"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/envsynctest"
+)
+
+// server is shared by all tests in the package because the CLI configuration,
+// and with it the backend URL, is only loaded once per process.
+var server *envsynctest.Server
+
+func TestMain(m *testing.M) {
+	server = envsynctest.NewServer()
+
+	dir, err := testutil.UseServer(server.URL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to configure fake server: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	server.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// setupProject creates a project for a new app in a temporary working
+// directory with the given local variables.
+func setupProject(t *testing.T, local string) (appID, envTypeID string) {
+	t.Helper()
+
+	appID, envTypeIDs := server.AddApp(t.Name(), "dev")
+	t.Chdir(t.TempDir())
+
+	project := fmt.Sprintf("app_id = %q\nenv_type_id = %q\n", appID, envTypeIDs[0])
+	if err := os.WriteFile(constants.DefaultProjectConfig, []byte(project), 0o644); err != nil {
+		t.Fatalf("failed to write project config: %v", err)
+	}
+	if err := os.WriteFile(".env", []byte(local), 0o644); err != nil {
+		t.Fatalf("failed to write .env: %v", err)
+	}
+
+	return appID, envTypeIDs[0]
+}
+
+func TestPushUseCase(t *testing.T) {
+	appID, envTypeID := setupProject(t, "HOST=example.com\nNEW=1\n")
+	server.SetVariables(appID, envTypeID, map[string]string{"HOST": "localhost", "OLD": "x"})
+
+	res, err := NewPushUseCase().Execute(context.Background(), constants.DefaultProjectConfig)
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	if len(res.Added) != 1 || res.Added[0].Key != "NEW" {
+		t.Errorf("Added = %v, want NEW", res.Added)
+	}
+	if len(res.Updated) != 1 || res.Updated[0].Key != "HOST" {
+		t.Errorf("Updated = %v, want HOST", res.Updated)
+	}
+	if len(res.Deleted) != 1 || res.Deleted[0].Key != "OLD" {
+		t.Errorf("Deleted = %v, want OLD", res.Deleted)
+	}
+
+	want := map[string]string{"HOST": "example.com", "NEW": "1"}
+	if got := server.Variables(appID, envTypeID); !reflect.DeepEqual(got, want) {
+		t.Errorf("remote variables = %v, want %v", got, want)
+	}
+}
+
+func TestPushUseCaseServerError(t *testing.T) {
+	appID, envTypeID := setupProject(t, "HOST=example.com\n")
+	server.SetVariables(appID, envTypeID, map[string]string{"HOST": "localhost"})
+
+	server.Inject(envsynctest.Fault{Path: "/api/env/batch", StatusCode: http.StatusInternalServerError})
+	defer server.ClearFaults()
+
+	if _, err := NewPushUseCase().Execute(context.Background(), constants.DefaultProjectConfig); err == nil {
+		t.Fatal("Execute succeeded, want error")
+	}
+
+	want := map[string]string{"HOST": "localhost"}
+	if got := server.Variables(appID, envTypeID); !reflect.DeepEqual(got, want) {
+		t.Errorf("remote variables = %v, want %v", got, want)
+	}
+}
+
+func TestPushUseCaseLargeBatch(t *testing.T) {
+	var local strings.Builder
+	want := make(map[string]string)
+	for i := range 250 {
+		key, value := fmt.Sprintf("KEY_%03d", i), strconv.Itoa(i)
+		fmt.Fprintf(&local, "%s=%s\n", key, value)
+		want[key] = value
+	}
+	appID, envTypeID := setupProject(t, local.String())
+	server.ResetRequests()
+
+	res, err := NewPushUseCase().Execute(context.Background(), constants.DefaultProjectConfig)
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if len(res.Added) != 250 {
+		t.Errorf("len(Added) = %d, want 250", len(res.Added))
+	}
+	if got := server.Variables(appID, envTypeID); !reflect.DeepEqual(got, want) {
+		t.Errorf("remote variables have %d keys, want %d", len(got), len(want))
+	}
+
+	batches := 0
+	for _, request := range server.Requests() {
+		if request.Method == http.MethodPut && request.Path == "/api/env/batch" {
+			batches++
+		}
+	}
+	if batches != 3 {
+		t.Errorf("sent %d batch requests, want 3", batches)
+	}
+}
@@ -40,6 +40,8 @@ func GpgKeyResponseToDomain(res responses.GpgKeyResponse) domain.GpgKey {
 		IsDefault:   res.IsDefault,
 		CreatedAt:   createdAt,
 		UpdatedAt:   updatedAt,
+
+		RevocationReason: res.RevocationReason,
 	}
 }
 
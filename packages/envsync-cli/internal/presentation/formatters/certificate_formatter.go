@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository/responses"
@@ -42,6 +43,26 @@ func (f *CertificateFormatter) FormatCertList(writer io.Writer, certs []domain.C
 	return err
 }
 
+func (f *CertificateFormatter) FormatExpiringCerts(writer io.Writer, certs []domain.Certificate) error {
+	if len(certs) == 0 {
+		return f.FormatSuccess(writer, "No certificates expire in this window.")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-36s  %-8s  %-30s  %-20s  %s\n",
+		"Serial", "Type", "Subject", "Expires", "Days Left"))
+	sb.WriteString(strings.Repeat("─", 110) + "\n")
+
+	for _, cert := range certs {
+		daysLeft := int(time.Until(*cert.NotAfter).Hours() / 24)
+		sb.WriteString(fmt.Sprintf("%-36s  %-8s  %-30s  %-20s  %d\n",
+			cert.SerialHex, cert.CertType, truncate(cert.SubjectCN, 30),
+			cert.NotAfter.Format("2006-01-02 15:04"), daysLeft))
+	}
+
+	return f.FormatWarning(writer, sb.String())
+}
+
 func (f *CertificateFormatter) FormatCAStatus(writer io.Writer, cert domain.Certificate) error {
 	msg := fmt.Sprintf("Organization CA Status\n\n"+
 		"  Subject:    %s\n"+
@@ -101,3 +122,16 @@ func (f *CertificateFormatter) FormatCRL(writer io.Writer, result domain.CRLResu
 	_, err = writer.Write([]byte("\n"))
 	return err
 }
+
+func (f *CertificateFormatter) FormatVerification(writer io.Writer, result domain.CertVerification) error {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Certificate %s (serial %s)\n\n", result.SubjectCN, result.SerialHex))
+	for _, check := range result.Checks {
+		sb.WriteString(fmt.Sprintf("  %-10s  %-4s  %s\n", check.Name, check.Status, check.Message))
+	}
+
+	if result.Valid {
+		return f.FormatSuccess(writer, sb.String())
+	}
+	return f.FormatError(writer, sb.String())
+}
@@ -28,6 +28,10 @@ func (f *ConfigFormatter) FormatSingleValue(writer io.Writer, key, value string)
 			value = "<not set>"
 		}
 		output = fmt.Sprintf("🌐 backend_url: %s\n", value)
+	case "cache_max_staleness", "cachemaxstaleness":
+		output = fmt.Sprintf("🗄️  cache_max_staleness: %s\n", value)
+	case "client_cert", "clientcert", "client_key", "clientkey", "ca_cert", "cacert":
+		output = fmt.Sprintf("🔐 %s: %s\n", strings.ToLower(key), value)
 	default:
 		output = fmt.Sprintf("❓ %s: %s\n", key, value)
 	}
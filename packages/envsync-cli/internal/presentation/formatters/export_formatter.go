@@ -0,0 +1,12 @@
+package formatters
+
+type ExportFormatter struct {
+	*BaseFormatter
+}
+
+func NewExportFormatter() *ExportFormatter {
+	base := NewBaseFormatter()
+	return &ExportFormatter{
+		BaseFormatter: base,
+	}
+}
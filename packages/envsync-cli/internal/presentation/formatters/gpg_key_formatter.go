@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
 )
@@ -59,6 +60,67 @@ func (f *GpgKeyFormatter) FormatKeyGenerated(writer io.Writer, key domain.GpgKey
 	return f.FormatSuccess(writer, msg)
 }
 
+func (f *GpgKeyFormatter) FormatKeyImported(writer io.Writer, key domain.GpgKey) error {
+	msg := fmt.Sprintf("GPG key imported successfully!\n\n"+
+		"  Name:        %s\n"+
+		"  Email:       %s\n"+
+		"  ID:          %s\n"+
+		"  Fingerprint: %s\n"+
+		"  Algorithm:   %s\n",
+		key.Name, key.Email, key.ID, key.Fingerprint, key.Algorithm)
+
+	return f.FormatSuccess(writer, msg)
+}
+
+func (f *GpgKeyFormatter) FormatKeyDetail(writer io.Writer, key domain.GpgKey) error {
+	algorithm := key.Algorithm
+	if key.KeySize != nil {
+		algorithm = fmt.Sprintf("%s (%d bits)", algorithm, *key.KeySize)
+	}
+
+	expires := "never"
+	if key.ExpiresAt != nil {
+		expires = key.ExpiresAt.Local().Format("2006-01-02 15:04:05")
+	}
+
+	status := "active"
+	if key.RevokedAt != nil {
+		status = "revoked at " + key.RevokedAt.Local().Format("2006-01-02 15:04:05")
+		if key.RevocationReason != nil && *key.RevocationReason != "" {
+			status += " (" + *key.RevocationReason + ")"
+		}
+	} else if key.ExpiresAt != nil && key.ExpiresAt.Before(time.Now()) {
+		status = "expired"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("  ID:          %s\n", key.ID))
+	sb.WriteString(fmt.Sprintf("  Name:        %s\n", key.Name))
+	sb.WriteString(fmt.Sprintf("  Email:       %s\n", key.Email))
+	sb.WriteString(fmt.Sprintf("  Fingerprint: %s\n", formatFingerprint(key.Fingerprint)))
+	sb.WriteString(fmt.Sprintf("  Key ID:      %s\n", key.KeyID))
+	sb.WriteString(fmt.Sprintf("  Algorithm:   %s\n", algorithm))
+	sb.WriteString(fmt.Sprintf("  Usage:       %s\n", strings.Join(key.UsageFlags, ", ")))
+	sb.WriteString(fmt.Sprintf("  Trust:       %s\n", key.TrustLevel))
+	sb.WriteString(fmt.Sprintf("  Default:     %t\n", key.IsDefault))
+	sb.WriteString(fmt.Sprintf("  Created:     %s\n", key.CreatedAt.Local().Format("2006-01-02 15:04:05")))
+	sb.WriteString(fmt.Sprintf("  Expires:     %s\n", expires))
+	sb.WriteString(fmt.Sprintf("  Status:      %s\n", status))
+
+	_, err := writer.Write([]byte(sb.String()))
+	return err
+}
+
+// formatFingerprint groups a fingerprint in blocks of four characters, as gpg
+// prints it
+func formatFingerprint(fingerprint string) string {
+	var groups []string
+	for i := 0; i < len(fingerprint); i += 4 {
+		groups = append(groups, fingerprint[i:min(i+4, len(fingerprint))])
+	}
+	return strings.Join(groups, " ")
+}
+
 func (f *GpgKeyFormatter) FormatSignResult(writer io.Writer, result domain.GpgSignatureResult) error {
 	_, err := writer.Write([]byte(result.Signature))
 	if err != nil {
@@ -69,15 +131,84 @@ func (f *GpgKeyFormatter) FormatSignResult(writer io.Writer, result domain.GpgSi
 }
 
 func (f *GpgKeyFormatter) FormatVerifyResult(writer io.Writer, result domain.GpgVerifyResult) error {
-	if result.Valid {
-		msg := "Signature is VALID"
-		if result.SignerFingerprint != nil {
-			msg += fmt.Sprintf("\n  Signer: %s", *result.SignerFingerprint)
+	msg := "Signature is VALID"
+	if !result.Valid {
+		msg = "Signature is INVALID"
+		if result.Reason != "" {
+			msg += ": " + result.Reason
 		}
+	}
+	if result.SignerFingerprint != nil {
+		signer := *result.SignerFingerprint
+		if result.SignerName != "" {
+			signer = result.SignerName + " (" + signer + ")"
+		}
+		msg += fmt.Sprintf("\n  Signer: %s", signer)
+	}
+	if result.SignedAt != nil {
+		msg += fmt.Sprintf("\n  Signed: %s", result.SignedAt.Format(time.RFC3339))
+	}
+
+	if result.Valid {
 		return f.FormatSuccess(writer, msg)
 	}
+	return f.FormatError(writer, msg)
+}
+
+func (f *GpgKeyFormatter) FormatManifestSigned(writer io.Writer, result domain.ManifestResult) error {
+	msg := fmt.Sprintf("Signed %s manifest of %d file(s)\n  Manifest:  %s\n  Signature: %s",
+		strings.ToUpper(result.Algorithm), len(result.Entries), result.Manifest, result.Signature)
+	return f.FormatSuccess(writer, msg)
+}
+
+func (f *GpgKeyFormatter) FormatManifestVerified(writer io.Writer, result domain.ManifestResult) error {
+	if result.Verification != nil && !result.Verification.Valid {
+		return f.FormatVerifyResult(writer, *result.Verification)
+	}
+
+	var sb strings.Builder
+	failed := 0
+	for _, entry := range result.Entries {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", entry.Name, entry.Status))
+		if entry.Status != "OK" {
+			failed++
+		}
+	}
+	if _, err := writer.Write([]byte(sb.String())); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return f.FormatError(writer, fmt.Sprintf("%d of %d file(s) did not match the signed manifest", failed, len(result.Entries)))
+	}
+	return f.FormatVerifyResult(writer, *result.Verification)
+}
+
+func (f *GpgKeyFormatter) FormatKeyring(writer io.Writer, entries []domain.KeyringEntry) error {
+	if len(entries) == 0 {
+		return f.FormatWarning(writer, "Local keyring is empty. Run `envsync gpg keyring refresh` to fetch the organization keys.")
+	}
 
-	return f.FormatError(writer, "Signature is INVALID")
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-40s  %-20s  %-30s  %-8s  %-20s\n",
+		"Fingerprint", "Name", "Email", "Status", "Fetched"))
+	sb.WriteString(strings.Repeat("─", 126) + "\n")
+
+	for _, entry := range entries {
+		status := "active"
+		if entry.RevokedAt != nil {
+			status = "revoked"
+		} else if entry.ExpiresAt != nil && entry.ExpiresAt.Before(time.Now()) {
+			status = "expired"
+		}
+
+		sb.WriteString(fmt.Sprintf("%-40s  %-20s  %-30s  %-8s  %-20s\n",
+			entry.Fingerprint, truncate(entry.Name, 20), truncate(entry.Email, 30), status,
+			entry.FetchedAt.Local().Format("2006-01-02 15:04:05")))
+	}
+
+	_, err := writer.Write([]byte(sb.String()))
+	return err
 }
 
 func (f *GpgKeyFormatter) FormatExport(writer io.Writer, publicKey string) error {
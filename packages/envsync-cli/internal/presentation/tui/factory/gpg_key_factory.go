@@ -0,0 +1,42 @@
+package factory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/charmbracelet/huh"
+)
+
+type GpgKeyFactory struct{}
+
+func NewGpgKeyFactory() *GpgKeyFactory {
+	return &GpgKeyFactory{}
+}
+
+// PromptPassphraseTUI asks for the passphrase protecting the private key of
+// the given user ID
+func (f *GpgKeyFactory) PromptPassphraseTUI(ctx context.Context, userID string) (string, error) {
+	var passphrase string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Passphrase").
+				Description("Enter the passphrase of the private key for " + userID).
+				EchoMode(huh.EchoModePassword).
+				Value(&passphrase).
+				Validate(func(str string) error {
+					if str == "" {
+						return errors.New("passphrase is required")
+					}
+					return nil
+				}),
+		),
+	)
+
+	if err := form.RunWithContext(ctx); err != nil {
+		return "", err
+	}
+
+	return passphrase, nil
+}
@@ -76,11 +76,14 @@ func (s *authRepo) LoginDeviceCode(ctx context.Context) (responses.DeviceCodeRes
 // LoginToken exchanges a device code for an authentication token
 func (s *authRepo) LoginToken(ctx context.Context, deviceCode, clientID, TokenUrl string) (responses.LoginTokenResponse, error) {
 	var resBody responses.LoginTokenResponse
+	var oauthErr OAuthError
 
 	res, err := s.httpClient.
 		SetBaseURL(TokenUrl).
 		R().
+		SetContext(ctx).
 		SetResult(&resBody).
+		SetError(&oauthErr).
 		SetFormData(map[string]string{
 			"grant_type":  "urn:ietf:params:oauth:grant-type:device_code",
 			"device_code": deviceCode,
@@ -92,6 +95,11 @@ func (s *authRepo) LoginToken(ctx context.Context, deviceCode, clientID, TokenUr
 		return responses.LoginTokenResponse{}, fmt.Errorf("failed to get login token: %w", err)
 	}
 
+	if oauthErr.Code != "" {
+		oauthErr.StatusCode = res.StatusCode()
+		return responses.LoginTokenResponse{}, &oauthErr
+	}
+
 	if res.StatusCode() != 200 {
 		return responses.LoginTokenResponse{}, fmt.Errorf("unexpected status code while fetching login token: %d", res.StatusCode())
 	}
@@ -16,6 +16,7 @@ type CertificateRepository interface {
 	GetCA(ctx context.Context) (responses.OrgCAResponse, error)
 	GetRootCA(ctx context.Context) (responses.RootCAResponse, error)
 	IssueMemberCert(ctx context.Context, req requests.IssueMemberCertRequest) (responses.MemberCertResponse, error)
+	IssueMemberCertFromCSR(ctx context.Context, req requests.IssueMemberCertFromCSRRequest) (responses.MemberCertResponse, error)
 	List(ctx context.Context) ([]responses.CertificateResponse, error)
 	Revoke(ctx context.Context, serialHex string, req requests.RevokeCertRequest) (responses.RevokeCertResponse, error)
 	GetCRL(ctx context.Context) (responses.CRLResponse, error)
@@ -84,26 +85,27 @@ func (r *certRepo) IssueMemberCert(ctx context.Context, req requests.IssueMember
 		return responses.MemberCertResponse{}, err
 	}
 
-	metadata := make(map[string]string)
-	for k, v := range resp.Metadata {
-		if v != nil {
-			metadata[k] = *v
-		}
+	return sdkMemberCertToResponse(resp), nil
+}
+
+func (r *certRepo) IssueMemberCertFromCSR(ctx context.Context, req requests.IssueMemberCertFromCSRRequest) (responses.MemberCertResponse, error) {
+	var desc *string
+	if req.Description != "" {
+		desc = &req.Description
 	}
 
-	return responses.MemberCertResponse{
-		ID:           resp.Id,
-		OrgID:        resp.OrgId,
-		SerialHex:    resp.SerialHex,
-		CertType:     resp.CertType,
-		SubjectCN:    resp.SubjectCn,
-		SubjectEmail: resp.SubjectEmail,
-		Status:       resp.Status,
-		Metadata:     metadata,
-		CertPEM:      resp.CertPem,
-		KeyPEM:       resp.KeyPem,
-		CreatedAt:    resp.CreatedAt,
-	}, nil
+	resp, err := r.client.Certificates.IssueMemberCertFromCsr(ctx, &sdk.IssueMemberCertFromCsrRequest{
+		CsrPem:      req.CSRPEM,
+		MemberEmail: req.MemberEmail,
+		Role:        req.Role,
+		Description: desc,
+		Metadata:    req.Metadata,
+	})
+	if err != nil {
+		return responses.MemberCertResponse{}, err
+	}
+
+	return sdkMemberCertToResponse(resp), nil
 }
 
 func (r *certRepo) List(ctx context.Context) ([]responses.CertificateResponse, error) {
@@ -199,3 +201,26 @@ func sdkOrgCaToResponse(resp *sdk.OrgCaResponse) responses.OrgCAResponse {
 		CreatedAt: resp.CreatedAt,
 	}
 }
+
+func sdkMemberCertToResponse(resp *sdk.MemberCertResponse) responses.MemberCertResponse {
+	metadata := make(map[string]string)
+	for k, v := range resp.Metadata {
+		if v != nil {
+			metadata[k] = *v
+		}
+	}
+
+	return responses.MemberCertResponse{
+		ID:           resp.Id,
+		OrgID:        resp.OrgId,
+		SerialHex:    resp.SerialHex,
+		CertType:     resp.CertType,
+		SubjectCN:    resp.SubjectCn,
+		SubjectEmail: resp.SubjectEmail,
+		Status:       resp.Status,
+		Metadata:     metadata,
+		CertPEM:      resp.CertPem,
+		KeyPEM:       resp.KeyPem,
+		CreatedAt:    resp.CreatedAt,
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	sdkclient "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/client"
+)
+
+// ChangeRepository exposes the most recent point-in-time (PIT) entries of an
+// environment. It is used as a lightweight change feed: a new PIT ID means the
+// variables or secrets were modified remotely.
+type ChangeRepository interface {
+	LatestEnvChange(ctx context.Context, appID string, envTypeID string) (string, error)
+	LatestSecretChange(ctx context.Context, appID string, envTypeID string) (string, error)
+}
+
+type changeRepo struct {
+	client *sdkclient.Client
+}
+
+func NewChangeRepository() ChangeRepository {
+	client := createSDKClient()
+
+	return &changeRepo{
+		client: client,
+	}
+}
+
+// LatestEnvChange returns the ID of the newest variable PIT, or an empty string
+// when the environment has no history yet.
+func (c *changeRepo) LatestEnvChange(ctx context.Context, appID, envTypeID string) (string, error) {
+	page, perPage := 1, 1
+	history, err := c.client.EnvironmentVariablesPointInTime.GetEnvHistory(ctx, &sdk.EnvHistoryRequest{
+		AppId:     appID,
+		EnvTypeId: envTypeID,
+		Page:      &page,
+		PerPage:   &perPage,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if history == nil || len(history.Pits) == 0 {
+		return "", nil
+	}
+
+	return history.Pits[0].Id, nil
+}
+
+// LatestSecretChange returns the ID of the newest secret PIT, or an empty string
+// when the environment has no history yet.
+func (c *changeRepo) LatestSecretChange(ctx context.Context, appID, envTypeID string) (string, error) {
+	page, perPage := 1, 1
+	history, err := c.client.SecretsPointInTime.GetSecretHistory(ctx, &sdk.SecretHistoryRequest{
+		AppId:     appID,
+		EnvTypeId: envTypeID,
+		Page:      &page,
+		PerPage:   &perPage,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if history == nil || len(history.Pits) == 0 {
+		return "", nil
+	}
+
+	return history.Pits[0].Id, nil
+}
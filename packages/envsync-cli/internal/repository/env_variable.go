@@ -68,7 +68,7 @@ func (s *syncRepo) BatchCreateEnv(ctx context.Context, env requests.BatchSyncEnv
 		}
 	}
 
-	_, err := s.client.EnvironmentVariables.BatchCreateEnvs(ctx, &sdk.BatchCreateEnvsRequest{
+	_, err := s.client.EnvironmentVariables.BatchCreateEnvsChunked(ctx, &sdk.BatchCreateEnvsRequest{
 		AppId:     env.AppID,
 		EnvTypeId: env.EnvTypeID,
 		Envs:      sdkEnvs,
@@ -85,7 +85,7 @@ func (s *syncRepo) BatchUpdateEnv(ctx context.Context, env requests.BatchSyncEnv
 		}
 	}
 
-	_, err := s.client.EnvironmentVariables.BatchUpdateEnvs(ctx, &sdk.BatchCreateEnvsRequest{
+	_, err := s.client.EnvironmentVariables.BatchUpdateEnvsChunked(ctx, &sdk.BatchCreateEnvsRequest{
 		AppId:     env.AppID,
 		EnvTypeId: env.EnvTypeID,
 		Envs:      sdkEnvs,
@@ -94,7 +94,7 @@ func (s *syncRepo) BatchUpdateEnv(ctx context.Context, env requests.BatchSyncEnv
 }
 
 func (s *syncRepo) BatchDeleteEnv(ctx context.Context, env requests.BatchDeleteRequest) error {
-	_, err := s.client.EnvironmentVariables.DeleteBatchEnv(ctx, &sdk.BatchDeleteEnvsRequest{
+	_, err := s.client.EnvironmentVariables.DeleteBatchEnvChunked(ctx, &sdk.BatchDeleteEnvsRequest{
 		AppId:     env.AppID,
 		EnvTypeId: env.EnvTypeID,
 		Keys:      env.Keys,
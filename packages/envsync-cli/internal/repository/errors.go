@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// IsUnavailable reports whether err means the EnvSync API could not be reached
+// or failed server-side, as opposed to the request being rejected. Callers use
+// it to decide when falling back to locally cached data is appropriate.
+func IsUnavailable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var apiErr *core.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsNetworkError reports whether err means the EnvSync API could not be
+// reached at all.
+func IsNetworkError(err error) bool {
+	if _, ok := core.AsAPIError(err); ok {
+		return false
+	}
+
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+// OAuthError is an error response of the OAuth token endpoint, which answers
+// device code polls that cannot return a token yet with HTTP 400 and an
+// error code (RFC 8628, section 3.5).
+type OAuthError struct {
+	StatusCode  int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Description)
+	}
+	return e.Code
+}
+
+// IsLoginPending reports whether the user has not completed the device login
+// yet, so the token endpoint should be polled again.
+func IsLoginPending(err error) bool {
+	var oauthErr *OAuthError
+	return errors.As(err, &oauthErr) && (oauthErr.Code == "authorization_pending" || oauthErr.Code == "slow_down")
+}
+
+// IsSlowDown reports whether the token endpoint asked to poll less often.
+func IsSlowDown(err error) bool {
+	var oauthErr *OAuthError
+	return errors.As(err, &oauthErr) && oauthErr.Code == "slow_down"
+}
+
+// IsLoginDenied reports whether the token endpoint rejected the device code
+// for good, because the user denied access, it expired or it is invalid.
+func IsLoginDenied(err error) bool {
+	var oauthErr *OAuthError
+	return errors.As(err, &oauthErr) && !IsLoginPending(err)
+}
+
+// IsAccessDenied reports whether the EnvSync API rejected the credentials or
+// permissions of the request.
+func IsAccessDenied(err error) bool {
+	return core.IsUnauthorized(err) || core.IsForbidden(err)
+}
+
+// IsNotImplemented reports whether the EnvSync API does not support the
+// request, such as an endpoint the server has not enabled.
+func IsNotImplemented(err error) bool {
+	apiErr, ok := core.AsAPIError(err)
+	return ok && apiErr.StatusCode == http.StatusNotImplemented
+}
+
+// DescribeError returns a message for err that can be shown to users. For API
+// errors it is the server's message with the rejected fields and the request
+// ID to quote when reporting the problem, rather than the raw response body.
+func DescribeError(err error) string {
+	apiErr, ok := core.AsAPIError(err)
+	if !ok || apiErr.Message == "" {
+		return err.Error()
+	}
+
+	var b strings.Builder
+	b.WriteString(apiErr.Message)
+	if apiErr.RequestID != "" {
+		fmt.Fprintf(&b, " (request ID: %s)", apiErr.RequestID)
+	}
+	for _, field := range apiErr.FieldErrors {
+		if field.Field == "" {
+			fmt.Fprintf(&b, "\n  - %s", field.Message)
+			continue
+		}
+		fmt.Fprintf(&b, "\n  - %s: %s", field.Field, field.Message)
+	}
+
+	return b.String()
+}
@@ -14,6 +14,8 @@ type GpgKeyRepository interface {
 	List(ctx context.Context) ([]responses.GpgKeyResponse, error)
 	Get(ctx context.Context, id string) (responses.GpgKeyResponse, error)
 	Generate(ctx context.Context, req requests.GenerateGpgKeyRequest) (responses.GpgKeyResponse, error)
+	Import(ctx context.Context, req requests.ImportGpgKeyRequest) (responses.GpgKeyResponse, error)
+	UpdateTrust(ctx context.Context, id string, trustLevel string) (responses.GpgKeyResponse, error)
 	Delete(ctx context.Context, id string) error
 	Revoke(ctx context.Context, id string, reason string) (responses.GpgKeyResponse, error)
 	Export(ctx context.Context, id string) (responses.GpgExportResponse, error)
@@ -85,6 +87,36 @@ func (r *gpgKeyRepo) Generate(ctx context.Context, req requests.GenerateGpgKeyRe
 	return sdkGpgKeyToResponse(key), nil
 }
 
+func (r *gpgKeyRepo) Import(ctx context.Context, req requests.ImportGpgKeyRequest) (responses.GpgKeyResponse, error) {
+	key, err := r.client.GpgKeys.ImportGpgKey(ctx, &sdk.ImportGpgKeyRequest{
+		Name:              req.Name,
+		ArmoredPublicKey:  req.ArmoredPublicKey,
+		ArmoredPrivateKey: req.ArmoredPrivateKey,
+		Passphrase:        req.Passphrase,
+	})
+	if err != nil {
+		return responses.GpgKeyResponse{}, err
+	}
+
+	return sdkGpgKeyToResponse(key), nil
+}
+
+func (r *gpgKeyRepo) UpdateTrust(ctx context.Context, id string, trustLevel string) (responses.GpgKeyResponse, error) {
+	level, err := sdk.NewUpdateTrustLevelRequestTrustLevelFromString(trustLevel)
+	if err != nil {
+		return responses.GpgKeyResponse{}, err
+	}
+
+	key, err := r.client.GpgKeys.UpdateGpgKeyTrustLevel(ctx, id, &sdk.UpdateTrustLevelRequest{
+		TrustLevel: level,
+	})
+	if err != nil {
+		return responses.GpgKeyResponse{}, err
+	}
+
+	return sdkGpgKeyDetailToResponse(key), nil
+}
+
 func (r *gpgKeyRepo) Delete(ctx context.Context, id string) error {
 	_, err := r.client.GpgKeys.DeleteGpgKey(ctx, id)
 	return err
@@ -211,5 +243,7 @@ func sdkGpgKeyDetailToResponse(k *sdk.GpgKeyDetailResponse) responses.GpgKeyResp
 		PublicKey:   k.PublicKey,
 		CreatedAt:   k.CreatedAt,
 		UpdatedAt:   k.UpdatedAt,
+
+		RevocationReason: k.RevocationReason,
 	}
 }
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"crypto/x509"
+	"fmt"
 	"net/http"
 	"os"
 
@@ -9,6 +11,8 @@ import (
 
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/config"
 	sdkclient "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/client"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/middleware"
 	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
 )
 
@@ -30,9 +34,7 @@ func createSDKClient() *sdkclient.Client {
 	opts := []option.RequestOption{
 		option.WithBaseURL(cfg.BackendURL),
 		option.WithHTTPHeader(headers),
-		option.WithHTTPClient(&http.Client{
-			Transport: otelhttp.NewTransport(http.DefaultTransport),
-		}),
+		option.WithMiddleware(middleware.Tracing(nil)),
 	}
 
 	if hasAPIKey && apiKey != "" {
@@ -40,10 +42,44 @@ func createSDKClient() *sdkclient.Client {
 	} else if cfg.AccessToken != "" {
 		opts = append(opts, option.WithToken(cfg.AccessToken))
 	}
+	opts = append(opts, tlsOptions(cfg)...)
 
 	return sdkclient.NewClient(opts...)
 }
 
+// tlsOptions returns the options that authenticate with the configured client
+// certificate and verify the backend with the configured CA certificates.
+// Files that cannot be read fail every request with the read error.
+func tlsOptions(cfg config.AppConfig) []option.RequestOption {
+	var opts []option.RequestOption
+
+	if cfg.ClientCert != "" {
+		certPEM, err := os.ReadFile(cfg.ClientCert)
+		if err != nil {
+			return []option.RequestOption{&core.TLSOption{Err: fmt.Errorf("failed to read client certificate: %w", err)}}
+		}
+		keyPEM, err := os.ReadFile(cfg.ClientKey)
+		if err != nil {
+			return []option.RequestOption{&core.TLSOption{Err: fmt.Errorf("failed to read client key: %w", err)}}
+		}
+		opts = append(opts, option.WithClientCertificate(certPEM, keyPEM))
+	}
+
+	if cfg.CACert != "" {
+		data, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return []option.RequestOption{&core.TLSOption{Err: fmt.Errorf("failed to read CA certificates: %w", err)}}
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return []option.RequestOption{&core.TLSOption{Err: fmt.Errorf("no CA certificates found in %s", cfg.CACert)}}
+		}
+		opts = append(opts, option.WithRootCAs(pool))
+	}
+
+	return opts
+}
+
 // createHTTPClient initializes and returns a new HTTP client with proper authentication
 // and configuration for API requests. Used only for auth login flows.
 func createHTTPClient() *resty.Client {
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/config"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/testutil"
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/envsynctest"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+)
+
+// server is shared by all tests in the package because the CLI configuration,
+// and with it the backend URL, is only loaded once per process.
+var server *envsynctest.Server
+
+func TestMain(m *testing.M) {
+	server = envsynctest.NewServer(envsynctest.WithMutualTLS())
+
+	dir, err := testutil.Home()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create temp dir: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := useClientCertificate(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to configure fake server: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	server.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// useClientCertificate issues a member certificate and points the CLI
+// configuration at the server, authenticating with the certificate only.
+func useClientCertificate(dir string) error {
+	os.Unsetenv("API_KEY")
+
+	admin := server.Client(option.WithApiKey("admin-key"))
+	ctx := context.Background()
+	if _, err := admin.Certificates.InitOrgCa(ctx, &sdk.InitOrgCaRequest{OrgName: "Acme"}); err != nil {
+		return err
+	}
+	cert, err := admin.Certificates.IssueMemberCert(ctx, &sdk.IssueMemberCertRequest{
+		MemberEmail: "deploy@example.com",
+		Role:        "service",
+	})
+	if err != nil {
+		return err
+	}
+
+	cfg := config.AppConfig{
+		BackendURL: server.URL,
+		ClientCert: filepath.Join(dir, "service.crt"),
+		ClientKey:  filepath.Join(dir, "service.key"),
+		CACert:     filepath.Join(dir, "ca.pem"),
+	}
+	files := map[string]string{
+		cfg.ClientCert: cert.CertPem,
+		cfg.ClientKey:  cert.KeyPem,
+		cfg.CACert:     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			return err
+		}
+	}
+
+	return testutil.WriteConfig(cfg)
+}
+
+func TestClientCertificateAuthentication(t *testing.T) {
+	server.ResetRequests()
+
+	if _, err := NewCertificateRepository().GetCA(context.Background()); err != nil {
+		t.Fatalf("GetCA() error = %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	if cert := requests[0].ClientCertificate; cert == nil || cert.Subject.CommonName != "deploy@example.com" {
+		t.Errorf("request was not authenticated with the member certificate")
+	}
+}
+
+func TestTLSOptionsUnreadableFiles(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]struct {
+		cfg  config.AppConfig
+		want string
+	}{
+		"missing certificate": {
+			config.AppConfig{ClientCert: filepath.Join(dir, "missing.crt"), ClientKey: filepath.Join(dir, "missing.key")},
+			"failed to read client certificate",
+		},
+		"missing CA": {
+			config.AppConfig{CACert: filepath.Join(dir, "missing.pem")},
+			"failed to read CA certificates",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := server.Client(tlsOptions(tt.cfg)...)
+			_, err := client.Certificates.GetOrgCa(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("GetOrgCa() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,14 @@ type IssueMemberCertRequest struct {
 	Metadata    map[string]string `json:"metadata,omitempty"`
 }
 
+type IssueMemberCertFromCSRRequest struct {
+	CSRPEM      string            `json:"csr_pem"`
+	MemberEmail string            `json:"member_email"`
+	Role        string            `json:"role"`
+	Description string            `json:"description,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
 type RevokeCertRequest struct {
 	Reason int `json:"reason"`
 }
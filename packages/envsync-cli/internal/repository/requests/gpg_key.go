@@ -10,6 +10,13 @@ type GenerateGpgKeyRequest struct {
 	IsDefault     bool     `json:"is_default"`
 }
 
+type ImportGpgKeyRequest struct {
+	Name              string  `json:"name"`
+	ArmoredPublicKey  string  `json:"armored_public_key"`
+	ArmoredPrivateKey *string `json:"armored_private_key,omitempty"`
+	Passphrase        *string `json:"passphrase,omitempty"`
+}
+
 type SignDataRequest struct {
 	GpgKeyID string `json:"gpg_key_id"`
 	Data     string `json:"data"`
@@ -16,6 +16,8 @@ type GpgKeyResponse struct {
 	PublicKey   string   `json:"public_key,omitempty"`
 	CreatedAt   string   `json:"created_at"`
 	UpdatedAt   string   `json:"updated_at"`
+
+	RevocationReason *string `json:"revocation_reason,omitempty"`
 }
 
 type GpgSignatureResponse struct {
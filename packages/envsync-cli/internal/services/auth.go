@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,6 +12,10 @@ import (
 	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository"
 )
 
+// ErrAuthorizationTimeout is returned when the user does not complete the
+// device login before the device code expires.
+var ErrAuthorizationTimeout = errors.New("authentication timeout")
+
 type AuthService interface {
 	InitiateLogin(ctx context.Context) (*domain.LoginCredentials, error)
 	CompleteLogin(ctx context.Context, credentials *domain.LoginCredentials) (*domain.AccessToken, error)
@@ -69,12 +74,22 @@ func (s *auth) PollForToken(ctx context.Context, credentials *domain.LoginCreden
 			return token, nil
 		}
 
-		// If it's not an authentication pending error, return the error
-		// In a real implementation, you'd check for specific error types
-		time.Sleep(interval)
+		// The device code was rejected, so polling again cannot succeed
+		if repository.IsLoginDenied(err) {
+			return nil, err
+		}
+		if repository.IsSlowDown(err) {
+			interval += 5 * time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
 	}
 
-	return nil, fmt.Errorf("authentication timeout: user did not complete login within %d seconds", credentials.ExpiresIn)
+	return nil, fmt.Errorf("%w: user did not complete login within %d seconds", ErrAuthorizationTimeout, credentials.ExpiresIn)
 }
 
 // SaveToken persists the access token to configuration
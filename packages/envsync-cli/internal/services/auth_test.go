@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	// The repositories load the CLI configuration from the home directory
+	dir, err := testutil.Home()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create temp dir: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// newTokenServer answers device code polls with the given OAuth error codes
+// in turn, and with a token once they are used up.
+func newTokenServer(t *testing.T, codes ...string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		n := int(polls.Add(1))
+		if n <= len(codes) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error":%q,"error_description":"device flow"}`, codes[n-1])
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"token-1","token_type":"Bearer"}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, &polls
+}
+
+func TestPollForToken(t *testing.T) {
+	server, polls := newTokenServer(t, "authorization_pending", "authorization_pending")
+	s := &auth{repo: repository.NewAuthRepository()}
+
+	token, err := s.PollForToken(context.Background(), &domain.LoginCredentials{
+		DeviceCode: "device-1",
+		ExpiresIn:  10,
+		TokenUrl:   server.URL,
+	})
+	if err != nil {
+		t.Fatalf("PollForToken returned error: %v", err)
+	}
+	if token.Token != "token-1" {
+		t.Errorf("unexpected token %q", token.Token)
+	}
+	if n := polls.Load(); n != 3 {
+		t.Errorf("expected 3 polls, got %d", n)
+	}
+}
+
+func TestPollForTokenStopsWhenDenied(t *testing.T) {
+	for _, code := range []string{"access_denied", "expired_token"} {
+		t.Run(code, func(t *testing.T) {
+			server, polls := newTokenServer(t, "authorization_pending", code, "authorization_pending")
+			s := &auth{repo: repository.NewAuthRepository()}
+
+			start := time.Now()
+			_, err := s.PollForToken(context.Background(), &domain.LoginCredentials{
+				DeviceCode: "device-1",
+				ExpiresIn:  10,
+				TokenUrl:   server.URL,
+			})
+			if !IsLoginDenied(err) {
+				t.Fatalf("expected the login to be denied, got %v", err)
+			}
+			if n := polls.Load(); n != 2 {
+				t.Errorf("expected polling to stop after 2 polls, got %d", n)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("polling did not stop promptly (took %s)", elapsed)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ type CertificateService interface {
 	GetCA(ctx context.Context) (domain.Certificate, error)
 	GetRootCA(ctx context.Context) (string, error)
 	IssueMemberCert(ctx context.Context, req requests.IssueMemberCertRequest) (domain.Certificate, error)
+	IssueMemberCertFromCSR(ctx context.Context, req requests.IssueMemberCertFromCSRRequest) (domain.Certificate, error)
 	ListCerts(ctx context.Context) ([]domain.Certificate, error)
 	RevokeCert(ctx context.Context, serialHex string, reason int) (responses.RevokeCertResponse, error)
 	GetCRL(ctx context.Context) (domain.CRLResult, error)
@@ -62,6 +63,14 @@ func (s *certService) IssueMemberCert(ctx context.Context, req requests.IssueMem
 	return mappers.MemberCertResponseToDomain(res), nil
 }
 
+func (s *certService) IssueMemberCertFromCSR(ctx context.Context, req requests.IssueMemberCertFromCSRRequest) (domain.Certificate, error) {
+	res, err := s.repo.IssueMemberCertFromCSR(ctx, req)
+	if err != nil {
+		return domain.Certificate{}, err
+	}
+	return mappers.MemberCertResponseToDomain(res), nil
+}
+
 func (s *certService) ListCerts(ctx context.Context) ([]domain.Certificate, error) {
 	res, err := s.repo.List(ctx)
 	if err != nil {
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository"
+)
+
+type ChangeService interface {
+	// GetRevision returns an opaque token identifying the current state of an
+	// environment. The token changes whenever a variable (or, when
+	// includeSecrets is set, a secret) is created, updated or deleted.
+	GetRevision(ctx context.Context, appID string, envTypeID string, includeSecrets bool) (string, error)
+}
+
+type changeService struct {
+	repo repository.ChangeRepository
+}
+
+func NewChangeService() ChangeService {
+	repo := repository.NewChangeRepository()
+	return &changeService{
+		repo: repo,
+	}
+}
+
+func (s *changeService) GetRevision(ctx context.Context, appID, envTypeID string, includeSecrets bool) (string, error) {
+	envRev, err := s.repo.LatestEnvChange(ctx, appID, envTypeID)
+	if err != nil {
+		return "", err
+	}
+
+	if !includeSecrets {
+		return envRev, nil
+	}
+
+	secretRev, err := s.repo.LatestSecretChange(ctx, appID, envTypeID)
+	if err != nil {
+		return "", err
+	}
+
+	return envRev + ":" + secretRev, nil
+}
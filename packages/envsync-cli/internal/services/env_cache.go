@@ -0,0 +1,259 @@
+package services
+
+import (
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/config"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/utils"
+)
+
+var (
+	ErrEnvCacheNotFound = errors.New("no cached environment found")
+	ErrEnvCacheStale    = errors.New("cached environment is too old")
+	ErrNoCacheKey       = errors.New("no credentials to derive the cache key from")
+)
+
+// cacheKeyInfo binds the wrapping key to this use of the credential
+const cacheKeyInfo = "envsync offline environment cache key v1"
+
+// EnvCacheService stores the last fetched environment of each app/env pair in
+// the user cache directory, encrypted with AES-GCM under a random cache key.
+// The cache key is stored next to the entries, wrapped with a key derived from
+// the credential the CLI authenticates with: API_KEY, the access token of
+// 'envsync login' or the key of the client certificate.
+//
+// A copy of the cache directory, a backup or another user account cannot read
+// the entries without the credential. It does not protect against anyone who
+// can read the credential, such as a process of the same user reading the CLI
+// configuration; that credential fetches the same secrets from the API
+// anyway. Logging in again rewraps the cache key for the new access token, so
+// the entries stay readable. With another API key they cannot be opened and
+// are replaced on the next fetch.
+type EnvCacheService interface {
+	Save(entry domain.EnvCache) error
+	// Load returns the cached entry. An entry older than the maximum
+	// staleness is returned together with ErrEnvCacheStale, so callers that
+	// refresh part of it can keep the rest.
+	Load(appID string, envTypeID string) (*domain.EnvCache, error)
+	// RewrapKey makes the cache key readable with the credential used once
+	// accessToken is saved, before it replaces the current one.
+	RewrapKey(accessToken string) error
+}
+
+type envCache struct {
+	cacheDir     string
+	keyPath      string
+	maxStaleness time.Duration
+	cfg          config.AppConfig
+	// credential returns the secret the cache key is wrapped with
+	credential func() ([]byte, error)
+}
+
+func NewEnvCacheService() EnvCacheService {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+
+	cfg := config.New()
+	return &envCache{
+		cacheDir:     filepath.Join(cacheDir, "envsync", "env"),
+		keyPath:      filepath.Join(cacheDir, "envsync", "env.key"),
+		maxStaleness: cfg.MaxStaleness(),
+		cfg:          cfg,
+		credential:   func() ([]byte, error) { return cacheCredential(cfg) },
+	}
+}
+
+func (c *envCache) Save(entry domain.EnvCache) error {
+	key, err := c.saveKey()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cached environment: %w", err)
+	}
+
+	sealed, err := utils.SealAESGCM(key, data, cacheAAD(entry.AppID, entry.EnvTypeID))
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.cacheDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	if err := utils.WriteFileAtomic(c.entryPath(entry.AppID, entry.EnvTypeID), sealed, 0o600); err != nil {
+		return fmt.Errorf("failed to write cached environment: %w", err)
+	}
+
+	return nil
+}
+
+func (c *envCache) Load(appID, envTypeID string) (*domain.EnvCache, error) {
+	sealed, err := os.ReadFile(c.entryPath(appID, envTypeID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrEnvCacheNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached environment: %w", err)
+	}
+
+	key, err := c.loadKey()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEnvCacheNotFound, err)
+	}
+
+	data, err := utils.OpenAESGCM(key, sealed, cacheAAD(appID, envTypeID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: the entry was written with another cache key", ErrEnvCacheNotFound)
+	}
+
+	var entry domain.EnvCache
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode cached environment: %w", err)
+	}
+
+	if age := time.Since(entry.OldestFetch()); age > c.maxStaleness {
+		return &entry, fmt.Errorf("%w: fetched %s ago, limit is %s (see 'envsync config set cache_max_staleness=<duration>')",
+			ErrEnvCacheStale, age.Round(time.Minute), c.maxStaleness)
+	}
+
+	return &entry, nil
+}
+
+func (c *envCache) entryPath(appID, envTypeID string) string {
+	sum := sha256.Sum256(cacheAAD(appID, envTypeID))
+	return filepath.Join(c.cacheDir, hex.EncodeToString(sum[:])+".cache")
+}
+
+func (c *envCache) RewrapKey(accessToken string) error {
+	key, err := c.loadKey()
+	if err != nil {
+		// Nothing readable to keep, the next fetch starts a new cache
+		return nil
+	}
+
+	cfg := c.cfg
+	cfg.AccessToken = accessToken
+	credential, err := cacheCredential(cfg)
+	if err != nil {
+		return err
+	}
+
+	return c.writeKey(key, credential)
+}
+
+// loadKey unwraps the cache key with the current credential.
+func (c *envCache) loadKey() ([]byte, error) {
+	credential, err := c.credential()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := os.ReadFile(c.keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache key: %w", err)
+	}
+
+	kek, err := wrappingKey(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := utils.OpenAESGCM(kek, wrapped, []byte(cacheKeyInfo))
+	if err != nil {
+		return nil, errors.New("the cache key was wrapped with other credentials")
+	}
+	return key, nil
+}
+
+// saveKey returns the cache key, or generates a new one when there is none
+// the current credential can unwrap. Entries written with an earlier key are
+// replaced as the environments are fetched again.
+func (c *envCache) saveKey() ([]byte, error) {
+	credential, err := c.credential()
+	if err != nil {
+		return nil, err
+	}
+	if key, err := c.loadKey(); err == nil {
+		return key, nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate cache key: %w", err)
+	}
+	if err := c.writeKey(key, credential); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// writeKey stores key wrapped with credential.
+func (c *envCache) writeKey(key, credential []byte) error {
+	kek, err := wrappingKey(credential)
+	if err != nil {
+		return err
+	}
+
+	wrapped, err := utils.SealAESGCM(kek, key, []byte(cacheKeyInfo))
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.keyPath), 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := utils.WriteFileAtomic(c.keyPath, wrapped, 0o600); err != nil {
+		return fmt.Errorf("failed to write cache key: %w", err)
+	}
+	return nil
+}
+
+// wrappingKey derives the key that wraps the cache key from credential.
+func wrappingKey(credential []byte) ([]byte, error) {
+	return hkdf.Key(sha256.New, credential, nil, cacheKeyInfo, 32)
+}
+
+// cacheCredential returns the credential the CLI authenticates with, in the
+// order the API client uses them.
+func cacheCredential(cfg config.AppConfig) ([]byte, error) {
+	if apiKey := os.Getenv("API_KEY"); apiKey != "" {
+		return []byte(apiKey), nil
+	}
+	if cfg.AccessToken != "" {
+		return []byte(cfg.AccessToken), nil
+	}
+	if cfg.ClientKey != "" {
+		key, err := os.ReadFile(cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client key: %w", err)
+		}
+		return key, nil
+	}
+	return nil, ErrNoCacheKey
+}
+
+// IsBackendUnavailable reports whether err means the API could not be reached,
+// in which case the cached environment may be used instead.
+func IsBackendUnavailable(err error) bool {
+	return repository.IsUnavailable(err)
+}
+
+func cacheAAD(appID, envTypeID string) []byte {
+	return []byte(appID + "/" + envTypeID)
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+)
+
+func newTestEnvCache(t *testing.T, maxStaleness time.Duration) *envCache {
+	t.Helper()
+	dir := t.TempDir()
+	return &envCache{
+		cacheDir:     filepath.Join(dir, "cache"),
+		keyPath:      filepath.Join(dir, "cache.key"),
+		maxStaleness: maxStaleness,
+		credential:   func() ([]byte, error) { return []byte("test-api-key"), nil },
+	}
+}
+
+func TestEnvCacheRoundTrip(t *testing.T) {
+	cache := newTestEnvCache(t, time.Hour)
+
+	entry := domain.EnvCache{
+		AppID:            "app-1",
+		EnvTypeID:        "dev",
+		Variables:        map[string]string{"PORT": "8080"},
+		Secrets:          map[string]string{"DB_PASSWORD": "hunter2"},
+		FetchedAt:        time.Now(),
+		SecretsFetchedAt: time.Now(),
+	}
+
+	if err := cache.Save(entry); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	loaded, err := cache.Load("app-1", "dev")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	merged := loaded.Merged()
+	if merged["PORT"] != "8080" || merged["DB_PASSWORD"] != "hunter2" {
+		t.Errorf("unexpected cached environment: %v", merged)
+	}
+
+	// Nothing from the environment may be readable on disk
+	files, _ := filepath.Glob(filepath.Join(cache.cacheDir, "*.cache"))
+	if len(files) != 1 {
+		t.Fatalf("expected one cache file, got %d", len(files))
+	}
+	raw, _ := os.ReadFile(files[0])
+	if strings.Contains(string(raw), "hunter2") || strings.Contains(string(raw), "PORT") {
+		t.Error("cache file contains plaintext environment data")
+	}
+
+	// Only the entry and the wrapped cache key are stored
+	err = filepath.WalkDir(filepath.Dir(cache.cacheDir), func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && filepath.Ext(path) != ".cache" && path != cache.keyPath {
+			t.Errorf("unexpected file %s next to the cache", path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnvCacheLoadErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		fetchedAt time.Duration
+		loadApp   string
+		expectErr error
+	}{
+		{
+			name:      "missing entry",
+			fetchedAt: 0,
+			loadApp:   "other-app",
+			expectErr: ErrEnvCacheNotFound,
+		},
+		{
+			name:      "stale entry",
+			fetchedAt: -2 * time.Hour,
+			loadApp:   "app-1",
+			expectErr: ErrEnvCacheStale,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newTestEnvCache(t, time.Hour)
+
+			err := cache.Save(domain.EnvCache{
+				AppID:     "app-1",
+				EnvTypeID: "dev",
+				Variables: map[string]string{"PORT": "8080"},
+				FetchedAt: time.Now().Add(tt.fetchedAt),
+			})
+			if err != nil {
+				t.Fatalf("Save returned error: %v", err)
+			}
+
+			entry, err := cache.Load(tt.loadApp, "dev")
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("expected %v, got %v", tt.expectErr, err)
+			}
+			// A stale entry is still returned so callers can keep parts of it
+			if errors.Is(err, ErrEnvCacheStale) && (entry == nil || entry.Variables["PORT"] != "8080") {
+				t.Errorf("expected the stale entry with the error, got %v", entry)
+			}
+		})
+	}
+}
+
+func TestEnvCacheRejectsOtherCredentials(t *testing.T) {
+	cache := newTestEnvCache(t, time.Hour)
+
+	err := cache.Save(domain.EnvCache{
+		AppID:     "app-1",
+		EnvTypeID: "dev",
+		Variables: map[string]string{"PORT": "8080"},
+		FetchedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	// Another user, or another API key
+	cache.credential = func() ([]byte, error) { return []byte("other-api-key"), nil }
+	if _, err := cache.Load("app-1", "dev"); !errors.Is(err, ErrEnvCacheNotFound) {
+		t.Errorf("expected %v with other credentials, got %v", ErrEnvCacheNotFound, err)
+	}
+
+	cache.credential = func() ([]byte, error) { return nil, ErrNoCacheKey }
+	if err := cache.Save(domain.EnvCache{AppID: "app-1", EnvTypeID: "dev", FetchedAt: time.Now()}); !errors.Is(err, ErrNoCacheKey) {
+		t.Errorf("expected %v without credentials, got %v", ErrNoCacheKey, err)
+	}
+}
+
+func TestEnvCacheRewrapKeyOnLogin(t *testing.T) {
+	t.Setenv("API_KEY", "")
+	cache := newTestEnvCache(t, time.Hour)
+	cache.credential = func() ([]byte, error) { return []byte("old-token"), nil }
+
+	err := cache.Save(domain.EnvCache{
+		AppID:     "app-1",
+		EnvTypeID: "dev",
+		Variables: map[string]string{"PORT": "8080"},
+		FetchedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	if err := cache.RewrapKey("new-token"); err != nil {
+		t.Fatalf("RewrapKey returned error: %v", err)
+	}
+
+	cache.credential = func() ([]byte, error) { return []byte("new-token"), nil }
+	entry, err := cache.Load("app-1", "dev")
+	if err != nil {
+		t.Fatalf("Load after logging in again returned error: %v", err)
+	}
+	if entry.Variables["PORT"] != "8080" {
+		t.Errorf("unexpected cached environment: %v", entry.Variables)
+	}
+}
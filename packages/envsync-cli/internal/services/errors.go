@@ -0,0 +1,33 @@
+package services
+
+import (
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/repository"
+)
+
+// IsNetworkError reports whether err means the API could not be reached.
+func IsNetworkError(err error) bool {
+	return repository.IsNetworkError(err)
+}
+
+// IsAccessDenied reports whether the API rejected the credentials or
+// permissions of the request.
+func IsAccessDenied(err error) bool {
+	return repository.IsAccessDenied(err)
+}
+
+// IsLoginDenied reports whether the device login was denied, expired or
+// rejected, so polling for the token cannot succeed.
+func IsLoginDenied(err error) bool {
+	return repository.IsLoginDenied(err)
+}
+
+// IsNotImplemented reports whether the API does not support the request.
+func IsNotImplemented(err error) bool {
+	return repository.IsNotImplemented(err)
+}
+
+// DescribeError returns a user-facing message for err, using the structured
+// details of API errors instead of the raw response.
+func DescribeError(err error) string {
+	return repository.DescribeError(err)
+}
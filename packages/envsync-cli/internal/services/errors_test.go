@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+func TestDescribeError(t *testing.T) {
+	validation := core.NewAPIError(http.StatusBadRequest, errors.New(`{"error":{"issues":[]}}`))
+	validation.Message = "invalid request"
+	validation.RequestID = "req-1"
+	validation.FieldErrors = []core.FieldError{
+		{Field: "name", Message: "Required"},
+		{Message: "Unknown key"},
+	}
+
+	notFound := core.NewAPIError(http.StatusNotFound, errors.New(`{"error":"App not found"}`))
+	notFound.Message = "App not found"
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"plain error", errors.New("boom"), "boom"},
+		{"api error without details", core.NewAPIError(http.StatusBadGateway, errors.New("Bad Gateway")), "502: Bad Gateway"},
+		{"wrapped api error", fmt.Errorf("failed to get app: %w", notFound), "App not found"},
+		{"field errors", validation, "invalid request (request ID: req-1)\n  - name: Required\n  - Unknown key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DescribeError(tt.err); got != tt.want {
+				t.Errorf("DescribeError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAccessDenied(t *testing.T) {
+	if !IsAccessDenied(fmt.Errorf("wrapped: %w", core.NewAPIError(http.StatusForbidden, nil))) {
+		t.Error("expected 403 to be access denied")
+	}
+	if IsAccessDenied(core.NewAPIError(http.StatusNotFound, nil)) {
+		t.Error("expected 404 not to be access denied")
+	}
+	if IsNetworkError(core.NewAPIError(http.StatusInternalServerError, nil)) {
+		t.Error("expected API error not to be a network error")
+	}
+}
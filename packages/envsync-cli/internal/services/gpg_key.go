@@ -13,6 +13,8 @@ type GpgKeyService interface {
 	ListKeys(ctx context.Context) ([]domain.GpgKey, error)
 	GetKey(ctx context.Context, id string) (domain.GpgKey, error)
 	GenerateKey(ctx context.Context, req requests.GenerateGpgKeyRequest) (domain.GpgKey, error)
+	ImportKey(ctx context.Context, req requests.ImportGpgKeyRequest) (domain.GpgKey, error)
+	UpdateTrust(ctx context.Context, id string, trustLevel string) (domain.GpgKey, error)
 	DeleteKey(ctx context.Context, id string) error
 	RevokeKey(ctx context.Context, id string, reason string) (domain.GpgKey, error)
 	ExportKey(ctx context.Context, id string) (string, string, error)
@@ -58,6 +60,22 @@ func (s *gpgKeyService) GenerateKey(ctx context.Context, req requests.GenerateGp
 	return mappers.GpgKeyResponseToDomain(res), nil
 }
 
+func (s *gpgKeyService) ImportKey(ctx context.Context, req requests.ImportGpgKeyRequest) (domain.GpgKey, error) {
+	res, err := s.repo.Import(ctx, req)
+	if err != nil {
+		return domain.GpgKey{}, err
+	}
+	return mappers.GpgKeyResponseToDomain(res), nil
+}
+
+func (s *gpgKeyService) UpdateTrust(ctx context.Context, id string, trustLevel string) (domain.GpgKey, error) {
+	res, err := s.repo.UpdateTrust(ctx, id, trustLevel)
+	if err != nil {
+		return domain.GpgKey{}, err
+	}
+	return mappers.GpgKeyResponseToDomain(res), nil
+}
+
 func (s *gpgKeyService) DeleteKey(ctx context.Context, id string) error {
 	return s.repo.Delete(ctx, id)
 }
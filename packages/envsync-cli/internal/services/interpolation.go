@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrUndefinedReference = errors.New("undefined variable reference")
+	ErrReferenceCycle     = errors.New("variable reference cycle")
+	ErrInvalidReference   = errors.New("invalid variable reference")
+	ErrUnknownEnvironment = errors.New("unknown environment in reference")
+)
+
+// referencePattern matches the inside of ${...}: an optional env:NAME. prefix,
+// the key and an optional :-default
+var referencePattern = regexp.MustCompile(`^(?:env:([^.{}]+)\.)?([A-Za-z_][A-Za-z0-9_]*)(?::-(.*))?$`)
+
+// InterpolationService expands ${KEY} references in environment values.
+//
+// Supported forms are ${KEY}, ${KEY:-default} (used when KEY is unset or
+// empty), ${env:NAME.KEY} for a variable of another environment type of the
+// app, and $${ for a literal "${".
+//
+// Values written before references existed may contain a "${" that was never
+// meant as one, so by default a reference that cannot be resolved, because it
+// is malformed, unterminated, undefined or names an unknown environment, is
+// kept verbatim. Strict mode turns these into errors. Cycles are always
+// errors.
+type InterpolationService interface {
+	// Resolve expands all references. Variables whose expanded value contains
+	// a secret are returned with the secrets. The references kept verbatim are
+	// returned for warnings; with strict, they are errors instead.
+	Resolve(ctx context.Context, appID string, variables, secrets map[string]string, strict bool) (map[string]string, map[string]string, []string, error)
+	// ResolveKnown expands the references that can be resolved from the
+	// variables alone and leaves all others untouched, for when the secrets
+	// are not available.
+	ResolveKnown(ctx context.Context, appID string, variables map[string]string) (map[string]string, error)
+}
+
+type interpolation struct {
+	envTypeService EnvTypeService
+}
+
+func NewInterpolationService() InterpolationService {
+	envTypeService := NewEnvTypeService()
+	return &interpolation{
+		envTypeService: envTypeService,
+	}
+}
+
+func (s *interpolation) Resolve(ctx context.Context, appID string, variables, secrets map[string]string, strict bool) (map[string]string, map[string]string, []string, error) {
+	env := make(map[string]string, len(variables)+len(secrets))
+	for key, value := range variables {
+		env[key] = value
+	}
+	for key, value := range secrets {
+		env[key] = value
+	}
+
+	in := newInterpolator(env, s.loader(ctx, appID), true, strict)
+	for key := range secrets {
+		in.sensitive[reference{key: key}] = true
+	}
+
+	resolvedVariables := make(map[string]string, len(variables))
+	resolvedSecrets := make(map[string]string, len(secrets))
+	for key := range env {
+		value, sensitive, err := in.lookup(reference{key: key}, nil)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if sensitive {
+			resolvedSecrets[key] = value
+		} else {
+			resolvedVariables[key] = value
+		}
+	}
+
+	unresolved := make([]string, 0, len(in.unresolved))
+	for literal := range in.unresolved {
+		unresolved = append(unresolved, literal)
+	}
+	sort.Strings(unresolved)
+
+	return resolvedVariables, resolvedSecrets, unresolved, nil
+}
+
+func (s *interpolation) ResolveKnown(ctx context.Context, appID string, variables map[string]string) (map[string]string, error) {
+	in := newInterpolator(variables, s.loader(ctx, appID), false, false)
+
+	resolved := make(map[string]string, len(variables))
+	for key := range variables {
+		value, _, err := in.lookup(reference{key: key}, nil)
+		if err != nil {
+			return nil, err
+		}
+		resolved[key] = value
+	}
+
+	return resolved, nil
+}
+
+// loader fetches the variables of another environment type of the app by name
+func (s *interpolation) loader(ctx context.Context, appID string) func(string) (map[string]string, error) {
+	return func(name string) (map[string]string, error) {
+		envTypes, err := s.envTypeService.GetEnvTypesByAppID(ctx, appID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, envType := range envTypes {
+			if !strings.EqualFold(envType.Name, name) {
+				continue
+			}
+
+			remoteEnv, err := NewSyncServiceForTarget(appID, envType.ID).ReadRemoteEnv(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			env := make(map[string]string, len(remoteEnv))
+			for _, e := range remoteEnv {
+				env[e.Key] = e.Value
+			}
+			return env, nil
+		}
+
+		return nil, fmt.Errorf("%w: '%s'", ErrUnknownEnvironment, name)
+	}
+}
+
+// reference identifies a key in the environment being resolved (env "") or in
+// another environment type
+type reference struct {
+	env string
+	key string
+}
+
+func (r reference) String() string {
+	if r.env == "" {
+		return r.key
+	}
+	return "env:" + r.env + "." + r.key
+}
+
+type interpolator struct {
+	envs map[string]map[string]string
+	load func(string) (map[string]string, error)
+	// complete is set when the secrets are part of the environment, so a key
+	// missing from it is undefined rather than possibly a secret
+	complete bool
+	// strict fails on references that cannot be resolved instead of keeping
+	// them verbatim
+	strict     bool
+	resolved   map[reference]string
+	sensitive  map[reference]bool
+	unresolved map[string]bool
+}
+
+func newInterpolator(env map[string]string, load func(string) (map[string]string, error), complete, strict bool) *interpolator {
+	return &interpolator{
+		envs:       map[string]map[string]string{"": env},
+		load:       load,
+		complete:   complete,
+		strict:     strict,
+		resolved:   make(map[reference]string),
+		sensitive:  make(map[reference]bool),
+		unresolved: make(map[string]bool),
+	}
+}
+
+// lookup returns the expanded value of ref, whether it is built from a secret,
+// and an error for cycles. A missing key is reported as ErrUndefinedReference.
+func (in *interpolator) lookup(ref reference, stack []reference) (string, bool, error) {
+	if value, ok := in.resolved[ref]; ok {
+		return value, in.sensitive[ref], nil
+	}
+
+	for i, r := range stack {
+		if r == ref {
+			chain := make([]string, 0, len(stack)-i+1)
+			for _, s := range stack[i:] {
+				chain = append(chain, s.String())
+			}
+			chain = append(chain, ref.String())
+			return "", false, fmt.Errorf("%w: %s", ErrReferenceCycle, strings.Join(chain, " -> "))
+		}
+	}
+
+	env, err := in.environment(ref.env)
+	if err != nil {
+		return "", false, err
+	}
+
+	raw, ok := env[ref.key]
+	if !ok {
+		return "", false, fmt.Errorf("%w: ${%s}", ErrUndefinedReference, ref)
+	}
+
+	value, sensitive, err := in.expand(raw, ref.env, append(stack, ref))
+	if err != nil {
+		return "", false, err
+	}
+
+	in.resolved[ref] = value
+	in.sensitive[ref] = in.sensitive[ref] || sensitive
+
+	return value, in.sensitive[ref], nil
+}
+
+func (in *interpolator) environment(name string) (map[string]string, error) {
+	key := strings.ToUpper(name)
+	if env, ok := in.envs[key]; ok {
+		return env, nil
+	}
+
+	env, err := in.load(name)
+	if err != nil {
+		return nil, err
+	}
+	in.envs[key] = env
+
+	return env, nil
+}
+
+// expand replaces the references in value. References without an env: prefix
+// are looked up in envName, the environment the value belongs to.
+func (in *interpolator) expand(value, envName string, stack []reference) (string, bool, error) {
+	var b strings.Builder
+	sensitive := false
+
+	for i := 0; i < len(value); {
+		if strings.HasPrefix(value[i:], "$${") {
+			b.WriteString("${")
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(value[i:], "${") {
+			b.WriteByte(value[i])
+			i++
+			continue
+		}
+
+		end := closingBrace(value, i+2)
+		if end < 0 {
+			if in.strict {
+				return "", false, fmt.Errorf("%w: unterminated '${' in %s", ErrInvalidReference, stack[len(stack)-1])
+			}
+			in.unresolved[fmt.Sprintf("unterminated '${' in %s", stack[len(stack)-1])] = true
+			b.WriteString(value[i:])
+			break
+		}
+
+		literal := value[i : end+1]
+		expanded, refSensitive, err := in.reference(value[i+2:end], envName, stack)
+		if err != nil {
+			if !in.strict && errors.Is(err, errUnresolved) {
+				if in.complete {
+					in.unresolved[fmt.Sprintf("'%s' in %s", literal, stack[len(stack)-1])] = true
+				}
+				b.WriteString(literal)
+				i = end + 1
+				continue
+			}
+			return "", false, err
+		}
+
+		b.WriteString(expanded)
+		sensitive = sensitive || refSensitive
+		i = end + 1
+	}
+
+	return b.String(), sensitive, nil
+}
+
+// errUnresolved marks references that are kept verbatim outside strict mode
+var errUnresolved = errors.New("unresolved reference")
+
+func (in *interpolator) reference(body, envName string, stack []reference) (string, bool, error) {
+	match := referencePattern.FindStringSubmatchIndex(body)
+	if match == nil {
+		if in.strict {
+			return "", false, fmt.Errorf("%w: '${%s}' in %s", ErrInvalidReference, body, stack[len(stack)-1])
+		}
+		return "", false, errUnresolved
+	}
+
+	ref := reference{env: envName, key: body[match[4]:match[5]]}
+	if match[2] >= 0 {
+		ref.env = body[match[2]:match[3]]
+	}
+	hasDefault := match[6] >= 0
+
+	value, sensitive, err := in.lookup(ref, stack)
+	if err == nil {
+		if value != "" || !hasDefault {
+			return value, sensitive, nil
+		}
+		return in.expand(body[match[6]:match[7]], envName, stack)
+	}
+	if !in.strict && errors.Is(err, ErrUnknownEnvironment) {
+		return "", false, errUnresolved
+	}
+	if !errors.Is(err, ErrUndefinedReference) {
+		return "", false, err
+	}
+
+	// Without the secrets a key missing from the current environment may
+	// still exist, so neither the key nor its default can be used
+	if !in.complete && ref.env == "" {
+		return "", false, errUnresolved
+	}
+	if !hasDefault {
+		if !in.strict {
+			return "", false, errUnresolved
+		}
+		return "", false, err
+	}
+
+	return in.expand(body[match[6]:match[7]], envName, stack)
+}
+
+// closingBrace returns the index of the '}' that closes the reference whose
+// body starts at start, allowing nested references in defaults
+func closingBrace(value string, start int) int {
+	depth := 1
+	for i := start; i < len(value); i++ {
+		switch {
+		case strings.HasPrefix(value[i:], "${"):
+			depth++
+			i++
+		case value[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
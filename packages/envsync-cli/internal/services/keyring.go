@@ -0,0 +1,88 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/domain"
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/utils"
+)
+
+// KeyringService stores the public keys of the organization next to the CLI
+// configuration, so signatures can be verified without the API. Each key is
+// kept in its own file named after its fingerprint, so a keyring can be
+// copied to machines without network access.
+type KeyringService interface {
+	List() ([]domain.KeyringEntry, error)
+	Save(entry domain.KeyringEntry) error
+	Dir() string
+}
+
+type keyring struct {
+	dir string
+}
+
+func NewKeyringService() KeyringService {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		panic(err)
+	}
+
+	return &keyring{dir: filepath.Join(configDir, "envsync", "keyring")}
+}
+
+func (k *keyring) Dir() string {
+	return k.dir
+}
+
+func (k *keyring) List() ([]domain.KeyringEntry, error) {
+	files, err := filepath.Glob(filepath.Join(k.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.KeyringEntry, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keyring entry: %w", err)
+		}
+		var entry domain.KeyringEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode keyring entry %s: %w", filepath.Base(file), err)
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	return entries, nil
+}
+
+func (k *keyring) Save(entry domain.KeyringEntry) error {
+	fingerprint := strings.ToUpper(entry.Fingerprint)
+	if fingerprint == "" || strings.ContainsAny(fingerprint, `/\.`) {
+		return errors.New("keyring entry has no valid fingerprint")
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode keyring entry: %w", err)
+	}
+
+	if err := os.MkdirAll(k.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create keyring directory: %w", err)
+	}
+
+	if err := utils.WriteFileAtomic(filepath.Join(k.dir, fingerprint+".json"), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write keyring entry: %w", err)
+	}
+
+	return nil
+}
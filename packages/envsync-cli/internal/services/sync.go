@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 
 	"github.com/BurntSushi/toml"
@@ -42,6 +43,17 @@ func NewSyncService() SyncService {
 	}
 }
 
+// NewSyncServiceForTarget returns a SyncService for the given app and
+// environment type instead of the ones in the project configuration
+func NewSyncServiceForTarget(appID, envTypeID string) SyncService {
+	r := repository.NewEnvVariableRepository(appID, envTypeID)
+
+	return &sync{
+		repo:       r,
+		projectCfg: domain.SyncConfig{AppID: appID, EnvTypeID: envTypeID},
+	}
+}
+
 func (s *sync) SyncConfigExist() error {
 	if _, err := os.Stat(constants.DefaultProjectConfig); errors.Is(err, os.ErrNotExist) {
 		return errors.New("project configuration file not found")
@@ -122,33 +134,37 @@ func (s *sync) WriteLocalEnv(env map[string]string) error {
 	return godotenv.Write(env, ".env")
 }
 
+// WriteRemoteEnv creates, updates and deletes the remote variables in batches.
+// Keys that fail do not stop the others from being written; the returned
+// error names every key that failed.
 func (s *sync) WriteRemoteEnv(ctx context.Context, env *domain.EnvironmentSync) error {
 	toCreate := env.ToAdd
 	toUpdate := env.ToUpdate
 	toDelete := env.ToDelete
 
+	var errs []error
 	if len(toCreate) != 0 {
 		batchCreateReq := mappers.EnvironmentVariableToBatchRequest(toCreate, s.projectCfg.AppID, s.projectCfg.EnvTypeID)
 		if err := s.repo.BatchCreateEnv(ctx, batchCreateReq); err != nil {
-			return err
+			errs = append(errs, fmt.Errorf("create: %w", err))
 		}
 	}
 
 	if len(toUpdate) != 0 {
 		batchUpdateReq := mappers.EnvironmentVariableToBatchRequest(toUpdate, s.projectCfg.AppID, s.projectCfg.EnvTypeID)
 		if err := s.repo.BatchUpdateEnv(ctx, batchUpdateReq); err != nil {
-			return err
+			errs = append(errs, fmt.Errorf("update: %w", err))
 		}
 	}
 
 	if len(toDelete) != 0 {
 		batchDeleteReq := mappers.KeysToBatchDeleteRequest(toDelete, s.projectCfg.AppID, s.projectCfg.EnvTypeID)
 		if err := s.repo.BatchDeleteEnv(ctx, batchDeleteReq); err != nil {
-			return err
+			errs = append(errs, fmt.Errorf("delete: %w", err))
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 func readTOMLConfig(c *domain.SyncConfig) error {
@@ -0,0 +1,59 @@
+package testutil
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/EnvSync-Cloud/envsync/packages/envsync-cli/internal/config"
+)
+
+// UseServer points the CLI configuration at the server at url and
+// authenticates with a test API key. The configuration, and with it the
+// backend URL, is only loaded once per process, so call it from TestMain and
+// share the server between the tests of the package. It returns the temporary
+// home directory, to be removed once the tests ran.
+func UseServer(url string) (string, error) {
+	dir, err := Home()
+	if err != nil {
+		return "", err
+	}
+	os.Setenv("API_KEY", "test-key")
+
+	if err := WriteConfig(config.AppConfig{BackendURL: url}); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// Home creates a temporary directory and makes it the home and configuration
+// directory of the process.
+func Home() (string, error) {
+	dir, err := os.MkdirTemp("", "envsync-test")
+	if err != nil {
+		return "", err
+	}
+	os.Setenv("HOME", dir)
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	return dir, nil
+}
+
+// WriteConfig writes cfg as the CLI configuration of the current home
+// directory.
+func WriteConfig(cfg config.AppConfig) error {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(configDir, "envsync"), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(configDir, "envsync", "config.json"), data, 0o644)
+}
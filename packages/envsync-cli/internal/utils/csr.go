@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+)
+
+// Key types for locally generated certificate keys
+const (
+	KeyTypeRSA       = "rsa"
+	KeyTypeECDSAP256 = "ecdsa-p256"
+	KeyTypeECDSAP384 = "ecdsa-p384"
+	KeyTypeEd25519   = "ed25519"
+)
+
+// KeyTypes lists the supported certificate key types
+var KeyTypes = []string{KeyTypeRSA, KeyTypeECDSAP256, KeyTypeECDSAP384, KeyTypeEd25519}
+
+// GenerateSigningKey generates a private key of the given type for a
+// certificate. RSA keys use a 3072-bit modulus.
+func GenerateSigningKey(keyType string) (crypto.Signer, error) {
+	switch keyType {
+	case KeyTypeRSA:
+		return rsa.GenerateKey(rand.Reader, 3072)
+	case KeyTypeECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyTypeECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case KeyTypeEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", keyType)
+	}
+}
+
+// CreateCSR builds a PEM-encoded PKCS#10 certificate signing request for the
+// member email, with the role as organizational unit, signed by key
+func CreateCSR(key crypto.Signer, email, role string) (string, error) {
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:         email,
+			OrganizationalUnit: []string{role},
+		},
+		EmailAddresses: []string{email},
+	}, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to create certificate request: %w", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE REQUEST",
+		Bytes: der,
+	})), nil
+}
+
+// EncodePrivateKey encodes a private key in PKCS#8 PEM format
+func EncodePrivateKey(key crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	})), nil
+}
@@ -1,6 +1,11 @@
 package utils
 
-import "os"
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
 
 func ReadFile(path string) (string, error) {
 	data, err := os.ReadFile(path)
@@ -13,3 +18,102 @@ func ReadFile(path string) (string, error) {
 func WriteFile(data, path string) error {
 	return os.WriteFile(path, []byte(data), 0644)
 }
+
+// WriteFileAtomic writes data to a temporary file in the target directory and
+// renames it into place, so readers never observe a partially written file.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmpPath, err := writeTemp(path, data, perm)
+	if err != nil {
+		return err
+	}
+
+	// Clean up the temporary file unless it was renamed into place
+	defer os.Remove(tmpPath)
+
+	return os.Rename(tmpPath, path)
+}
+
+// AtomicFile is one of the files replaced by WriteFilesAtomic.
+type AtomicFile struct {
+	Path string
+	Data []byte
+	Perm os.FileMode
+}
+
+// WriteFilesAtomic replaces a set of files that must stay consistent with each
+// other, such as a certificate and its key. All files are written to temporary
+// files first and then renamed into place back to back. When a rename fails,
+// the files already replaced get their previous contents back, so readers see
+// either the old or the new set. Only a crash between two renames can leave
+// the set mixed.
+func WriteFilesAtomic(files ...AtomicFile) error {
+	tmpPaths := make([]string, 0, len(files))
+	defer func() {
+		for _, tmpPath := range tmpPaths {
+			os.Remove(tmpPath)
+		}
+	}()
+	for _, file := range files {
+		tmpPath, err := writeTemp(file.Path, file.Data, file.Perm)
+		if err != nil {
+			return err
+		}
+		tmpPaths = append(tmpPaths, tmpPath)
+	}
+
+	previous := make([]*AtomicFile, len(files))
+	for i, file := range files {
+		data, err := os.ReadFile(file.Path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			return err
+		}
+		previous[i] = &AtomicFile{Path: file.Path, Data: data, Perm: info.Mode().Perm()}
+	}
+
+	for i, file := range files {
+		if err := os.Rename(tmpPaths[i], file.Path); err != nil {
+			errs := []error{err}
+			for j := i - 1; j >= 0; j-- {
+				if previous[j] == nil {
+					errs = append(errs, os.Remove(files[j].Path))
+				} else {
+					errs = append(errs, WriteFileAtomic(previous[j].Path, previous[j].Data, previous[j].Perm))
+				}
+			}
+			return errors.Join(errs...)
+		}
+	}
+	return nil
+}
+
+// writeTemp writes data to a temporary file next to path and returns its name.
+func writeTemp(path string, data []byte, perm os.FileMode) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmp.Name()
+
+	err = tmp.Chmod(perm)
+	if err == nil {
+		_, err = tmp.Write(data)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	return tmpPath, nil
+}
@@ -0,0 +1,12 @@
+package utils
+
+import "runtime"
+
+// ShellArgs returns the arguments that run command through the system shell:
+// cmd /C on Windows and sh -c elsewhere.
+func ShellArgs(command string) []string {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd", "/C", command}
+	}
+	return []string{"sh", "-c", command}
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// ParseSignal maps a signal name such as "SIGHUP" or "hup" to the signal
+func ParseSignal(name string) (os.Signal, error) {
+	signals := map[string]os.Signal{
+		"HUP":  syscall.SIGHUP,
+		"INT":  syscall.SIGINT,
+		"QUIT": syscall.SIGQUIT,
+		"TERM": syscall.SIGTERM,
+	}
+
+	key := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(name)), "SIG")
+	sig, ok := signals[key]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signal %q (supported: SIGHUP, SIGINT, SIGQUIT, SIGTERM)", name)
+	}
+
+	return sig, nil
+}
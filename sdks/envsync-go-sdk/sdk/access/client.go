@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -59,10 +62,13 @@ func (c *Client) CreateCliLogin(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "access.CreateCliLogin",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -103,10 +109,13 @@ func (c *Client) CreateWebLogin(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "access.CreateWebLogin",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -154,10 +163,13 @@ func (c *Client) CallbackWebLogin(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "access.CallbackWebLogin",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -197,10 +209,13 @@ func (c *Client) CreateApiLogin(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "access.CreateApiLogin",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -249,10 +264,13 @@ func (c *Client) CallbackApiLogin(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "access.CallbackApiLogin",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
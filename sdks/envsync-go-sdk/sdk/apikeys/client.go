@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -59,10 +62,13 @@ func (c *Client) GetAllApiKeys(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "apikeys.GetAllApiKeys",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -105,10 +111,13 @@ func (c *Client) CreateApiKey(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "apikeys.CreateApiKey",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -154,10 +163,13 @@ func (c *Client) GetApiKey(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "apikeys.GetApiKey",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -204,10 +216,13 @@ func (c *Client) UpdateApiKey(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "apikeys.UpdateApiKey",
 			URL:             endpointURL,
 			Method:          http.MethodPut,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -253,10 +268,13 @@ func (c *Client) DeleteApiKey(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "apikeys.DeleteApiKey",
 			URL:             endpointURL,
 			Method:          http.MethodDelete,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -301,10 +319,13 @@ func (c *Client) RegenerateApiKey(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "apikeys.RegenerateApiKey",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
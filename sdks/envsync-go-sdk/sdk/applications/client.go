@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -59,10 +62,13 @@ func (c *Client) GetApps(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "applications.GetApps",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -105,10 +111,13 @@ func (c *Client) CreateApp(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "applications.CreateApp",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -154,10 +163,13 @@ func (c *Client) GetApp(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "applications.GetApp",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -202,10 +214,13 @@ func (c *Client) DeleteApp(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "applications.DeleteApp",
 			URL:             endpointURL,
 			Method:          http.MethodDelete,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -252,10 +267,13 @@ func (c *Client) UpdateApp(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "applications.UpdateApp",
 			URL:             endpointURL,
 			Method:          http.MethodPatch,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
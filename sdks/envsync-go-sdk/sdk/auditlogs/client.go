@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -67,10 +70,13 @@ func (c *Client) GetAuditLogs(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "auditlogs.GetAuditLogs",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
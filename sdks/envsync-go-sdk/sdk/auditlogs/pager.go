@@ -0,0 +1,59 @@
+package auditlogs
+
+import (
+	context "context"
+	iter "iter"
+	strconv "strconv"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	internal "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/internal"
+	option "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+)
+
+// All iterates over the audit logs of every page, starting at request.Page.
+// request.PerPage sets the page size and defaults to internal.DefaultPageSize.
+func (c *Client) All(
+	ctx context.Context,
+	request *sdk.GetAuditLogsRequest,
+	opts ...option.RequestOption,
+) iter.Seq2[*sdk.GetAuditLogsResponseItem, error] {
+	query := sdk.GetAuditLogsRequest{}
+	if request != nil {
+		query = *request
+	}
+	start, err := pageNumber(query.Page, 1)
+	if err != nil {
+		return failed(err)
+	}
+	perPage, err := pageNumber(query.PerPage, internal.DefaultPageSize)
+	if err != nil {
+		return failed(err)
+	}
+	query.PerPage = sdk.String(strconv.Itoa(perPage))
+
+	return internal.Paginate(ctx, start, func(ctx context.Context, page int) (*internal.Page[*sdk.GetAuditLogsResponseItem], error) {
+		pageQuery := query
+		pageQuery.Page = sdk.String(strconv.Itoa(page))
+		response, err := c.GetAuditLogs(ctx, &pageQuery, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &internal.Page[*sdk.GetAuditLogsResponseItem]{
+			Items:      response.GetAuditLogs(),
+			TotalPages: int(response.GetTotalPages()),
+		}, nil
+	})
+}
+
+func pageNumber(value *string, fallback int) (int, error) {
+	if value == nil || *value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(*value)
+}
+
+func failed(err error) iter.Seq2[*sdk.GetAuditLogsResponseItem, error] {
+	return func(yield func(*sdk.GetAuditLogsResponseItem, error) bool) {
+		yield(nil, err)
+	}
+}
@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -59,10 +62,13 @@ func (c *Client) Whoami(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "authentication.Whoami",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
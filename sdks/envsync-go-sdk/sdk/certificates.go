@@ -20,6 +20,14 @@ type IssueMemberCertRequest struct {
 	Metadata    map[string]string `json:"metadata,omitempty" url:"-"`
 }
 
+type IssueMemberCertFromCsrRequest struct {
+	CsrPem      string            `json:"csr_pem" url:"-"`
+	MemberEmail string            `json:"member_email" url:"-"`
+	Role        string            `json:"role" url:"-"`
+	Description *string           `json:"description,omitempty" url:"-"`
+	Metadata    map[string]string `json:"metadata,omitempty" url:"-"`
+}
+
 type RevokeCertRequest struct {
 	Reason int `json:"reason" url:"-"`
 }
@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -61,10 +64,13 @@ func (c *Client) InitOrgCa(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "certificates.InitOrgCa",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -106,10 +112,13 @@ func (c *Client) GetOrgCa(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "certificates.GetOrgCa",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -150,10 +159,13 @@ func (c *Client) GetRootCa(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "certificates.GetRootCa",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -196,10 +208,63 @@ func (c *Client) IssueMemberCert(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "certificates.IssueMemberCert",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
+			BodyProperties:  options.BodyProperties,
+			QueryParameters: options.QueryParameters,
+			Client:          options.HTTPClient,
+			Request:         request,
+			Response:        &response,
+			ErrorDecoder:    internal.NewErrorDecoder(errorCodes),
+		},
+	); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Issue a member certificate for the public key of a PKCS#10 certificate signing request. The private key never leaves the caller, so key_pem is empty. Requires a miniKMS that implements IssueMemberCertFromCSR, enabled with MINIKMS_CSR_ISSUANCE_ENABLED.
+func (c *Client) IssueMemberCertFromCsr(
+	ctx context.Context,
+	request *sdk.IssueMemberCertFromCsrRequest,
+	opts ...option.RequestOption,
+) (*sdk.MemberCertResponse, error) {
+	options := core.NewRequestOptions(opts...)
+	baseURL := internal.ResolveBaseURL(
+		options.BaseURL,
+		c.baseURL,
+		"http://localhost:4000",
+	)
+	endpointURL := baseURL + "/api/certificate/issue/csr"
+	headers := internal.MergeHeaders(
+		c.header.Clone(),
+		options.ToHeader(),
+	)
+	headers.Set("Content-Type", "application/json")
+	errorCodes := internal.ErrorCodes{
+		500: func(apiError *core.APIError) error {
+			return &sdk.InternalServerError{
+				APIError: apiError,
+			}
+		},
+	}
+
+	var response *sdk.MemberCertResponse
+	if err := c.caller.Call(
+		ctx,
+		&internal.CallParams{
+			Operation:       "certificates.IssueMemberCertFromCsr",
+			URL:             endpointURL,
+			Method:          http.MethodPost,
+			Headers:         headers,
+			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -241,10 +306,13 @@ func (c *Client) GetCrl(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "certificates.GetCrl",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -285,10 +353,13 @@ func (c *Client) ListCertificates(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "certificates.ListCertificates",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -333,10 +404,13 @@ func (c *Client) GetCertificate(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "certificates.GetCertificate",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -383,10 +457,13 @@ func (c *Client) RevokeCert(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "certificates.RevokeCert",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -432,10 +509,13 @@ func (c *Client) CheckOcsp(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "certificates.CheckOcsp",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -1,6 +1,22 @@
 package core
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors matched by *APIError with errors.Is, based on the status
+// code of the response.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+)
 
 // APIError is a lightweight wrapper around the standard error
 // interface that preserves the status code from the RPC, if any.
@@ -8,6 +24,26 @@ type APIError struct {
 	err error
 
 	StatusCode int `json:"-"`
+
+	// Code is the machine-readable error code sent by the server, such as
+	// NOT_FOUND or VALIDATION_ERROR.
+	Code string `json:"-"`
+	// Message is the human-readable error message sent by the server.
+	Message string `json:"-"`
+	// FieldErrors lists the invalid fields of a rejected request.
+	FieldErrors []FieldError `json:"-"`
+	// RequestID identifies the request in the server logs, if the server
+	// sent one.
+	RequestID string `json:"-"`
+	// RetryAfter is the delay requested by the Retry-After header, if any.
+	RetryAfter time.Duration `json:"-"`
+}
+
+// FieldError describes why a single field of a request was rejected.
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
 }
 
 // NewAPIError constructs a new API error.
@@ -27,16 +63,84 @@ func (a *APIError) Unwrap() error {
 	return a.err
 }
 
+// Is reports whether the error matches one of the sentinel errors, such as
+// ErrNotFound.
+func (a *APIError) Is(target error) bool {
+	if a == nil {
+		return false
+	}
+	switch target {
+	case ErrNotFound:
+		return a.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return a.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return a.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return a.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return a.StatusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return a.StatusCode == http.StatusBadRequest || a.StatusCode == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
 // Error returns the API error's message.
 func (a *APIError) Error() string {
 	if a == nil || (a.err == nil && a.StatusCode == 0) {
 		return ""
 	}
-	if a.err == nil {
-		return fmt.Sprintf("%d", a.StatusCode)
+	var message string
+	switch {
+	case a.err == nil:
+		message = fmt.Sprintf("%d", a.StatusCode)
+	case a.StatusCode == 0:
+		message = a.err.Error()
+	default:
+		message = fmt.Sprintf("%d: %s", a.StatusCode, a.err.Error())
 	}
-	if a.StatusCode == 0 {
-		return a.err.Error()
+	if a.RequestID != "" {
+		message += fmt.Sprintf(" (request ID: %s)", a.RequestID)
+	}
+	return message
+}
+
+// AsAPIError returns the *APIError in err's chain, if any.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError, true
 	}
-	return fmt.Sprintf("%d: %s", a.StatusCode, a.err.Error())
+	return nil, false
+}
+
+// IsNotFound reports whether err is an API error with status 404.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsUnauthorized reports whether err is an API error with status 401.
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden reports whether err is an API error with status 403.
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsConflict reports whether err is an API error with status 409.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsRateLimited reports whether err is an API error with status 429.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsValidation reports whether err is an API error with status 400 or 422.
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
 }
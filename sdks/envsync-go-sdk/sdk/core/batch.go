@@ -0,0 +1,120 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxBatchSize is the largest number of items the API accepts in a single
+// batch request.
+const MaxBatchSize = 100
+
+// maxErrorKeys is the number of failed keys named in a BatchError message.
+const maxErrorKeys = 5
+
+// DefaultBatchConcurrency is the number of batch chunks sent at the same time
+// when no concurrency is configured.
+const DefaultBatchConcurrency = 4
+
+// BatchOptions configures how a large batch is split into requests.
+type BatchOptions struct {
+	// Size is the number of items per request, capped at MaxBatchSize.
+	Size int
+	// Concurrency is the number of requests sent at the same time.
+	Concurrency int
+	// Resume skips the keys that succeeded in an earlier result, so a failed
+	// batch can be retried without sending them again.
+	Resume *BatchResult
+}
+
+// BatchResult reports which keys of a batch were applied and which failed.
+// Keys are listed in the order they were given.
+type BatchResult struct {
+	Succeeded []string
+	Failed    []*BatchFailure
+}
+
+// BatchFailure is a key that could not be applied, with the reason.
+type BatchFailure struct {
+	Key string
+	Err error
+}
+
+// FailedKeys returns the keys that could not be applied.
+func (r *BatchResult) FailedKeys() []string {
+	if r == nil {
+		return nil
+	}
+	keys := make([]string, len(r.Failed))
+	for i, failure := range r.Failed {
+		keys[i] = failure.Key
+	}
+	return keys
+}
+
+// Err returns a *BatchError if any key failed, and nil otherwise.
+func (r *BatchResult) Err() error {
+	if r == nil || len(r.Failed) == 0 {
+		return nil
+	}
+	return &BatchError{Result: r}
+}
+
+// BatchError is returned when some keys of a batch could not be applied. The
+// keys that succeeded have been applied regardless.
+type BatchError struct {
+	Result *BatchResult
+}
+
+func (b *BatchError) Error() string {
+	keys := b.Result.FailedKeys()
+	total := len(keys) + len(b.Result.Succeeded)
+	if len(keys) > maxErrorKeys {
+		keys = append(keys[:maxErrorKeys], "...")
+	}
+	return fmt.Sprintf("%d of %d keys failed (%s): %v", len(b.Result.Failed), total, strings.Join(keys, ", "), b.Result.Failed[0].Err)
+}
+
+// Unwrap returns the distinct errors of the failed keys, so errors.Is and
+// errors.As match any of them.
+func (b *BatchError) Unwrap() []error {
+	var errs []error
+	for _, failure := range b.Result.Failed {
+		if len(errs) == 0 || errs[len(errs)-1] != failure.Err {
+			errs = append(errs, failure.Err)
+		}
+	}
+	return errs
+}
+
+// AsBatchError returns the *BatchError in err's chain, if any.
+func AsBatchError(err error) (*BatchError, bool) {
+	var batchError *BatchError
+	if errors.As(err, &batchError) {
+		return batchError, true
+	}
+	return nil, false
+}
+
+// BatchOption implements the RequestOption interface.
+type BatchOption struct {
+	Size        int
+	Concurrency int
+	Resume      *BatchResult
+}
+
+func (b *BatchOption) applyRequestOptions(opts *RequestOptions) {
+	if opts.Batch == nil {
+		opts.Batch = &BatchOptions{}
+	}
+	if b.Size > 0 {
+		opts.Batch.Size = b.Size
+	}
+	if b.Concurrency > 0 {
+		opts.Batch.Concurrency = b.Concurrency
+	}
+	if b.Resume != nil {
+		opts.Batch.Resume = b.Resume
+	}
+}
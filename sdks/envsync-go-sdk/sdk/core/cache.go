@@ -0,0 +1,295 @@
+package core
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache caches the responses of the read endpoints of a client. Responses
+// with an ETag or Last-Modified header are revalidated with a conditional
+// request, and a 304 Not Modified response is served from the cache. Without
+// validators, responses are served from the cache for TTL.
+//
+// Responses are cached per URL, request body and credentials. A successful
+// request that changes data clears the cache.
+type Cache struct {
+	// Store holds the cached responses.
+	Store CacheStore
+	// TTL is how long a response is served from the cache without asking the
+	// server, unless the server sets a max-age. With zero, every response is
+	// revalidated and only responses with validators are cached.
+	TTL time.Duration
+	// Clock defaults to the system clock.
+	Clock Clock
+}
+
+// CacheEntry is a cached response.
+type CacheEntry struct {
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	// Expires is when the entry must be revalidated before use.
+	Expires time.Time `json:"expires"`
+	// Sensitive is set for responses that hold secrets.
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// CacheStore stores cache entries by key.
+type CacheStore interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Clear()
+}
+
+func (c *Cache) now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock.Now()
+}
+
+// Lookup returns the entry for key and whether it can be used without
+// revalidation.
+//
+// This method is primarily used by the generated code and is not meant to be
+// used directly.
+func (c *Cache) Lookup(key string) (entry *CacheEntry, fresh bool) {
+	entry, ok := c.Store.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return entry, c.now().Before(entry.Expires)
+}
+
+// Save caches a successful response with the given body under key, unless
+// the server forbids it or the response could never be reused.
+//
+// This method is primarily used by the generated code and is not meant to be
+// used directly.
+func (c *Cache) Save(key string, response *http.Response, body []byte, sensitive bool) {
+	maxAge, cacheable := c.maxAge(response.Header)
+	if !cacheable {
+		return
+	}
+	entry := &CacheEntry{
+		StatusCode:   response.StatusCode,
+		Header:       response.Header.Clone(),
+		Body:         body,
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+		Expires:      c.now().Add(maxAge),
+		Sensitive:    sensitive,
+	}
+	if maxAge <= 0 && entry.ETag == "" && entry.LastModified == "" {
+		return
+	}
+	c.Store.Set(key, entry)
+}
+
+// Revalidated renews entry after the server answered 304 Not Modified.
+//
+// This method is primarily used by the generated code and is not meant to be
+// used directly.
+func (c *Cache) Revalidated(key string, entry *CacheEntry, header http.Header) {
+	maxAge, cacheable := c.maxAge(header)
+	if !cacheable {
+		return
+	}
+	renewed := *entry
+	renewed.Expires = c.now().Add(maxAge)
+	if etag := header.Get("ETag"); etag != "" {
+		renewed.ETag = etag
+	}
+	c.Store.Set(key, &renewed)
+}
+
+// maxAge returns how long a response with header stays fresh and whether it
+// may be cached at all.
+func (c *Cache) maxAge(header http.Header) (time.Duration, bool) {
+	maxAge := c.TTL
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(strings.ToLower(directive)), "=")
+		switch name {
+		case "no-store":
+			return 0, false
+		case "no-cache":
+			maxAge = 0
+		case "max-age":
+			if seconds, err := strconv.Atoi(value); err == nil {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return maxAge, true
+}
+
+// Response returns the cached response as an HTTP response to request.
+func (e *CacheEntry) Response(request *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       request,
+	}
+}
+
+type memoryCacheStore struct {
+	mu      sync.Mutex
+	entries map[string]*CacheEntry
+}
+
+// NewMemoryCacheStore returns a CacheStore that keeps entries in memory.
+func NewMemoryCacheStore() CacheStore {
+	return &memoryCacheStore{entries: make(map[string]*CacheEntry)}
+}
+
+func (m *memoryCacheStore) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	return entry, ok
+}
+
+func (m *memoryCacheStore) Set(key string, entry *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = entry
+}
+
+func (m *memoryCacheStore) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]*CacheEntry)
+}
+
+// cacheFileSuffix is the extension of the files of a file cache store.
+const cacheFileSuffix = ".cache"
+
+type fileCacheStore struct {
+	dir  string
+	aead cipher.AEAD
+}
+
+// NewFileCacheStore returns a CacheStore that keeps entries in files in dir,
+// so they outlive the process. When encryptionKey is set, it must be 32 bytes
+// long and every entry is encrypted with AES-256-GCM. Without it, responses
+// holding secrets are not stored.
+func NewFileCacheStore(dir string, encryptionKey []byte) (CacheStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	store := &fileCacheStore{dir: dir}
+	if encryptionKey != nil {
+		if len(encryptionKey) != 32 {
+			return nil, errors.New("cache encryption key must be 32 bytes")
+		}
+		block, err := aes.NewCipher(encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		if store.aead, err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+func (f *fileCacheStore) path(key string) string {
+	return filepath.Join(f.dir, key+cacheFileSuffix)
+}
+
+// Get returns the entry for key. Entries that cannot be read or decrypted
+// are treated as missing.
+func (f *fileCacheStore) Get(key string) (*CacheEntry, bool) {
+	data, err := os.ReadFile(f.path(key))
+	if err != nil {
+		return nil, false
+	}
+	if f.aead != nil {
+		size := f.aead.NonceSize()
+		if len(data) < size {
+			return nil, false
+		}
+		if data, err = f.aead.Open(nil, data[:size], data[size:], []byte(key)); err != nil {
+			return nil, false
+		}
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Set writes the entry for key. Failures are ignored, as the entry is only
+// an optimization.
+func (f *fileCacheStore) Set(key string, entry *CacheEntry) {
+	if entry.Sensitive && f.aead == nil {
+		// Secrets are never written to disk in the clear
+		os.Remove(f.path(key))
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if f.aead != nil {
+		nonce := make([]byte, f.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return
+		}
+		data = f.aead.Seal(nonce, nonce, data, []byte(key))
+	}
+
+	// Write to a temporary file first so readers never see a partial entry
+	tmp, err := os.CreateTemp(f.dir, "entry-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (f *fileCacheStore) Clear() {
+	files, _ := filepath.Glob(filepath.Join(f.dir, "*"+cacheFileSuffix))
+	for _, file := range files {
+		os.Remove(file)
+	}
+}
+
+// CacheOption implements the RequestOption interface.
+type CacheOption struct {
+	Cache *Cache
+}
+
+func (c *CacheOption) applyRequestOptions(opts *RequestOptions) {
+	opts.Cache = c.Cache
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"context"
+	"net/http"
+)
+
+// RoundTripFunc sends an HTTP request and returns its response.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// Middleware wraps the function that sends each HTTP request made by the
+// client. It may inspect or modify the request before calling next, and the
+// response or error after. Middleware runs once per attempt, so a retried
+// call passes through it several times.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Chain returns a Middleware that applies the given middleware in order, the
+// first being the outermost.
+func Chain(middleware ...Middleware) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			next = middleware[i](next)
+		}
+		return next
+	}
+}
+
+type operationKey struct{}
+
+// WithOperation returns a copy of ctx that carries the name of the API
+// operation a request is made for.
+func WithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// Operation returns the name of the API operation, such as
+// "applications.CreateApp", that a request with the given context is made
+// for. Middleware reads it from the request context.
+func Operation(ctx context.Context) string {
+	operation, _ := ctx.Value(operationKey{}).(string)
+	return operation
+}
+
+// MiddlewareOption implements the RequestOption interface.
+type MiddlewareOption struct {
+	Middleware []Middleware
+}
+
+func (m *MiddlewareOption) applyRequestOptions(opts *RequestOptions) {
+	opts.Middleware = append(opts.Middleware, m.Middleware...)
+}
@@ -0,0 +1,256 @@
+package core
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Clock tells the time for the rate limiter. Tests can replace it to control
+// the passing of time.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// RateLimit configures a RateLimiter.
+type RateLimit struct {
+	// RequestsPerSecond is the sustained rate of requests. Zero means no
+	// limit.
+	RequestsPerSecond float64
+	// Burst is the number of requests that may be sent at once after a quiet
+	// period. It defaults to 1.
+	Burst int
+	// MaxConcurrent is the number of requests that may be in flight at the
+	// same time. Zero means no limit.
+	MaxConcurrent int
+	// Clock defaults to the system clock.
+	Clock Clock
+}
+
+// RateLimiter limits the requests of a client with a token bucket and an
+// optional cap on concurrent requests. It also holds back requests when the
+// server asks for it, with a 429 response and a Retry-After header or with
+// RateLimit-Remaining and RateLimit-Reset headers reporting an exhausted
+// quota.
+//
+// A RateLimiter is safe for concurrent use and may be shared by several
+// clients to give them a common budget.
+type RateLimiter struct {
+	clock Clock
+	rate  float64
+	burst float64
+	slots chan struct{}
+
+	mu sync.Mutex
+	// tokens is the number of requests allowed at last. It is negative when
+	// requests are waiting for their turn.
+	tokens float64
+	last   time.Time
+	// pausedUntil is the time until which the server asked not to send
+	// requests.
+	pausedUntil time.Time
+}
+
+// NewRateLimiter returns a RateLimiter for the given limits.
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	clock := limit.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+	burst := math.Max(float64(limit.Burst), 1)
+	l := &RateLimiter{
+		clock:  clock,
+		rate:   limit.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   clock.Now(),
+	}
+	if limit.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, limit.MaxConcurrent)
+	}
+	return l
+}
+
+// Wait blocks until a request may be sent according to the rate limit, or
+// until ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	delay := l.reserve()
+	for delay > 0 {
+		select {
+		case <-ctx.Done():
+			l.cancel()
+			return ctx.Err()
+		case <-l.clock.After(delay):
+		}
+		// The server may have asked to pause in the meantime
+		delay = l.paused()
+	}
+	return nil
+}
+
+// reserve takes a token and returns how long to wait before using it.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	var delay time.Duration
+	if l.rate > 0 {
+		if now.After(l.last) {
+			l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+			l.last = now
+		}
+		l.tokens--
+		if l.tokens < 0 {
+			// The token is available once the bucket has refilled up to it
+			delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+		}
+	}
+	return max(delay, l.pausedUntil.Sub(now))
+}
+
+// paused returns how long requests are still held back by the server.
+func (l *RateLimiter) paused() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.pausedUntil.Sub(l.clock.Now())
+}
+
+// cancel returns the token of a request that was not sent.
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+1)
+	}
+}
+
+// Pause holds back requests until the given time.
+func (l *RateLimiter) Pause(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// acquire takes a concurrency slot, if concurrency is limited.
+func (l *RateLimiter) acquire(ctx context.Context) (release func(), err error) {
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-l.slots }) }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// observe pauses the limiter when the response asks to slow down.
+func (l *RateLimiter) observe(header http.Header, statusCode int) {
+	now := l.clock.Now()
+	if statusCode == http.StatusTooManyRequests {
+		if delay := ParseRetryAfter(header.Get("Retry-After"), now); delay > 0 {
+			l.Pause(now.Add(delay))
+			return
+		}
+	}
+	for _, prefix := range []string{"RateLimit-", "X-RateLimit-"} {
+		remaining := header.Get(prefix + "Remaining")
+		if remaining == "" {
+			continue
+		}
+		if remaining == "0" {
+			if delay := parseReset(header.Get(prefix+"Reset"), now); delay > 0 {
+				l.Pause(now.Add(delay))
+			}
+		}
+		return
+	}
+}
+
+// Middleware returns middleware that applies the limiter to every attempt of
+// a request. A concurrency slot is held until the response body is closed.
+func (l *RateLimiter) Middleware() Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			release, err := l.acquire(req.Context())
+			if err != nil {
+				return nil, err
+			}
+			if err := l.Wait(req.Context()); err != nil {
+				release()
+				return nil, err
+			}
+
+			resp, err := next(req)
+			if err != nil {
+				release()
+				return nil, err
+			}
+			l.observe(resp.Header, resp.StatusCode)
+			resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+			return resp, nil
+		}
+	}
+}
+
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
+
+// ParseRetryAfter returns the delay requested by a Retry-After header given in
+// seconds or as an HTTP date, or zero if there is none.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
+
+// parseReset parses a rate limit reset header, which gives either the number
+// of seconds until the quota resets or, for large values, the Unix time at
+// which it does.
+func parseReset(value string, now time.Time) time.Duration {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	if seconds > 1_000_000_000 {
+		return time.Unix(seconds, 0).Sub(now)
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// RateLimitOption implements the RequestOption interface.
+type RateLimitOption struct {
+	Limiter *RateLimiter
+}
+
+func (r *RateLimitOption) applyRequestOptions(opts *RequestOptions) {
+	opts.Middleware = append(opts.Middleware, r.Limiter.Middleware())
+}
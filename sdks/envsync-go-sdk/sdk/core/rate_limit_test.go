@@ -0,0 +1,262 @@
+package core_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/envsynctest"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a Clock whose time only moves when advanced.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward and fires the timers that are due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// Waiting returns the delays of the pending timers.
+func (c *fakeClock) Waiting() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delays := make([]time.Duration, len(c.waiters))
+	for i, w := range c.waiters {
+		delays[i] = w.at.Sub(c.now)
+	}
+	return delays
+}
+
+// waitAsync calls Wait in the background and returns its result channel,
+// after the call has started waiting on the clock.
+func waitAsync(t *testing.T, clock *fakeClock, limiter *core.RateLimiter, ctx context.Context) <-chan error {
+	t.Helper()
+	before := len(clock.Waiting())
+	done := make(chan error, 1)
+	go func() { done <- limiter.Wait(ctx) }()
+	require.Eventually(t, func() bool { return len(clock.Waiting()) > before }, time.Second, time.Millisecond)
+	return done
+}
+
+func assertBlocked(t *testing.T, done <-chan error) {
+	t.Helper()
+	select {
+	case err := <-done:
+		t.Fatalf("Wait returned %v, want it to block", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func assertDone(t *testing.T, done <-chan error) {
+	t.Helper()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return")
+	}
+}
+
+func TestRateLimiterBurstThenRate(t *testing.T) {
+	clock := newFakeClock()
+	limiter := core.NewRateLimiter(core.RateLimit{RequestsPerSecond: 2, Burst: 2, Clock: clock})
+	ctx := context.Background()
+
+	require.NoError(t, limiter.Wait(ctx))
+	require.NoError(t, limiter.Wait(ctx))
+
+	done := waitAsync(t, clock, limiter, ctx)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond}, clock.Waiting())
+	clock.Advance(499 * time.Millisecond)
+	assertBlocked(t, done)
+	clock.Advance(time.Millisecond)
+	assertDone(t, done)
+
+	// The bucket refills up to the burst after a quiet period
+	clock.Advance(time.Minute)
+	require.NoError(t, limiter.Wait(ctx))
+	require.NoError(t, limiter.Wait(ctx))
+	assert.Empty(t, clock.Waiting())
+}
+
+func TestRateLimiterCancelReturnsToken(t *testing.T) {
+	clock := newFakeClock()
+	limiter := core.NewRateLimiter(core.RateLimit{RequestsPerSecond: 1, Clock: clock})
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := waitAsync(t, clock, limiter, ctx)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	// The cancelled request does not delay the next one
+	next := waitAsync(t, clock, limiter, context.Background())
+	assert.Equal(t, time.Second, clock.Waiting()[1])
+	clock.Advance(time.Second)
+	assertDone(t, next)
+}
+
+func TestRateLimiterPause(t *testing.T) {
+	clock := newFakeClock()
+	limiter := core.NewRateLimiter(core.RateLimit{RequestsPerSecond: 100, Burst: 10, Clock: clock})
+
+	limiter.Pause(clock.Now().Add(3 * time.Second))
+	done := waitAsync(t, clock, limiter, context.Background())
+
+	// A longer pause requested while waiting holds the request back further
+	limiter.Pause(clock.Now().Add(5 * time.Second))
+	clock.Advance(3 * time.Second)
+	assertBlocked(t, done)
+	clock.Advance(2 * time.Second)
+	assertDone(t, done)
+}
+
+func TestRateLimiterAdaptsToServerHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+		want   time.Duration
+	}{
+		{"retry after", http.StatusTooManyRequests, http.Header{"Retry-After": {"7"}}, 7 * time.Second},
+		{"ratelimit reset", http.StatusOK, http.Header{"Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {"4"}}, 4 * time.Second},
+		{"x-ratelimit reset time", http.StatusOK, http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1767225610"}}, 10 * time.Second},
+		{"quota left", http.StatusOK, http.Header{"Ratelimit-Remaining": {"3"}, "Ratelimit-Reset": {"4"}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			limiter := core.NewRateLimiter(core.RateLimit{Clock: clock})
+			send := limiter.Middleware()(func(*http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: tt.status, Header: tt.header, Body: http.NoBody}, nil
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+			resp, err := send(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			if tt.want == 0 {
+				assert.NoError(t, limiter.Wait(context.Background()))
+				assert.Empty(t, clock.Waiting())
+				return
+			}
+			done := waitAsync(t, clock, limiter, context.Background())
+			assert.Equal(t, []time.Duration{tt.want}, clock.Waiting())
+			clock.Advance(tt.want)
+			assertDone(t, done)
+		})
+	}
+}
+
+func TestRateLimiterMaxConcurrent(t *testing.T) {
+	limiter := core.NewRateLimiter(core.RateLimit{MaxConcurrent: 1})
+	send := limiter.Middleware()(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	first, err := send(req)
+	require.NoError(t, err)
+
+	second := make(chan error, 1)
+	go func() {
+		resp, err := send(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		second <- err
+	}()
+	assertBlocked(t, second)
+
+	// The slot is released when the body of the first response is closed
+	first.Body.Close()
+	assertDone(t, second)
+}
+
+func TestRateLimiterSharedBySubClients(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+
+	clock := newFakeClock()
+	limiter := core.NewRateLimiter(core.RateLimit{RequestsPerSecond: 1, Clock: clock})
+	client := server.Client(option.WithRateLimiter(limiter))
+
+	_, err := client.Applications.GetApps(context.Background())
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.EnvironmentTypes.GetEnvTypes(context.Background())
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return len(clock.Waiting()) == 1 }, time.Second, time.Millisecond)
+	assertBlocked(t, done)
+	clock.Advance(time.Second)
+	assertDone(t, done)
+}
+
+func TestWithRateLimit(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+	client := server.Client(option.WithRateLimit(1000, 5), option.WithMaxConcurrency(2))
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Applications.GetApp(context.Background(), strconv.Itoa(i))
+			assert.True(t, core.IsNotFound(err))
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, server.Requests(), 10)
+}
@@ -3,6 +3,7 @@
 package core
 
 import (
+	tls "crypto/tls"
 	fmt "fmt"
 	http "net/http"
 	url "net/url"
@@ -24,6 +25,11 @@ type RequestOptions struct {
 	BodyProperties  map[string]interface{}
 	QueryParameters url.Values
 	MaxAttempts     uint
+	RetryPolicy     *RetryPolicy
+	Middleware      []Middleware
+	Batch           *BatchOptions
+	Cache           *Cache
+	TLSConfig       *tls.Config
 	Token           string
 	ApiKey          string
 }
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"net/http"
+	"time"
+)
+
+// RetryDecision describes whether requests that failed with a given status
+// code are retried.
+type RetryDecision int
+
+const (
+	// RetryNever never retries the request.
+	RetryNever RetryDecision = iota
+	// RetryIdempotent retries the request only if it is safe to repeat: its
+	// operation is idempotent or it carries an Idempotency-Key header.
+	RetryIdempotent
+	// RetryAlways retries the request regardless of its method.
+	RetryAlways
+)
+
+// IdempotencyKeyHeader is the header used to deduplicate repeated requests.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// BackoffFunc returns the delay before the given retry, starting at zero for
+// the first retry.
+type BackoffFunc func(retry uint) time.Duration
+
+// RetryPolicy configures how failed requests are retried.
+//
+// Start from DefaultRetryPolicy and change the fields that should differ;
+// MaxAttempts, Backoff and MaxDelay also fall back to their defaults when left
+// at zero, but a zero Jitter turns jitter off. A Retry-After header sent by
+// the server takes precedence over the backoff, up to MaxDelay, and no retry
+// is attempted if the delay would exceed the deadline of the request context.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// The MaxAttempts request option takes precedence when it is set.
+	MaxAttempts uint
+
+	// Backoff returns the delay before each retry. Defaults to
+	// DefaultBackoff.
+	Backoff BackoffFunc
+
+	// MaxDelay caps the delay before a retry, including the one requested
+	// by a Retry-After header. Defaults to DefaultMaxRetryDelay.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction of each backoff delay that is randomized, from
+	// 0 (none) to 1. A Jitter of 0.25 waits between 75% and 100% of the delay,
+	// but never less than the delay before the first retry.
+	Jitter float64
+
+	// StatusRules overrides the decision for individual status codes. By
+	// default 429 is always retried, and 408 and 5xx responses are retried
+	// for idempotent requests.
+	StatusRules map[int]RetryDecision
+
+	// RetryNonIdempotent treats every request as idempotent, so operations
+	// that create or change resources are retried like reads.
+	RetryNonIdempotent bool
+
+	// IdempotencyKeys adds a random Idempotency-Key header to requests of
+	// non-idempotent operations that lack one, which makes them safe to
+	// retry. The same key is sent on every attempt.
+	IdempotencyKeys bool
+}
+
+// DefaultMaxRetryDelay is the longest delay before a retry unless the policy
+// sets MaxDelay.
+const DefaultMaxRetryDelay = 30 * time.Second
+
+// DefaultRetryPolicy returns the policy used when none is configured.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 2,
+		Backoff:     DefaultBackoff,
+		MaxDelay:    DefaultMaxRetryDelay,
+		Jitter:      0.25,
+	}
+}
+
+// DefaultBackoff grows quadratically from 500ms up to 5s.
+func DefaultBackoff(retry uint) time.Duration {
+	return ExponentialBackoff(500*time.Millisecond, 5*time.Second)(retry)
+}
+
+// ExponentialBackoff returns a backoff of base + base*retry², capped at max.
+func ExponentialBackoff(base, max time.Duration) BackoffFunc {
+	return func(retry uint) time.Duration {
+		delay := base + base*time.Duration(retry*retry)
+		if delay > max || delay < 0 {
+			return max
+		}
+		return delay
+	}
+}
+
+// ConstantBackoff waits the same delay before every retry.
+func ConstantBackoff(delay time.Duration) BackoffFunc {
+	return func(uint) time.Duration {
+		return delay
+	}
+}
+
+// StatusDecision returns the decision for a response with the given status.
+func (p *RetryPolicy) StatusDecision(statusCode int) RetryDecision {
+	if decision, ok := p.StatusRules[statusCode]; ok {
+		return decision
+	}
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		// The request was rejected before it was processed
+		return RetryAlways
+	case statusCode == http.StatusRequestTimeout, statusCode >= http.StatusInternalServerError:
+		return RetryIdempotent
+	}
+	return RetryNever
+}
+
+// RetryPolicyOption implements the RequestOption interface.
+type RetryPolicyOption struct {
+	RetryPolicy *RetryPolicy
+}
+
+func (r *RetryPolicyOption) applyRequestOptions(opts *RequestOptions) {
+	policy := *r.RetryPolicy
+	// Idempotency keys enabled by an earlier option stay enabled
+	if opts.RetryPolicy != nil && opts.RetryPolicy.IdempotencyKeys {
+		policy.IdempotencyKeys = true
+	}
+	opts.RetryPolicy = &policy
+}
+
+// IdempotencyKeysOption implements the RequestOption interface.
+type IdempotencyKeysOption struct{}
+
+func (i *IdempotencyKeysOption) applyRequestOptions(opts *RequestOptions) {
+	policy := *DefaultRetryPolicy()
+	if opts.RetryPolicy != nil {
+		policy = *opts.RetryPolicy
+	}
+	policy.IdempotencyKeys = true
+	opts.RetryPolicy = &policy
+}
@@ -0,0 +1,31 @@
+package core_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyKeepsIdempotencyKeys(t *testing.T) {
+	policy := core.DefaultRetryPolicy()
+	policy.MaxAttempts = 5
+
+	options := core.NewRequestOptions(option.WithIdempotencyKeys(), option.WithRetryPolicy(policy))
+	assert.True(t, options.RetryPolicy.IdempotencyKeys)
+	assert.Equal(t, uint(5), options.RetryPolicy.MaxAttempts)
+	assert.False(t, policy.IdempotencyKeys, "the caller's policy must not change")
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 3*time.Second, core.ParseRetryAfter("3", now))
+	assert.Equal(t, time.Minute, core.ParseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now))
+	assert.Zero(t, core.ParseRetryAfter("-5", now))
+	assert.Zero(t, core.ParseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	assert.Zero(t, core.ParseRetryAfter("soon", now))
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrTLSTransport is returned by requests when TLS options are combined with
+// an HTTP client whose transport cannot be configured.
+var ErrTLSTransport = errors.New("TLS options require an *http.Client with an *http.Transport")
+
+// TLSOption implements the RequestOption interface. It adds client
+// certificates and root CAs to the TLS configuration of the client.
+type TLSOption struct {
+	Certificates []tls.Certificate
+	RootCAs      *x509.CertPool
+	// Err is returned by every request, for options built from invalid
+	// input.
+	Err error
+}
+
+func (t *TLSOption) applyRequestOptions(opts *RequestOptions) {
+	if opts.TLSConfig == nil {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	} else {
+		// Options are applied for every client of the SDK, so the
+		// configuration of a previous client must not change
+		opts.TLSConfig = opts.TLSConfig.Clone()
+	}
+	opts.TLSConfig.Certificates = append(opts.TLSConfig.Certificates, t.Certificates...)
+	if t.RootCAs != nil {
+		opts.TLSConfig.RootCAs = t.RootCAs
+	}
+
+	if t.Err != nil {
+		opts.HTTPClient = errorClient{err: t.Err}
+		return
+	}
+	if _, ok := opts.HTTPClient.(errorClient); ok {
+		return
+	}
+	opts.HTTPClient = withTLSConfig(opts.HTTPClient, opts.TLSConfig)
+}
+
+// withTLSConfig returns a copy of client whose transport uses config. A nil
+// client stands for http.DefaultClient.
+func withTLSConfig(client HTTPClient, config *tls.Config) HTTPClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	httpClient, ok := client.(*http.Client)
+	if !ok {
+		return errorClient{err: ErrTLSTransport}
+	}
+
+	roundTripper := httpClient.Transport
+	if roundTripper == nil {
+		roundTripper = http.DefaultTransport
+	}
+	transport, ok := roundTripper.(*http.Transport)
+	if !ok {
+		return errorClient{err: ErrTLSTransport}
+	}
+
+	transport = transport.Clone()
+	transport.TLSClientConfig = config
+	configured := *httpClient
+	configured.Transport = transport
+	return &configured
+}
+
+// ShareTLSTransport replaces the TLS options in opts with the HTTP client and
+// TLS configuration they resolved to in resolved, so the clients created from
+// the returned options share one transport instead of each building its own.
+func ShareTLSTransport(opts []RequestOption, resolved *RequestOptions) []RequestOption {
+	shared := make([]RequestOption, 0, len(opts)+1)
+	for _, opt := range opts {
+		if _, ok := opt.(*TLSOption); !ok {
+			shared = append(shared, opt)
+		}
+	}
+	if len(shared) == len(opts) {
+		return opts
+	}
+	return append(shared, &sharedTLSOption{client: resolved.HTTPClient, config: resolved.TLSConfig})
+}
+
+// sharedTLSOption implements the RequestOption interface. It sets the client
+// and configuration built once from the TLS options.
+type sharedTLSOption struct {
+	client HTTPClient
+	config *tls.Config
+}
+
+func (s *sharedTLSOption) applyRequestOptions(opts *RequestOptions) {
+	opts.HTTPClient = s.client
+	opts.TLSConfig = s.config
+}
+
+// NewClientCertificateOption returns a TLSOption that authenticates with the
+// PEM-encoded certificate and private key. The certificate may be followed by
+// its intermediate CAs, which are sent to the server with it.
+func NewClientCertificateOption(certPEM, keyPEM []byte) *TLSOption {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return &TLSOption{Err: fmt.Errorf("invalid client certificate: %w", err)}
+	}
+	return &TLSOption{Certificates: []tls.Certificate{cert}}
+}
+
+// errorClient fails every request with err.
+type errorClient struct {
+	err error
+}
+
+func (c errorClient) Do(*http.Request) (*http.Response, error) {
+	return nil, c.err
+}
@@ -0,0 +1,147 @@
+package core_test
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"testing"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	sdkclient "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/client"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/envsynctest"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMutualTLSServer starts a mutual TLS server and issues a member
+// certificate with an API key client.
+func newMutualTLSServer(t *testing.T) (*envsynctest.Server, *sdk.MemberCertResponse) {
+	t.Helper()
+	server := envsynctest.NewServer(envsynctest.WithMutualTLS())
+	t.Cleanup(server.Close)
+
+	admin := server.Client(option.WithApiKey("admin-key"))
+	ctx := context.Background()
+	_, err := admin.Certificates.InitOrgCa(ctx, &sdk.InitOrgCaRequest{OrgName: "Acme"})
+	require.NoError(t, err)
+	cert, err := admin.Certificates.IssueMemberCert(ctx, &sdk.IssueMemberCertRequest{
+		MemberEmail: "deploy@example.com",
+		Role:        "service",
+	})
+	require.NoError(t, err)
+
+	server.ResetRequests()
+	return server, cert
+}
+
+func TestClientCertificate(t *testing.T) {
+	server, cert := newMutualTLSServer(t)
+	client := server.Client(option.WithClientCertificate([]byte(cert.CertPem), []byte(cert.KeyPem)))
+
+	_, err := client.Certificates.GetOrgCa(context.Background())
+	require.NoError(t, err)
+
+	requests := server.Requests()
+	require.Len(t, requests, 1)
+	require.NotNil(t, requests[0].ClientCertificate)
+	assert.Equal(t, "deploy@example.com", requests[0].ClientCertificate.Subject.CommonName)
+	assert.Empty(t, requests[0].Header.Get("X-API-Key"))
+}
+
+func TestClientCertificateRequired(t *testing.T) {
+	server, _ := newMutualTLSServer(t)
+
+	_, err := server.Client().Certificates.GetOrgCa(context.Background())
+	apiErr, ok := core.AsAPIError(err)
+	require.True(t, ok, "error = %v", err)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
+
+func TestClientCertificateRevoked(t *testing.T) {
+	server, cert := newMutualTLSServer(t)
+	admin := server.Client(option.WithApiKey("admin-key"))
+	_, err := admin.Certificates.RevokeCert(context.Background(), cert.SerialHex, &sdk.RevokeCertRequest{Reason: 1})
+	require.NoError(t, err)
+
+	client := server.Client(
+		option.WithClientCertificate([]byte(cert.CertPem), []byte(cert.KeyPem)),
+		option.WithMaxAttempts(1),
+	)
+	_, err = client.Certificates.GetOrgCa(context.Background())
+	assert.Error(t, err)
+}
+
+func TestClientCertificateInvalid(t *testing.T) {
+	server, cert := newMutualTLSServer(t)
+	client := server.Client(option.WithClientCertificate([]byte(cert.CertPem), []byte("not a key")))
+
+	_, err := client.Certificates.GetOrgCa(context.Background())
+	assert.ErrorContains(t, err, "invalid client certificate")
+	assert.Empty(t, server.Requests())
+}
+
+func TestRootCAs(t *testing.T) {
+	server, cert := newMutualTLSServer(t)
+	ctx := context.Background()
+
+	// The server certificate is not trusted without its root
+	client := sdkclient.NewClient(
+		option.WithBaseURL(server.URL),
+		option.WithApiKey("admin-key"),
+		option.WithMaxAttempts(1),
+	)
+	_, err := client.Certificates.GetOrgCa(ctx)
+	var unknownAuthority x509.UnknownAuthorityError
+	assert.ErrorAs(t, err, &unknownAuthority)
+
+	// An HTTP client set before the TLS options keeps its settings
+	httpClient := &http.Client{}
+	client = server.Client(
+		option.WithHTTPClient(httpClient),
+		option.WithRootCAs(server.RootCAs()),
+		option.WithClientCertificate([]byte(cert.CertPem), []byte(cert.KeyPem)),
+	)
+	_, err = client.Certificates.GetOrgCa(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, httpClient.Transport, "the given HTTP client was modified")
+
+	// Transports other than *http.Transport cannot be configured
+	client = server.Client(
+		option.WithHTTPClient(&http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}),
+		option.WithRootCAs(server.RootCAs()),
+	)
+	_, err = client.Certificates.GetOrgCa(ctx)
+	assert.ErrorIs(t, err, core.ErrTLSTransport)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestShareTLSTransport(t *testing.T) {
+	server, cert := newMutualTLSServer(t)
+	opts := []option.RequestOption{
+		option.WithRootCAs(server.RootCAs()),
+		option.WithClientCertificate([]byte(cert.CertPem), []byte(cert.KeyPem)),
+		option.WithMaxAttempts(1),
+	}
+
+	resolved := core.NewRequestOptions(opts...)
+	shared := core.ShareTLSTransport(opts, resolved)
+	require.Len(t, shared, 2)
+
+	// Every client built from the shared options uses the same transport
+	first := core.NewRequestOptions(shared...)
+	second := core.NewRequestOptions(shared...)
+	assert.Same(t, resolved.HTTPClient, first.HTTPClient)
+	assert.Same(t, first.HTTPClient, second.HTTPClient)
+	assert.Same(t, resolved.TLSConfig, second.TLSConfig)
+	assert.Equal(t, uint(1), second.MaxAttempts)
+
+	withoutTLS := []option.RequestOption{option.WithMaxAttempts(1)}
+	assert.Equal(t, withoutTLS, core.ShareTLSTransport(withoutTLS, core.NewRequestOptions(withoutTLS...)))
+}
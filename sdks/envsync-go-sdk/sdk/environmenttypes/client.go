@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -59,10 +62,13 @@ func (c *Client) GetEnvTypes(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmenttypes.GetEnvTypes",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -105,10 +111,13 @@ func (c *Client) CreateEnvType(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmenttypes.CreateEnvType",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -154,10 +163,13 @@ func (c *Client) GetEnvType(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmenttypes.GetEnvType",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -202,10 +214,13 @@ func (c *Client) DeleteEnvType(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmenttypes.DeleteEnvType",
 			URL:             endpointURL,
 			Method:          http.MethodDelete,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -252,10 +267,13 @@ func (c *Client) UpdateEnvType(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmenttypes.UpdateEnvType",
 			URL:             endpointURL,
 			Method:          http.MethodPatch,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
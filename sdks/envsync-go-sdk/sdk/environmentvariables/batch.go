@@ -0,0 +1,76 @@
+package environmentvariables
+
+import (
+	context "context"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	core "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+	internal "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/internal"
+	option "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+)
+
+// BatchCreateEnvsChunked creates any number of environment variables, split into batches of
+// option.WithBatchSize sent option.WithBatchConcurrency at a time. The
+// result lists the keys that were created and those that failed, and the
+// error is a *core.BatchError if any failed.
+func (c *Client) BatchCreateEnvsChunked(
+	ctx context.Context,
+	request *sdk.BatchCreateEnvsRequest,
+	opts ...option.RequestOption,
+) (*core.BatchResult, error) {
+	result := internal.Batch(ctx, request.GetEnvs(), (*sdk.BatchCreateEnvsRequestEnvsItem).GetKey, core.NewRequestOptions(opts...).Batch,
+		func(ctx context.Context, chunk []*sdk.BatchCreateEnvsRequestEnvsItem) error {
+			_, err := c.BatchCreateEnvs(ctx, &sdk.BatchCreateEnvsRequest{
+				AppId:     request.GetAppId(),
+				EnvTypeId: request.GetEnvTypeId(),
+				Envs:      chunk,
+			}, opts...)
+			return err
+		},
+	)
+	return result, result.Err()
+}
+
+// BatchUpdateEnvsChunked updates any number of environment variables in batches, like
+// BatchCreateEnvsChunked.
+func (c *Client) BatchUpdateEnvsChunked(
+	ctx context.Context,
+	request *sdk.BatchCreateEnvsRequest,
+	opts ...option.RequestOption,
+) (*core.BatchResult, error) {
+	result := internal.Batch(ctx, request.GetEnvs(), (*sdk.BatchCreateEnvsRequestEnvsItem).GetKey, core.NewRequestOptions(opts...).Batch,
+		func(ctx context.Context, chunk []*sdk.BatchCreateEnvsRequestEnvsItem) error {
+			_, err := c.BatchUpdateEnvs(ctx, &sdk.BatchCreateEnvsRequest{
+				AppId:     request.GetAppId(),
+				EnvTypeId: request.GetEnvTypeId(),
+				Envs:      chunk,
+			}, opts...)
+			return err
+		},
+	)
+	return result, result.Err()
+}
+
+// DeleteBatchEnvChunked deletes any number of environment variables in batches, like
+// BatchCreateEnvsChunked.
+func (c *Client) DeleteBatchEnvChunked(
+	ctx context.Context,
+	request *sdk.BatchDeleteEnvsRequest,
+	opts ...option.RequestOption,
+) (*core.BatchResult, error) {
+	var keys []string
+	if request != nil {
+		keys = request.Keys
+	}
+	result := internal.Batch(ctx, keys, func(key string) string { return key }, core.NewRequestOptions(opts...).Batch,
+		func(ctx context.Context, chunk []string) error {
+			_, err := c.DeleteBatchEnv(ctx, &sdk.BatchDeleteEnvsRequest{
+				AppId:     request.AppId,
+				EnvTypeId: request.EnvTypeId,
+				Keys:      chunk,
+			}, opts...)
+			return err
+		},
+	)
+	return result, result.Err()
+}
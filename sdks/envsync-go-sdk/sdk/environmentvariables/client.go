@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -61,10 +64,13 @@ func (c *Client) GetEnvs(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariables.GetEnvs",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -108,10 +114,13 @@ func (c *Client) DeleteEnv(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariables.DeleteEnv",
 			URL:             endpointURL,
 			Method:          http.MethodDelete,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -159,10 +168,13 @@ func (c *Client) GetEnv(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariables.GetEnv",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -210,10 +222,13 @@ func (c *Client) UpdateEnv(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariables.UpdateEnv",
 			URL:             endpointURL,
 			Method:          http.MethodPatch,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -257,10 +272,13 @@ func (c *Client) CreateEnv(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariables.CreateEnv",
 			URL:             endpointURL,
 			Method:          http.MethodPut,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -304,10 +322,13 @@ func (c *Client) BatchCreateEnvs(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariables.BatchCreateEnvs",
 			URL:             endpointURL,
 			Method:          http.MethodPut,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -351,10 +372,13 @@ func (c *Client) DeleteBatchEnv(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariables.DeleteBatchEnv",
 			URL:             endpointURL,
 			Method:          http.MethodDelete,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -398,10 +422,13 @@ func (c *Client) BatchUpdateEnvs(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariables.BatchUpdateEnvs",
 			URL:             endpointURL,
 			Method:          http.MethodPatch,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -61,10 +64,13 @@ func (c *Client) GetEnvHistory(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariablespointintime.GetEnvHistory",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -108,10 +114,13 @@ func (c *Client) GetEnvsAtPointInTime(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariablespointintime.GetEnvsAtPointInTime",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -155,10 +164,13 @@ func (c *Client) GetEnvsAtTimestamp(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariablespointintime.GetEnvsAtTimestamp",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -202,10 +214,13 @@ func (c *Client) GetEnvDiff(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariablespointintime.GetEnvDiff",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -253,10 +268,13 @@ func (c *Client) GetVariableTimeline(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariablespointintime.GetVariableTimeline",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -0,0 +1,44 @@
+package environmentvariablespointintime
+
+import (
+	context "context"
+	iter "iter"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	internal "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/internal"
+	option "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+)
+
+// AllEnvHistory iterates over the environment variable point-in-time history of every
+// page, starting at request.Page. request.PerPage sets the page size and
+// defaults to internal.DefaultPageSize.
+func (c *Client) AllEnvHistory(
+	ctx context.Context,
+	request *sdk.EnvHistoryRequest,
+	opts ...option.RequestOption,
+) iter.Seq2[*sdk.EnvHistoryResponsePitsItem, error] {
+	query := sdk.EnvHistoryRequest{}
+	if request != nil {
+		query = *request
+	}
+	start := 1
+	if query.Page != nil {
+		start = *query.Page
+	}
+	if query.PerPage == nil || *query.PerPage <= 0 {
+		query.PerPage = sdk.Int(internal.DefaultPageSize)
+	}
+
+	return internal.Paginate(ctx, start, func(ctx context.Context, page int) (*internal.Page[*sdk.EnvHistoryResponsePitsItem], error) {
+		pageQuery := query
+		pageQuery.Page = sdk.Int(page)
+		response, err := c.GetEnvHistory(ctx, &pageQuery, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &internal.Page[*sdk.EnvHistoryResponsePitsItem]{
+			Items:      response.GetPits(),
+			TotalPages: response.GetTotalPages(),
+		}, nil
+	})
+}
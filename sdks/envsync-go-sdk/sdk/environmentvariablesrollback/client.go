@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -61,10 +64,13 @@ func (c *Client) RollbackEnvsToPitId(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariablesrollback.RollbackEnvsToPitId",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -108,10 +114,13 @@ func (c *Client) RollbackEnvsToTimestamp(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariablesrollback.RollbackEnvsToTimestamp",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -159,10 +168,13 @@ func (c *Client) RollbackVariableToPitId(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariablesrollback.RollbackVariableToPitId",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -210,10 +222,13 @@ func (c *Client) RollbackVariableToTimestamp(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "environmentvariablesrollback.RollbackVariableToTimestamp",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
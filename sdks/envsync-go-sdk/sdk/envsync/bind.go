@@ -0,0 +1,240 @@
+package envsync
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRequired is the cause of a FieldError for a required variable that is
+// not set and has no default.
+var ErrRequired = errors.New("required variable is not set")
+
+// FieldError describes a variable that could not be bound to a field.
+type FieldError struct {
+	// Field is the path of the struct field, such as "Database.URL".
+	Field string
+	// Key is the variable bound to the field.
+	Key string
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s (%s): %v", e.Key, e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// BindError collects the errors of all fields that could not be bound.
+type BindError struct {
+	Fields []*FieldError
+}
+
+func (e *BindError) Error() string {
+	lines := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		lines[i] = "  " + field.Error()
+	}
+	return fmt.Sprintf("envsync: %d invalid variables:\n%s", len(e.Fields), strings.Join(lines, "\n"))
+}
+
+func (e *BindError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, field := range e.Fields {
+		errs[i] = field
+	}
+	return errs
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	urlType             = reflect.TypeOf(url.URL{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Bind sets the fields of the struct pointed to by dst from values.
+//
+// Fields are bound by their envsync tag, which holds the key of the variable
+// and optionally ",required":
+//
+//	Port int `envsync:"PORT,required"`
+//
+// A default tag gives the value used when the variable is not set. Fields
+// without a tag are ignored, except nested structs whose fields are bound in
+// turn. Strings, bools, integers, floats, time.Duration, url.URL and types
+// implementing encoding.TextUnmarshaler are supported, as well as pointers to
+// and slices of them. Slice values are separated by commas.
+//
+// All fields are bound before Bind returns a *BindError listing every field
+// that is missing or could not be converted.
+func Bind(values map[string]string, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("envsync: Bind requires a non-nil pointer to a struct, got %T", dst)
+	}
+
+	var errs []*FieldError
+	bindStruct(values, v.Elem(), "", &errs)
+	if len(errs) > 0 {
+		return &BindError{Fields: errs}
+	}
+	return nil
+}
+
+func bindStruct(values map[string]string, v reflect.Value, path string, errs *[]*FieldError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := path + field.Name
+
+		tag, tagged := field.Tag.Lookup("envsync")
+		if !tagged {
+			if isNestedStruct(field.Type) {
+				if field.Type.Kind() == reflect.Pointer {
+					if v.Field(i).IsNil() {
+						v.Field(i).Set(reflect.New(field.Type.Elem()))
+					}
+					bindStruct(values, v.Field(i).Elem(), name+".", errs)
+				} else {
+					bindStruct(values, v.Field(i), name+".", errs)
+				}
+			}
+			continue
+		}
+		if tag == "-" {
+			continue
+		}
+
+		key, flags, _ := strings.Cut(tag, ",")
+		if key == "" {
+			key = field.Name
+		}
+		required := false
+		for _, flag := range strings.Split(flags, ",") {
+			switch strings.TrimSpace(flag) {
+			case "":
+			case "required":
+				required = true
+			default:
+				*errs = append(*errs, &FieldError{Field: name, Key: key, Err: fmt.Errorf("unknown tag option %q", flag)})
+			}
+		}
+
+		value, ok := values[key]
+		if !ok {
+			value, ok = field.Tag.Lookup("default")
+		}
+		if !ok {
+			if required {
+				*errs = append(*errs, &FieldError{Field: name, Key: key, Err: ErrRequired})
+			}
+			continue
+		}
+
+		if err := setValue(v.Field(i), value); err != nil {
+			*errs = append(*errs, &FieldError{Field: name, Key: key, Err: err})
+		}
+	}
+}
+
+// isNestedStruct reports whether a field of type t is bound field by field
+// rather than from a single value.
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == urlType {
+		return false
+	}
+	return !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func setValue(v reflect.Value, value string) error {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		// The error of the type may quote the value, which can be a secret
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid %s", v.Type())
+		}
+		return nil
+	}
+
+	switch v.Type() {
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.New("invalid duration")
+		}
+		v.SetInt(int64(d))
+		return nil
+	case urlType:
+		u, err := url.Parse(value)
+		if err != nil {
+			return errors.New("invalid URL")
+		}
+		if u.Scheme == "" {
+			return errors.New("invalid URL: missing scheme")
+		}
+		v.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := setValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Slice:
+		parts := []string{}
+		if strings.TrimSpace(value) != "" {
+			parts = strings.Split(value, ",")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setValue(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		v.Set(slice)
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("invalid boolean")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 0, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s", v.Type())
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 0, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s", v.Type())
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s", v.Type())
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package envsync
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type databaseConfig struct {
+	URL      url.URL `envsync:"DB_URL,required"`
+	MaxConns int     `envsync:"DB_MAX_CONNS" default:"10"`
+}
+
+type testConfig struct {
+	Name     string        `envsync:"NAME"`
+	Debug    bool          `envsync:"DEBUG"`
+	Port     uint16        `envsync:"PORT"`
+	Ratio    float64       `envsync:"RATIO"`
+	Timeout  time.Duration `envsync:"TIMEOUT" default:"5s"`
+	Hosts    []string      `envsync:"HOSTS"`
+	Ports    []int         `envsync:"PORTS"`
+	Callback *url.URL      `envsync:"CALLBACK_URL"`
+	Limit    *int          `envsync:"LIMIT"`
+	Bind     net.IP        `envsync:"BIND"`
+	Ignored  string        `envsync:"-"`
+	Untagged string
+
+	Database databaseConfig
+}
+
+func TestBind(t *testing.T) {
+	var cfg testConfig
+	err := Bind(map[string]string{
+		"NAME":         "api",
+		"DEBUG":        "true",
+		"PORT":         "8080",
+		"RATIO":        "0.5",
+		"HOSTS":        "a.example.com, b.example.com",
+		"PORTS":        "80,443",
+		"CALLBACK_URL": "https://example.com/callback",
+		"BIND":         "127.0.0.1",
+		"DB_URL":       "postgres://localhost/app",
+		"Untagged":     "value",
+		"-":            "value",
+	}, &cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "api", cfg.Name)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, uint16(8080), cfg.Port)
+	assert.Equal(t, 0.5, cfg.Ratio)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, cfg.Hosts)
+	assert.Equal(t, []int{80, 443}, cfg.Ports)
+	require.NotNil(t, cfg.Callback)
+	assert.Equal(t, "/callback", cfg.Callback.Path)
+	assert.Nil(t, cfg.Limit)
+	assert.Equal(t, "127.0.0.1", cfg.Bind.String())
+	assert.Empty(t, cfg.Ignored)
+	assert.Empty(t, cfg.Untagged)
+	assert.Equal(t, "localhost", cfg.Database.URL.Host)
+	assert.Equal(t, 10, cfg.Database.MaxConns)
+}
+
+func TestBindAggregatesErrors(t *testing.T) {
+	var cfg testConfig
+	err := Bind(map[string]string{
+		"DEBUG":   "maybe",
+		"PORT":    "70000",
+		"TIMEOUT": "soon",
+		"PORTS":   "80,https",
+		"BIND":    "s3cr3t-value",
+	}, &cfg)
+
+	var bindErr *BindError
+	require.ErrorAs(t, err, &bindErr)
+
+	fields := make(map[string]string)
+	for _, field := range bindErr.Fields {
+		fields[field.Field] = field.Key
+	}
+	assert.Equal(t, map[string]string{
+		"Debug":        "DEBUG",
+		"Port":         "PORT",
+		"Timeout":      "TIMEOUT",
+		"Ports":        "PORTS",
+		"Bind":         "BIND",
+		"Database.URL": "DB_URL",
+	}, fields)
+	assert.True(t, errors.Is(err, ErrRequired))
+
+	// Values may be secrets, so errors only name the key and type
+	for _, value := range []string{"maybe", "70000", "soon", "https", "s3cr3t-value"} {
+		assert.NotContains(t, err.Error(), value)
+	}
+}
+
+func TestBindRejectsNonStruct(t *testing.T) {
+	var cfg testConfig
+	assert.Error(t, Bind(nil, cfg))
+	assert.Error(t, Bind(nil, (*testConfig)(nil)))
+
+	var n int
+	assert.Error(t, Bind(nil, &n))
+}
+
+func TestBindURLWithoutScheme(t *testing.T) {
+	var cfg databaseConfig
+	err := Bind(map[string]string{"DB_URL": "db.internal/app"}, &cfg)
+	assert.Error(t, err)
+}
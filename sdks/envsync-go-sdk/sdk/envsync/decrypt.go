@@ -0,0 +1,116 @@
+package envsync
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Secrets of applications that are not managed by EnvSync are encrypted by
+// the client with the public key of the application, either directly with
+// RSA-OAEP ("RSA:" prefix) or, for larger values, with an AES-GCM key that is
+// itself encrypted with RSA-OAEP ("HYB:" prefix).
+const (
+	rsaPrefix    = "RSA:"
+	hybridPrefix = "HYB:"
+)
+
+// gcmNonceSize is the size of the AES-GCM nonce in hybrid encrypted values.
+const gcmNonceSize = 12
+
+func parsePrivateKey(privateKeyPEM string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, errors.New("failed to decode private key PEM block")
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+func decrypt(value string, key *rsa.PrivateKey) (string, error) {
+	switch {
+	case strings.HasPrefix(value, rsaPrefix):
+		return decryptRSA(strings.TrimPrefix(value, rsaPrefix), key)
+	case strings.HasPrefix(value, hybridPrefix):
+		return decryptHybrid(strings.TrimPrefix(value, hybridPrefix), key)
+	default:
+		return "", errors.New("unknown encryption method")
+	}
+}
+
+func decryptRSA(encoded string, key *rsa.PrivateKey) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode base64: %w", err)
+	}
+
+	plaintext, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, data, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt with RSA: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// decryptHybrid decrypts a value laid out as the length of the encrypted AES
+// key (2 bytes, big endian), the encrypted AES key, the nonce and the
+// ciphertext.
+func decryptHybrid(encoded string, key *rsa.PrivateKey) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode base64: %w", err)
+	}
+	if len(data) < 2 {
+		return "", errors.New("invalid encrypted data: too short")
+	}
+
+	keyLength := int(binary.BigEndian.Uint16(data))
+	data = data[2:]
+	if len(data) < keyLength+gcmNonceSize {
+		return "", errors.New("invalid encrypted data: insufficient length")
+	}
+
+	aesKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, data[:keyLength], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt AES key: %w", err)
+	}
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	nonce := data[keyLength : keyLength+gcmNonceSize]
+	plaintext, err := gcm.Open(nil, nonce, data[keyLength+gcmNonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt data: %w", err)
+	}
+	return string(plaintext), nil
+}
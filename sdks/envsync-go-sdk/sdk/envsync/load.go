@@ -0,0 +1,273 @@
+// Package envsync loads the variables and secrets of an EnvSync environment
+// into a Go struct:
+//
+//	type Config struct {
+//		DatabaseURL *url.URL      `envsync:"DB_URL,required"`
+//		Timeout     time.Duration `envsync:"TIMEOUT" default:"5s"`
+//		Hosts       []string      `envsync:"HOSTS"`
+//	}
+//
+//	var cfg Config
+//	err := envsync.Load(ctx, &cfg,
+//		envsync.App("api"),
+//		envsync.Env("PROD"),
+//		envsync.RequestOptions(option.WithApiKey(apiKey)),
+//	)
+//
+// See Bind for the supported field types and tags. Watch follows an
+// environment for changes to its variables.
+package envsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	sdkclient "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/client"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+)
+
+// ErrNoPrivateKey is returned when an application encrypts its secrets with
+// its own key pair and no private key was given to decrypt them.
+var ErrNoPrivateKey = errors.New("envsync: application secrets are not managed and no private key was given")
+
+// Option configures Load, Fetch and Watch.
+type Option func(*options)
+
+type options struct {
+	app            string
+	env            string
+	client         *sdkclient.Client
+	requestOptions []option.RequestOption
+	privateKey     string
+	privateKeyFile string
+	skipSecrets    bool
+	pollInterval   time.Duration
+	onError        func(error)
+}
+
+// App selects the application by name or ID. It is required.
+func App(nameOrID string) Option {
+	return func(o *options) {
+		o.app = nameOrID
+	}
+}
+
+// Env selects the environment type by name or ID. Without it the default
+// environment type of the application is used.
+func Env(nameOrID string) Option {
+	return func(o *options) {
+		o.env = nameOrID
+	}
+}
+
+// Client sets the client used to fetch the variables. Without it a client is
+// created from the options given to RequestOptions.
+func Client(c *sdkclient.Client) Option {
+	return func(o *options) {
+		o.client = c
+	}
+}
+
+// RequestOptions sets options, such as the base URL and credentials, for
+// the requests made by Load, Fetch and Watch.
+func RequestOptions(opts ...option.RequestOption) Option {
+	return func(o *options) {
+		o.requestOptions = append(o.requestOptions, opts...)
+	}
+}
+
+// PrivateKey sets the PEM encoded RSA private key used to decrypt the
+// secrets of applications that are not managed by EnvSync.
+func PrivateKey(pem string) Option {
+	return func(o *options) {
+		o.privateKey = pem
+	}
+}
+
+// PrivateKeyFile is like PrivateKey but reads the key from a file.
+func PrivateKeyFile(path string) Option {
+	return func(o *options) {
+		o.privateKeyFile = path
+	}
+}
+
+// WithoutSecrets loads only the variables of the environment.
+func WithoutSecrets() Option {
+	return func(o *options) {
+		o.skipSecrets = true
+	}
+}
+
+// Load fetches the variables and secrets of an environment and binds them to
+// the struct pointed to by dst.
+func Load(ctx context.Context, dst interface{}, opts ...Option) error {
+	values, err := Fetch(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	return Bind(values, dst)
+}
+
+// Fetch returns the variables and secrets of an environment by key. Secrets
+// take precedence over variables with the same key.
+func Fetch(ctx context.Context, opts ...Option) (map[string]string, error) {
+	t, err := resolve(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return t.fetch(ctx)
+}
+
+// target is a resolved application and environment type.
+type target struct {
+	client    *sdkclient.Client
+	app       *sdk.GetAppsResponseItem
+	envTypeID string
+	options   *options
+}
+
+func resolve(ctx context.Context, opts []Option) (*target, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.app == "" {
+		return nil, errors.New("envsync: no application given")
+	}
+	c := o.client
+	if c == nil {
+		c = sdkclient.NewClient(o.requestOptions...)
+	}
+
+	app, err := findApp(ctx, c, o.app, o.requestOptions)
+	if err != nil {
+		return nil, err
+	}
+	envTypeID, err := findEnvType(app, o.env)
+	if err != nil {
+		return nil, err
+	}
+	return &target{client: c, app: app, envTypeID: envTypeID, options: o}, nil
+}
+
+func (t *target) fetch(ctx context.Context) (map[string]string, error) {
+	envs, err := t.client.EnvironmentVariables.GetEnvs(ctx, &sdk.GetEnvRequest{AppId: t.app.Id, EnvTypeId: t.envTypeID}, t.options.requestOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("envsync: fetching variables: %w", err)
+	}
+	values := make(map[string]string, len(envs))
+	for _, env := range envs {
+		values[env.Key] = env.Value
+	}
+
+	if !t.app.EnableSecrets || t.options.skipSecrets {
+		return values, nil
+	}
+	secrets, err := fetchSecrets(ctx, t.client, t.app, t.envTypeID, t.options)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range secrets {
+		values[key] = value
+	}
+	return values, nil
+}
+
+// findApp returns the application with the given ID or, failing that, the
+// only application with the given name.
+func findApp(ctx context.Context, c *sdkclient.Client, nameOrID string, opts []option.RequestOption) (*sdk.GetAppsResponseItem, error) {
+	apps, err := c.Applications.GetApps(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("envsync: fetching applications: %w", err)
+	}
+
+	var matches []*sdk.GetAppsResponseItem
+	for _, app := range apps {
+		if app.Id == nameOrID {
+			return app, nil
+		}
+		if strings.EqualFold(app.Name, nameOrID) {
+			matches = append(matches, app)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("envsync: application %q not found", nameOrID)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("envsync: %d applications are named %q, select one by ID", len(matches), nameOrID)
+	}
+}
+
+func findEnvType(app *sdk.GetAppsResponseItem, nameOrID string) (string, error) {
+	for _, envType := range app.EnvTypes {
+		if nameOrID == "" && envType.IsDefault {
+			return envType.Id, nil
+		}
+		if nameOrID != "" && (envType.Id == nameOrID || strings.EqualFold(envType.Name, nameOrID)) {
+			return envType.Id, nil
+		}
+	}
+	if nameOrID == "" {
+		return "", fmt.Errorf("envsync: application %q has no default environment type", app.Name)
+	}
+	return "", fmt.Errorf("envsync: environment type %q not found in application %q", nameOrID, app.Name)
+}
+
+// fetchSecrets returns the decrypted secrets of the environment. Managed
+// secrets are revealed by the API, others are decrypted with the private key.
+func fetchSecrets(ctx context.Context, c *sdkclient.Client, app *sdk.GetAppsResponseItem, envTypeID string, o *options) (map[string]string, error) {
+	secrets, err := c.Secrets.GetSecrets(ctx, &sdk.GetSecretRequest{AppId: app.Id, EnvTypeId: envTypeID}, o.requestOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("envsync: fetching secrets: %w", err)
+	}
+	values := make(map[string]string, len(secrets))
+	if len(secrets) == 0 {
+		return values, nil
+	}
+
+	if app.IsManagedSecret {
+		keys := make([]string, len(secrets))
+		for i, secret := range secrets {
+			keys[i] = secret.Key
+		}
+		revealed, err := c.Secrets.RevealSecrets(ctx, &sdk.RevealSecretsRequest{AppId: app.Id, EnvTypeId: envTypeID, Keys: keys}, o.requestOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("envsync: revealing secrets: %w", err)
+		}
+		for _, secret := range revealed {
+			values[secret.Key] = secret.Value
+		}
+		return values, nil
+	}
+
+	privateKey := o.privateKey
+	if privateKey == "" && o.privateKeyFile != "" {
+		data, err := os.ReadFile(o.privateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("envsync: reading private key: %w", err)
+		}
+		privateKey = string(data)
+	}
+	if privateKey == "" {
+		return nil, ErrNoPrivateKey
+	}
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("envsync: %w", err)
+	}
+	for _, secret := range secrets {
+		value, err := decrypt(secret.Value, key)
+		if err != nil {
+			return nil, fmt.Errorf("envsync: decrypting secret %s: %w", secret.Key, err)
+		}
+		values[secret.Key] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,113 @@
+package envsync
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/envsynctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type appConfig struct {
+	Host     string `envsync:"HOST,required"`
+	Port     int    `envsync:"PORT"`
+	Password string `envsync:"PASSWORD"`
+}
+
+// createApp creates an application with secrets enabled and a PROD and DEV
+// environment type.
+func createApp(t *testing.T, server *envsynctest.Server, publicKey *string) (appID, prodID string) {
+	t.Helper()
+	ctx := context.Background()
+	client := server.Client()
+
+	enableSecrets := true
+	app, err := client.Applications.CreateApp(ctx, &sdk.CreateAppRequest{Name: "api", EnableSecrets: &enableSecrets, PublicKey: publicKey})
+	require.NoError(t, err)
+
+	isDefault := true
+	_, err = client.EnvironmentTypes.CreateEnvType(ctx, &sdk.CreateEnvTypeRequest{Name: "DEV", AppId: app.Id, IsDefault: &isDefault})
+	require.NoError(t, err)
+	prod, err := client.EnvironmentTypes.CreateEnvType(ctx, &sdk.CreateEnvTypeRequest{Name: "PROD", AppId: app.Id})
+	require.NoError(t, err)
+
+	return app.Id, prod.Id
+}
+
+func TestLoadManagedSecrets(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+	appID, prodID := createApp(t, server, nil)
+	server.SetVariables(appID, prodID, map[string]string{"HOST": "db.internal", "PORT": "5432"})
+	server.SetSecrets(appID, prodID, map[string]string{"PASSWORD": "s3cret"})
+
+	var cfg appConfig
+	err := Load(context.Background(), &cfg, App("API"), Env("prod"), Client(server.Client()))
+	require.NoError(t, err)
+	assert.Equal(t, appConfig{Host: "db.internal", Port: 5432, Password: "s3cret"}, cfg)
+}
+
+func TestLoadLocallyEncryptedSecrets(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	privateDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+
+	encrypted, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, &key.PublicKey, []byte("s3cret"), nil)
+	require.NoError(t, err)
+
+	server := envsynctest.NewServer()
+	defer server.Close()
+	appID, prodID := createApp(t, server, &publicKey)
+	server.SetVariables(appID, prodID, map[string]string{"HOST": "db.internal"})
+	server.SetSecrets(appID, prodID, map[string]string{"PASSWORD": "RSA:" + base64.StdEncoding.EncodeToString(encrypted)})
+
+	var cfg appConfig
+	err = Load(context.Background(), &cfg, App(appID), Env("PROD"), Client(server.Client()))
+	assert.ErrorIs(t, err, ErrNoPrivateKey)
+
+	err = Load(context.Background(), &cfg, App(appID), Env("PROD"), Client(server.Client()), PrivateKey(privateKey))
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", cfg.Password)
+}
+
+func TestFetch(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+	appID, envTypeIDs := server.AddApp("web", "staging", "production")
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "staging.internal"})
+
+	t.Run("default environment type", func(t *testing.T) {
+		values, err := Fetch(context.Background(), App("web"), Client(server.Client()))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"HOST": "staging.internal"}, values)
+	})
+
+	t.Run("unknown application", func(t *testing.T) {
+		_, err := Fetch(context.Background(), App("missing"), Client(server.Client()))
+		assert.ErrorContains(t, err, `application "missing" not found`)
+	})
+
+	t.Run("unknown environment type", func(t *testing.T) {
+		_, err := Fetch(context.Background(), App("web"), Env("qa"), Client(server.Client()))
+		assert.ErrorContains(t, err, `environment type "qa" not found`)
+	})
+
+	t.Run("missing required variable", func(t *testing.T) {
+		var cfg appConfig
+		err := Load(context.Background(), &cfg, App("web"), Env("production"), Client(server.Client()))
+		assert.ErrorIs(t, err, ErrRequired)
+	})
+}
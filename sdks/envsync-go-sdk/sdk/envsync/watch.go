@@ -0,0 +1,271 @@
+package envsync
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+)
+
+const (
+	// DefaultPollInterval is how often Watch checks for a new revision.
+	DefaultPollInterval = 30 * time.Second
+
+	// maxPollBackoff caps the delay between polls while requests fail.
+	maxPollBackoff = 5 * time.Minute
+)
+
+// PollInterval sets how often Watch checks for changes. It defaults to
+// DefaultPollInterval.
+func PollInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = d
+	}
+}
+
+// OnError sets a function called with the errors of failed polls. Watch keeps
+// polling after an error, backing off up to a few minutes between attempts.
+func OnError(f func(error)) Option {
+	return func(o *options) {
+		o.onError = f
+	}
+}
+
+// Snapshot is an immutable set of variables at one revision of an
+// environment. It is safe for concurrent use.
+type Snapshot struct {
+	revision string
+	values   map[string]string
+}
+
+// Revision returns the ID of the point in time the snapshot was taken at, or
+// "" if the environment has no history.
+func (s *Snapshot) Revision() string {
+	return s.revision
+}
+
+// Get returns the value of a variable and whether it is set.
+func (s *Snapshot) Get(key string) (string, bool) {
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Values returns a copy of all variables by key.
+func (s *Snapshot) Values() map[string]string {
+	values := make(map[string]string, len(s.values))
+	for key, value := range s.values {
+		values[key] = value
+	}
+	return values
+}
+
+// Bind binds the variables to the struct pointed to by dst like Bind.
+func (s *Snapshot) Bind(dst interface{}) error {
+	return Bind(s.values, dst)
+}
+
+// ChangeSet describes the difference between two snapshots. Keys are sorted.
+type ChangeSet struct {
+	Added    []string
+	Modified []string
+	Deleted  []string
+
+	Previous *Snapshot
+	Current  *Snapshot
+}
+
+func diffSnapshots(previous, current *Snapshot) ChangeSet {
+	changes := ChangeSet{Previous: previous, Current: current}
+	for key, value := range current.values {
+		old, ok := previous.values[key]
+		if !ok {
+			changes.Added = append(changes.Added, key)
+		} else if old != value {
+			changes.Modified = append(changes.Modified, key)
+		}
+	}
+	for key := range previous.values {
+		if _, ok := current.values[key]; !ok {
+			changes.Deleted = append(changes.Deleted, key)
+		}
+	}
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Modified)
+	sort.Strings(changes.Deleted)
+	return changes
+}
+
+func (c ChangeSet) empty() bool {
+	return len(c.Added) == 0 && len(c.Modified) == 0 && len(c.Deleted) == 0
+}
+
+// Watcher follows the variables of an environment. Secrets are not watched.
+type Watcher struct {
+	target   *target
+	interval time.Duration
+	onError  func(error)
+
+	snapshot atomic.Pointer[Snapshot]
+	changes  chan ChangeSet
+}
+
+// Watch loads the variables of an environment and then polls its
+// point-in-time history for new revisions until ctx is cancelled. It fails
+// if the initial load fails; later errors are retried with backoff and
+// reported to the function given to OnError.
+//
+//	w, err := envsync.Watch(ctx, envsync.App("api"), envsync.Env("PROD"))
+//	if err != nil {
+//		return err
+//	}
+//	for changes := range w.Changes() {
+//		log.Printf("config changed: %v", changes.Modified)
+//	}
+func Watch(ctx context.Context, opts ...Option) (*Watcher, error) {
+	// The caller's slice may have room to spare, which append would write to
+	opts = append(opts[:len(opts):len(opts)], WithoutSecrets())
+	t, err := resolve(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		target:   t,
+		interval: t.options.pollInterval,
+		onError:  t.options.onError,
+		changes:  make(chan ChangeSet, 1),
+	}
+	if w.interval <= 0 {
+		w.interval = DefaultPollInterval
+	}
+
+	snapshot, err := w.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w.snapshot.Store(snapshot)
+
+	go w.poll(ctx)
+	return w, nil
+}
+
+// Snapshot returns the latest variables.
+func (w *Watcher) Snapshot() *Snapshot {
+	return w.snapshot.Load()
+}
+
+// Changes returns the changes observed by the watcher. A change set is sent
+// when a new revision changed a variable, and the snapshot is updated before
+// it is sent. Polling never waits for the receiver: a change set that was not
+// received yet is replaced by one spanning both revisions, so reading the
+// channel is optional. It is closed when the context given to Watch is
+// cancelled.
+func (w *Watcher) Changes() <-chan ChangeSet {
+	return w.changes
+}
+
+// revision returns the ID of the latest point in time of the environment.
+func (w *Watcher) revision(ctx context.Context) (string, error) {
+	page, perPage := 1, 1
+	history, err := w.target.client.EnvironmentVariablesPointInTime.GetEnvHistory(ctx, &sdk.EnvHistoryRequest{
+		AppId:     w.target.app.Id,
+		EnvTypeId: w.target.envTypeID,
+		Page:      &page,
+		PerPage:   &perPage,
+	}, w.target.options.requestOptions...)
+	if err != nil {
+		return "", fmt.Errorf("envsync: fetching history: %w", err)
+	}
+	if len(history.Pits) == 0 {
+		return "", nil
+	}
+	return history.Pits[0].Id, nil
+}
+
+// load takes a snapshot of the environment. The revision is read first, so
+// a change made while the variables are fetched is seen by the next poll.
+func (w *Watcher) load(ctx context.Context) (*Snapshot, error) {
+	revision, err := w.revision(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values, err := w.target.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{revision: revision, values: values}, nil
+}
+
+func (w *Watcher) poll(ctx context.Context) {
+	defer close(w.changes)
+
+	delay := w.interval
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		changes, err := w.check(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if w.onError != nil {
+				w.onError(err)
+			}
+			delay = min(delay*2, max(maxPollBackoff, w.interval))
+			timer.Reset(delay)
+			continue
+		}
+		delay = w.interval
+
+		if changes != nil {
+			w.publish(*changes)
+		}
+		timer.Reset(delay)
+	}
+}
+
+// publish sends changes without blocking. A pending change set is merged
+// into changes, so the receiver gets the difference to the last snapshot it
+// saw. poll is the only sender, so the channel has room after the merge.
+func (w *Watcher) publish(changes ChangeSet) {
+	select {
+	case pending := <-w.changes:
+		changes = diffSnapshots(pending.Previous, changes.Current)
+	default:
+	}
+	if !changes.empty() {
+		w.changes <- changes
+	}
+}
+
+// check loads a new snapshot if the revision changed and returns the changes
+// to the variables, if any.
+func (w *Watcher) check(ctx context.Context) (*ChangeSet, error) {
+	previous := w.Snapshot()
+	revision, err := w.revision(ctx)
+	if err != nil || revision == previous.revision {
+		return nil, err
+	}
+
+	current, err := w.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w.snapshot.Store(current)
+
+	changes := diffSnapshots(previous, current)
+	if changes.empty() {
+		return nil, nil
+	}
+	return &changes, nil
+}
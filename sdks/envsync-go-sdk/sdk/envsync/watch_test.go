@@ -0,0 +1,185 @@
+package envsync
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/envsynctest"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, changes <-chan ChangeSet) ChangeSet {
+	t.Helper()
+	select {
+	case c, ok := <-changes:
+		require.True(t, ok, "changes closed")
+		return c
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change received")
+		return ChangeSet{}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+	appID, envTypeIDs := server.AddApp("api", "prod")
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "a", "PORT": "80", "OLD": "x"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w, err := Watch(ctx, App("api"), Client(server.Client()), PollInterval(5*time.Millisecond))
+	require.NoError(t, err)
+
+	initial := w.Snapshot()
+	host, ok := initial.Get("HOST")
+	assert.True(t, ok)
+	assert.Equal(t, "a", host)
+
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "b", "PORT": "80", "NEW": "y"})
+
+	changes := receive(t, w.Changes())
+	assert.Equal(t, []string{"NEW"}, changes.Added)
+	assert.Equal(t, []string{"HOST"}, changes.Modified)
+	assert.Equal(t, []string{"OLD"}, changes.Deleted)
+	assert.Same(t, initial, changes.Previous)
+	assert.Same(t, changes.Current, w.Snapshot())
+	assert.NotEqual(t, initial.Revision(), w.Snapshot().Revision())
+
+	var cfg struct {
+		Host string `envsync:"HOST"`
+	}
+	require.NoError(t, w.Snapshot().Bind(&cfg))
+	assert.Equal(t, "b", cfg.Host)
+
+	// The previous snapshot is unchanged
+	host, _ = initial.Get("HOST")
+	assert.Equal(t, "a", host)
+
+	cancel()
+	select {
+	case _, ok := <-w.Changes():
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("changes not closed after cancel")
+	}
+}
+
+func TestWatchWithoutReadingChanges(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+	appID, envTypeIDs := server.AddApp("api", "prod")
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "a", "PORT": "80"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w, err := Watch(ctx, App("api"), Client(server.Client()), PollInterval(5*time.Millisecond))
+	require.NoError(t, err)
+	initial := w.Snapshot()
+
+	hostIs := func(want string) func() bool {
+		return func() bool {
+			host, _ := w.Snapshot().Get("HOST")
+			return host == want
+		}
+	}
+
+	// Nobody receives from Changes, yet the snapshot follows both revisions
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "b", "PORT": "80"})
+	require.Eventually(t, hostIs("b"), 5*time.Second, 5*time.Millisecond)
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "c", "PORT": "81"})
+	require.Eventually(t, hostIs("c"), 5*time.Second, 5*time.Millisecond)
+
+	// Once polling stopped, the pending change set spans both revisions
+	cancel()
+	changes := receive(t, w.Changes())
+	assert.Same(t, initial, changes.Previous)
+	assert.Same(t, w.Snapshot(), changes.Current)
+	assert.Equal(t, []string{"HOST", "PORT"}, changes.Modified)
+}
+
+func TestWatchRecoversFromErrors(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+	appID, envTypeIDs := server.AddApp("api", "prod")
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "a"})
+
+	var mu sync.Mutex
+	var errs []error
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w, err := Watch(ctx, App("api"), Client(server.Client()),
+		PollInterval(5*time.Millisecond),
+		RequestOptions(option.WithMaxAttempts(1)),
+		OnError(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}),
+	)
+	require.NoError(t, err)
+
+	server.Inject(envsynctest.Fault{Path: "/api/env/history", StatusCode: http.StatusServiceUnavailable, Times: 2})
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "b"})
+
+	changes := receive(t, w.Changes())
+	assert.Equal(t, []string{"HOST"}, changes.Modified)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, errs, 2)
+}
+
+func TestWatchIgnoresRevisionsWithoutChanges(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+	appID, envTypeIDs := server.AddApp("api", "prod")
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "a"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w, err := Watch(ctx, App("api"), Client(server.Client()), PollInterval(time.Hour))
+	require.NoError(t, err)
+	initial := w.Snapshot()
+
+	// A change that is reverted before the next poll leaves two new revisions
+	// but no difference
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "b"})
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "a"})
+
+	changes, err := w.check(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, changes)
+	assert.NotEqual(t, initial.Revision(), w.Snapshot().Revision())
+}
+
+func TestWatchFailsWhenInitialLoadFails(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+
+	_, err := Watch(context.Background(), App("missing"), Client(server.Client()))
+	assert.Error(t, err)
+}
+
+func TestWatchKeepsCallerOptions(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+
+	// Spare capacity after the options must not be written to
+	opts := make([]Option, 2, 3)
+	opts[0], opts[1] = App("missing"), Client(server.Client())
+	spare := opts[:3]
+	spare[2] = Env("PROD")
+
+	_, err := Watch(context.Background(), opts...)
+	assert.Error(t, err)
+
+	var o options
+	spare[2](&o)
+	assert.Equal(t, "PROD", o.env)
+}
@@ -0,0 +1,376 @@
+package envsynctest
+
+import (
+	"net/http"
+	"sort"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	"github.com/google/uuid"
+)
+
+type app struct {
+	id              string
+	name            string
+	description     string
+	metadata        map[string]interface{}
+	enableSecrets   bool
+	isManagedSecret bool
+	publicKey       *string
+	createdAt       string
+	updatedAt       string
+	seq             int
+}
+
+type envType struct {
+	id          string
+	appID       string
+	name        string
+	color       string
+	isDefault   bool
+	isProtected bool
+	createdAt   string
+	updatedAt   string
+	seq         int
+}
+
+// AddApp creates an application with the given environment types, the first
+// of which is the default, and returns their IDs.
+func (s *Server) AddApp(name string, envTypeNames ...string) (appID string, envTypeIDs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.createApp(&sdk.CreateAppRequest{Name: name})
+	for i, envTypeName := range envTypeNames {
+		isDefault := i == 0
+		e := s.createEnvType(&sdk.CreateEnvTypeRequest{
+			Name:      envTypeName,
+			AppId:     a.id,
+			IsDefault: &isDefault,
+		})
+		envTypeIDs = append(envTypeIDs, e.id)
+	}
+	return a.id, envTypeIDs
+}
+
+func (s *Server) registerApps(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/app", s.getApps)
+	mux.HandleFunc("POST /api/app", s.postApp)
+	mux.HandleFunc("GET /api/app/{id}", s.getApp)
+	mux.HandleFunc("PATCH /api/app/{id}", s.patchApp)
+	mux.HandleFunc("DELETE /api/app/{id}", s.deleteApp)
+
+	mux.HandleFunc("GET /api/env_type", s.getEnvTypes)
+	mux.HandleFunc("POST /api/env_type", s.postEnvType)
+	mux.HandleFunc("GET /api/env_type/{id}", s.getEnvType)
+	mux.HandleFunc("PATCH /api/env_type/{id}", s.patchEnvType)
+	mux.HandleFunc("DELETE /api/env_type/{id}", s.deleteEnvType)
+}
+
+func (s *Server) createApp(request *sdk.CreateAppRequest) *app {
+	now := s.timestamp()
+	a := &app{
+		id:          uuid.NewString(),
+		name:        request.Name,
+		description: request.Description,
+		metadata:    request.Metadata,
+		publicKey:   request.PublicKey,
+		createdAt:   now,
+		updatedAt:   now,
+		seq:         s.nextSeq(),
+	}
+	if request.EnableSecrets != nil {
+		a.enableSecrets = *request.EnableSecrets
+	}
+	// Without a public key the server manages the secret encryption
+	a.isManagedSecret = a.enableSecrets && a.publicKey == nil
+	if a.metadata == nil {
+		a.metadata = map[string]interface{}{}
+	}
+	s.apps[a.id] = a
+	return a
+}
+
+func (s *Server) createEnvType(request *sdk.CreateEnvTypeRequest) *envType {
+	now := s.timestamp()
+	e := &envType{
+		id:        uuid.NewString(),
+		appID:     request.AppId,
+		name:      request.Name,
+		color:     "#6366f1",
+		createdAt: now,
+		updatedAt: now,
+		seq:       s.nextSeq(),
+	}
+	if request.Color != nil {
+		e.color = *request.Color
+	}
+	if request.IsDefault != nil {
+		e.isDefault = *request.IsDefault
+	}
+	if request.IsProtected != nil {
+		e.isProtected = *request.IsProtected
+	}
+	s.envTypes[e.id] = e
+	return e
+}
+
+// sortedApps returns the apps ordered by creation.
+func (s *Server) sortedApps() []*app {
+	apps := make([]*app, 0, len(s.apps))
+	for _, a := range s.apps {
+		apps = append(apps, a)
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].seq < apps[j].seq
+	})
+	return apps
+}
+
+// appEnvTypes returns the env types of an app ordered by creation.
+func (s *Server) appEnvTypes(appID string) []*envType {
+	var envTypes []*envType
+	for _, e := range s.envTypes {
+		if appID == "" || e.appID == appID {
+			envTypes = append(envTypes, e)
+		}
+	}
+	sort.Slice(envTypes, func(i, j int) bool {
+		return envTypes[i].seq < envTypes[j].seq
+	})
+	return envTypes
+}
+
+func (s *Server) countEntries(stores map[scope]*store, appID string) int {
+	count := 0
+	for sc, st := range stores {
+		if sc.appID == appID {
+			count += len(st.entries)
+		}
+	}
+	return count
+}
+
+func (s *Server) getApps(w http.ResponseWriter, r *http.Request) {
+	response := sdk.GetAppsResponse{}
+	for _, a := range s.sortedApps() {
+		item := &sdk.GetAppsResponseItem{
+			Id:              a.id,
+			Name:            a.name,
+			Description:     a.description,
+			EnableSecrets:   a.enableSecrets,
+			IsManagedSecret: a.isManagedSecret,
+			PublicKey:       a.publicKey,
+			Metadata:        a.metadata,
+			OrgId:           OrgID,
+			EnvCount:        float64(s.countEntries(s.variables, a.id)),
+			SecretCount:     float64(s.countEntries(s.secrets, a.id)),
+			EnvTypes:        []*sdk.GetAppsResponseItemEnvTypesItem{},
+			CreatedAt:       a.createdAt,
+			UpdatedAt:       a.updatedAt,
+		}
+		for _, e := range s.appEnvTypes(a.id) {
+			item.EnvTypes = append(item.EnvTypes, &sdk.GetAppsResponseItemEnvTypesItem{
+				Id:          e.id,
+				Name:        e.name,
+				IsDefault:   e.isDefault,
+				IsProtected: e.isProtected,
+				Color:       e.color,
+			})
+		}
+		response = append(response, item)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) postApp(w http.ResponseWriter, r *http.Request) {
+	var request sdk.CreateAppRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if request.Name == "" {
+		writeError(w, http.StatusBadRequest, "", "Name is required.")
+		return
+	}
+
+	a := s.createApp(&request)
+	s.audit("app_created", "App "+a.name+" created.", map[string]interface{}{"app_id": a.id})
+	writeJSON(w, http.StatusCreated, &sdk.CreateAppResponse{Message: "App created successfully", Id: a.id})
+}
+
+func (s *Server) getApp(w http.ResponseWriter, r *http.Request) {
+	a, ok := s.apps[r.PathValue("id")]
+	if !ok {
+		notFound(w, "App", r.PathValue("id"))
+		return
+	}
+
+	response := &sdk.GetAppResponse{
+		Id:              a.id,
+		Name:            a.name,
+		Description:     a.description,
+		Metadata:        a.metadata,
+		EnableSecrets:   a.enableSecrets,
+		IsManagedSecret: a.isManagedSecret,
+		PublicKey:       a.publicKey,
+		OrgId:           OrgID,
+		EnvCount:        float64(s.countEntries(s.variables, a.id)),
+		SecretCount:     float64(s.countEntries(s.secrets, a.id)),
+		EnvTypes:        []*sdk.GetAppResponseEnvTypesItem{},
+		CreatedAt:       a.createdAt,
+		UpdatedAt:       a.updatedAt,
+	}
+	for _, e := range s.appEnvTypes(a.id) {
+		response.EnvTypes = append(response.EnvTypes, &sdk.GetAppResponseEnvTypesItem{
+			Id:          e.id,
+			Name:        e.name,
+			IsDefault:   e.isDefault,
+			IsProtected: e.isProtected,
+			Color:       e.color,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) patchApp(w http.ResponseWriter, r *http.Request) {
+	a, ok := s.apps[r.PathValue("id")]
+	if !ok {
+		notFound(w, "App", r.PathValue("id"))
+		return
+	}
+	var request sdk.UpdateAppRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	if request.Name != nil {
+		a.name = *request.Name
+	}
+	if request.Description != nil {
+		a.description = *request.Description
+	}
+	if request.Metadata != nil {
+		a.metadata = request.Metadata
+	}
+	a.updatedAt = s.timestamp()
+	s.audit("app_updated", "App "+a.name+" updated.", map[string]interface{}{"app_id": a.id})
+	writeJSON(w, http.StatusOK, &sdk.UpdateAppResponse{Message: "App updated successfully"})
+}
+
+func (s *Server) deleteApp(w http.ResponseWriter, r *http.Request) {
+	a, ok := s.apps[r.PathValue("id")]
+	if !ok {
+		notFound(w, "App", r.PathValue("id"))
+		return
+	}
+
+	delete(s.apps, a.id)
+	for id, e := range s.envTypes {
+		if e.appID == a.id {
+			delete(s.envTypes, id)
+		}
+	}
+	for sc := range s.variables {
+		if sc.appID == a.id {
+			delete(s.variables, sc)
+		}
+	}
+	for sc := range s.secrets {
+		if sc.appID == a.id {
+			delete(s.secrets, sc)
+		}
+	}
+	s.audit("app_deleted", "App "+a.name+" deleted.", map[string]interface{}{"app_id": a.id})
+	writeJSON(w, http.StatusOK, &sdk.DeleteAppResponse{Message: "App deleted successfully"})
+}
+
+func (e *envType) response() *sdk.EnvTypeResponse {
+	return &sdk.EnvTypeResponse{
+		Id:          e.id,
+		Name:        e.name,
+		OrgId:       OrgID,
+		AppId:       e.appID,
+		Color:       e.color,
+		IsDefault:   e.isDefault,
+		IsProtected: e.isProtected,
+		CreatedAt:   e.createdAt,
+		UpdatedAt:   e.updatedAt,
+	}
+}
+
+func (s *Server) getEnvTypes(w http.ResponseWriter, r *http.Request) {
+	response := sdk.EnvTypesResponse{}
+	for _, e := range s.appEnvTypes("") {
+		response = append(response, e.response())
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) postEnvType(w http.ResponseWriter, r *http.Request) {
+	var request sdk.CreateEnvTypeRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if request.Name == "" || request.AppId == "" {
+		writeError(w, http.StatusBadRequest, "", "name and app_id are required.")
+		return
+	}
+	if _, ok := s.apps[request.AppId]; !ok {
+		validationError(w, "App not found: "+request.AppId)
+		return
+	}
+
+	e := s.createEnvType(&request)
+	s.audit("env_type_created", "Environment type "+e.name+" created.", map[string]interface{}{"app_id": e.appID, "env_type_id": e.id})
+	writeJSON(w, http.StatusCreated, e.response())
+}
+
+func (s *Server) getEnvType(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.envTypes[r.PathValue("id")]
+	if !ok {
+		notFound(w, "Environment type", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, e.response())
+}
+
+func (s *Server) patchEnvType(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.envTypes[r.PathValue("id")]
+	if !ok {
+		notFound(w, "Environment type", r.PathValue("id"))
+		return
+	}
+	var request sdk.UpdateEnvTypeRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	if request.Name != "" {
+		e.name = request.Name
+	}
+	if request.Color != nil {
+		e.color = *request.Color
+	}
+	if request.IsDefault != nil {
+		e.isDefault = *request.IsDefault
+	}
+	if request.IsProtected != nil {
+		e.isProtected = *request.IsProtected
+	}
+	e.updatedAt = s.timestamp()
+	s.audit("env_type_updated", "Environment type "+e.name+" updated.", map[string]interface{}{"app_id": e.appID, "env_type_id": e.id})
+	writeJSON(w, http.StatusOK, e.response())
+}
+
+func (s *Server) deleteEnvType(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.envTypes[r.PathValue("id")]
+	if !ok {
+		notFound(w, "Environment type", r.PathValue("id"))
+		return
+	}
+
+	delete(s.envTypes, e.id)
+	delete(s.variables, scope{appID: e.appID, envTypeID: e.id})
+	delete(s.secrets, scope{appID: e.appID, envTypeID: e.id})
+	s.audit("env_type_deleted", "Environment type "+e.name+" deleted.", map[string]interface{}{"app_id": e.appID, "env_type_id": e.id})
+	writeJSON(w, http.StatusOK, &sdk.DeleteEnvTypeRequest{Id: e.id})
+}
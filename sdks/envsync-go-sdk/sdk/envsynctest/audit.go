@@ -0,0 +1,124 @@
+package envsynctest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	"github.com/google/uuid"
+)
+
+const defaultAuditLogsPerPage = 20
+
+// pastTimes are the windows accepted by filter_by_past_time.
+var pastTimes = map[string]time.Duration{
+	"last_3_hours":  3 * time.Hour,
+	"last_24_hours": 24 * time.Hour,
+	"last_7_days":   7 * 24 * time.Hour,
+	"last_30_days":  30 * 24 * time.Hour,
+	"last_90_days":  90 * 24 * time.Hour,
+	"last_180_days": 180 * 24 * time.Hour,
+	"last_1_year":   365 * 24 * time.Hour,
+}
+
+type auditLog struct {
+	id        string
+	action    string
+	userID    string
+	details   string
+	message   string
+	createdAt time.Time
+}
+
+// AuditActions returns the actions of the audit log, oldest first.
+func (s *Server) AuditActions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	actions := make([]string, len(s.auditLogs))
+	for i, log := range s.auditLogs {
+		actions[i] = log.action
+	}
+	return actions
+}
+
+func (s *Server) audit(action, message string, details map[string]interface{}) {
+	encoded, _ := json.Marshal(details)
+	s.auditLogs = append(s.auditLogs, &auditLog{
+		id:        uuid.NewString(),
+		action:    action,
+		userID:    UserID,
+		details:   string(encoded),
+		message:   message,
+		createdAt: s.now(),
+	})
+}
+
+func (s *Server) registerAuditLogs(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/audit_log", s.getAuditLogs)
+}
+
+func (s *Server) getAuditLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, perPage := 1, defaultAuditLogsPerPage
+	if value, err := strconv.Atoi(query.Get("page")); err == nil && value > 0 {
+		page = value
+	}
+	if value, err := strconv.Atoi(query.Get("per_page")); err == nil && value > 0 {
+		perPage = value
+	}
+
+	user := query.Get("filter_by_user")
+	category := query.Get("filter_by_category")
+	var since time.Time
+	if window, ok := pastTimes[query.Get("filter_by_past_time")]; ok {
+		since = s.now().Add(-window)
+	}
+
+	// Newest first, like the API
+	var matching []*auditLog
+	for i := len(s.auditLogs) - 1; i >= 0; i-- {
+		log := s.auditLogs[i]
+		if user != "" && log.userID != user {
+			continue
+		}
+		if category != "" && !matchCategory(category, log.action) {
+			continue
+		}
+		if log.createdAt.Before(since) {
+			continue
+		}
+		matching = append(matching, log)
+	}
+
+	response := &sdk.GetAuditLogsResponseWrapper{
+		AuditLogs:  sdk.GetAuditLogsResponse{},
+		TotalPages: float64((len(matching) + perPage - 1) / perPage),
+	}
+	for i := (page - 1) * perPage; i >= 0 && i < len(matching) && i < page*perPage; i++ {
+		log := matching[i]
+		response.AuditLogs = append(response.AuditLogs, &sdk.GetAuditLogsResponseItem{
+			Id:        log.id,
+			Action:    log.action,
+			OrgId:     OrgID,
+			UserId:    log.userID,
+			Details:   log.details,
+			Message:   log.message,
+			CreatedAt: formatTime(log.createdAt),
+			UpdatedAt: formatTime(log.createdAt),
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// matchCategory matches an action against a category such as "app*", where
+// '*' matches any suffix like the API's LIKE pattern.
+func matchCategory(category, action string) bool {
+	prefix, wildcard := strings.CutSuffix(category, "*")
+	if wildcard {
+		return strings.HasPrefix(action, prefix)
+	}
+	return action == category
+}
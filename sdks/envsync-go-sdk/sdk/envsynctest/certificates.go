@@ -0,0 +1,462 @@
+package envsynctest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"sort"
+	"time"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	"github.com/google/uuid"
+)
+
+const (
+	certTypeOrgCA  = "org_ca"
+	certTypeMember = "member"
+
+	certStatusActive  = "active"
+	certStatusRevoked = "revoked"
+)
+
+// memberCertValidity is the lifetime of issued member certificates.
+const memberCertValidity = 365 * 24 * time.Hour
+
+// issuer is a CA certificate together with its private key.
+type issuer struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+type certificate struct {
+	item   *sdk.CertificateListResponseItem
+	cert   *x509.Certificate
+	reason int
+	seq    int
+}
+
+// certificateAuthority holds the root CA, created with the server, and the
+// organization CA and certificates issued through the API.
+type certificateAuthority struct {
+	root      *issuer
+	org       *issuer
+	certs     map[string]*certificate
+	crlNumber int64
+}
+
+func (s *Server) registerCertificates(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/certificate/ca/init", s.initOrgCA)
+	mux.HandleFunc("GET /api/certificate/ca", s.getOrgCA)
+	mux.HandleFunc("GET /api/certificate/root-ca", s.getRootCA)
+	mux.HandleFunc("POST /api/certificate/issue", s.issueMemberCert)
+	mux.HandleFunc("POST /api/certificate/issue/csr", s.issueMemberCertFromCSR)
+	mux.HandleFunc("GET /api/certificate/crl", s.getCRL)
+	mux.HandleFunc("GET /api/certificate", s.listCertificates)
+	mux.HandleFunc("GET /api/certificate/{id}", s.getCertificate)
+	mux.HandleFunc("POST /api/certificate/{serial}/revoke", s.withCertificate(s.revokeCertificate))
+	mux.HandleFunc("GET /api/certificate/{serial}/ocsp", s.checkOCSP)
+}
+
+// RootCA returns the root certificate that anchors all certificates issued by
+// the server.
+func (s *Server) RootCA() *x509.Certificate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.certificateAuthority().root.cert
+}
+
+// certificateAuthority returns the CA state, creating the root CA on first
+// use.
+func (s *Server) certificateAuthority() *certificateAuthority {
+	if s.certificates != nil {
+		return s.certificates
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic("envsynctest: generating root CA key: " + err.Error())
+	}
+	now := s.now()
+	template := &x509.Certificate{
+		SerialNumber:          randomSerial(),
+		Subject:               pkix.Name{CommonName: "EnvSync Test Root CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		panic("envsynctest: creating root CA: " + err.Error())
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	s.certificates = &certificateAuthority{
+		root:  &issuer{cert: cert, key: key},
+		certs: make(map[string]*certificate),
+	}
+	return s.certificates
+}
+
+func randomSerial() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		panic("envsynctest: generating serial: " + err.Error())
+	}
+	return serial
+}
+
+func encodePEM(blockType string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
+
+// issue signs a certificate for key from template and records it.
+func (s *Server) issue(parent *issuer, template *x509.Certificate, key crypto.PublicKey, certType, email string, description *string, metadata map[string]string) (*certificate, error) {
+	template.SerialNumber = randomSerial()
+	der, err := x509.CreateCertificate(rand.Reader, template, parent.cert, key, parent.key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.timestamp()
+	notBefore := formatTime(cert.NotBefore)
+	notAfter := formatTime(cert.NotAfter)
+	item := &sdk.CertificateListResponseItem{
+		Id:          uuid.NewString(),
+		OrgId:       OrgID,
+		SerialHex:   hex.EncodeToString(cert.SerialNumber.Bytes()),
+		CertType:    certType,
+		SubjectCn:   cert.Subject.CommonName,
+		Status:      certStatusActive,
+		NotBefore:   &notBefore,
+		NotAfter:    &notAfter,
+		Description: description,
+		Metadata:    map[string]*string{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if email != "" {
+		item.SubjectEmail = &email
+	}
+	for k, v := range metadata {
+		item.Metadata[k] = &v
+	}
+
+	c := &certificate{item: item, cert: cert, seq: s.nextSeq()}
+	s.certificateAuthority().certs[item.SerialHex] = c
+	return c, nil
+}
+
+func (s *Server) initOrgCA(w http.ResponseWriter, r *http.Request) {
+	var request sdk.InitOrgCaRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if request.OrgName == "" {
+		writeError(w, http.StatusBadRequest, "", "org_name is required.")
+		return
+	}
+	ca := s.certificateAuthority()
+	if ca.org != nil {
+		conflict(w, "Organization CA already initialized")
+		return
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	now := s.now()
+	c, err := s.issue(ca.root, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: request.OrgName + " CA", Organization: []string{request.OrgName}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              ca.root.cert.NotAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}, key.Public(), certTypeOrgCA, "", request.Description, nil)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	ca.org = &issuer{cert: c.cert, key: key}
+
+	s.audit("cert_ca_initialized", "Organization CA initialized: "+request.OrgName, map[string]interface{}{
+		"certificate_id": c.item.Id,
+		"serial_hex":     c.item.SerialHex,
+	})
+	writeJSON(w, http.StatusCreated, c.orgCAResponse())
+}
+
+func (c *certificate) orgCAResponse() *sdk.OrgCaResponse {
+	certPEM := encodePEM("CERTIFICATE", c.cert.Raw)
+	return &sdk.OrgCaResponse{
+		Id:        c.item.Id,
+		OrgId:     c.item.OrgId,
+		SerialHex: c.item.SerialHex,
+		CertType:  c.item.CertType,
+		SubjectCn: c.item.SubjectCn,
+		Status:    c.item.Status,
+		CertPem:   &certPEM,
+		CreatedAt: c.item.CreatedAt,
+	}
+}
+
+func (s *Server) getOrgCA(w http.ResponseWriter, r *http.Request) {
+	ca := s.certificateAuthority()
+	if ca.org == nil {
+		writeError(w, http.StatusNotFound, "", "Organization CA not initialized.")
+		return
+	}
+	writeJSON(w, http.StatusOK, ca.certs[hex.EncodeToString(ca.org.cert.SerialNumber.Bytes())].orgCAResponse())
+}
+
+func (s *Server) getRootCA(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, &sdk.RootCaResponse{
+		CertPem: encodePEM("CERTIFICATE", s.certificateAuthority().root.cert.Raw),
+	})
+}
+
+func (s *Server) issueMemberCert(w http.ResponseWriter, r *http.Request) {
+	var request sdk.IssueMemberCertRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	response, ok := s.issueMember(w, request.MemberEmail, request.Role, request.Description, request.Metadata, key.Public())
+	if !ok {
+		return
+	}
+	response.KeyPem = encodePEM("PRIVATE KEY", keyDER)
+	writeJSON(w, http.StatusCreated, response)
+}
+
+func (s *Server) issueMemberCertFromCSR(w http.ResponseWriter, r *http.Request) {
+	var request sdk.IssueMemberCertFromCsrRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	block, _ := pem.Decode([]byte(request.CsrPem))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		validationError(w, "csr_pem must be a PEM-encoded certificate request.")
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		validationError(w, "Invalid certificate request: "+err.Error())
+		return
+	}
+	if err := csr.CheckSignature(); err != nil {
+		validationError(w, "Invalid certificate request signature.")
+		return
+	}
+	if csr.Subject.CommonName != request.MemberEmail {
+		validationError(w, "Certificate request subject does not match member_email.")
+		return
+	}
+	response, ok := s.issueMember(w, request.MemberEmail, request.Role, request.Description, request.Metadata, csr.PublicKey)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// issueMember issues a member certificate for key signed by the org CA,
+// writing the error response when it cannot.
+func (s *Server) issueMember(w http.ResponseWriter, email, role string, description *string, metadata map[string]string, key crypto.PublicKey) (*sdk.MemberCertResponse, bool) {
+	if email == "" || role == "" {
+		writeError(w, http.StatusBadRequest, "", "member_email and role are required.")
+		return nil, false
+	}
+	ca := s.certificateAuthority()
+	if ca.org == nil {
+		validationError(w, "Organization CA not initialized. Initialize CA first.")
+		return nil, false
+	}
+
+	now := s.now()
+	c, err := s.issue(ca.org, &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:         email,
+			Organization:       ca.org.cert.Subject.Organization,
+			OrganizationalUnit: []string{role},
+		},
+		EmailAddresses: []string{email},
+		NotBefore:      now.Add(-time.Hour),
+		NotAfter:       now.Add(memberCertValidity),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, key, certTypeMember, email, description, metadata)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "", err.Error())
+		return nil, false
+	}
+
+	s.audit("cert_member_issued", "Member certificate issued for: "+email, map[string]interface{}{
+		"certificate_id": c.item.Id,
+		"serial_hex":     c.item.SerialHex,
+		"member_email":   email,
+		"role":           role,
+	})
+	return &sdk.MemberCertResponse{
+		Id:           c.item.Id,
+		OrgId:        c.item.OrgId,
+		SerialHex:    c.item.SerialHex,
+		CertType:     c.item.CertType,
+		SubjectCn:    c.item.SubjectCn,
+		SubjectEmail: c.item.SubjectEmail,
+		Status:       c.item.Status,
+		Metadata:     c.item.Metadata,
+		CertPem:      encodePEM("CERTIFICATE", c.cert.Raw),
+		CreatedAt:    c.item.CreatedAt,
+	}, true
+}
+
+// sortedCertificates returns the certificates newest first, as listed by the
+// API.
+func (s *Server) sortedCertificates() []*certificate {
+	certs := make([]*certificate, 0, len(s.certificateAuthority().certs))
+	for _, c := range s.certificateAuthority().certs {
+		certs = append(certs, c)
+	}
+	sort.Slice(certs, func(i, j int) bool {
+		return certs[i].seq > certs[j].seq
+	})
+	return certs
+}
+
+func (s *Server) listCertificates(w http.ResponseWriter, r *http.Request) {
+	response := sdk.CertificateListResponse{}
+	for _, c := range s.sortedCertificates() {
+		response = append(response, c.item)
+	}
+	s.audit("certs_viewed", "Certificates list viewed", map[string]interface{}{"count": len(response)})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getCertificate(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, c := range s.certificateAuthority().certs {
+		if c.item.Id != id {
+			continue
+		}
+		s.audit("cert_viewed", "Certificate viewed: "+c.item.SerialHex, map[string]interface{}{
+			"certificate_id": id,
+			"serial_hex":     c.item.SerialHex,
+		})
+		// The SDK decodes this endpoint as a list
+		writeJSON(w, http.StatusOK, sdk.CertificateListResponse{c.item})
+		return
+	}
+	writeError(w, http.StatusNotFound, "", "Certificate not found")
+}
+
+func (s *Server) withCertificate(handler func(http.ResponseWriter, *http.Request, *certificate)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, ok := s.certificateAuthority().certs[r.PathValue("serial")]
+		if !ok {
+			notFound(w, "Certificate", r.PathValue("serial"))
+			return
+		}
+		handler(w, r, c)
+	}
+}
+
+func (s *Server) revokeCertificate(w http.ResponseWriter, r *http.Request, c *certificate) {
+	var request sdk.RevokeCertRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	now := s.timestamp()
+	c.item.Status = certStatusRevoked
+	c.item.RevokedAt = &now
+	c.item.UpdatedAt = now
+	c.reason = request.Reason
+
+	s.audit("cert_revoked", "Certificate revoked: "+c.item.SerialHex, map[string]interface{}{
+		"serial_hex": c.item.SerialHex,
+		"reason":     request.Reason,
+	})
+	writeJSON(w, http.StatusOK, &sdk.RevokeCertResponse{
+		Message:   "Certificate revoked successfully.",
+		SerialHex: c.item.SerialHex,
+		Status:    certStatusRevoked,
+	})
+}
+
+// getCRL returns a CRL signed by the organization CA listing every revoked
+// certificate. Delta CRLs are not supported, so delta_only is ignored.
+func (s *Server) getCRL(w http.ResponseWriter, r *http.Request) {
+	ca := s.certificateAuthority()
+	if ca.org == nil {
+		validationError(w, "Organization CA not initialized. Initialize CA first.")
+		return
+	}
+
+	var revoked []x509.RevocationListEntry
+	for _, c := range s.sortedCertificates() {
+		if c.item.Status != certStatusRevoked || c.cert.Issuer.String() != ca.org.cert.Subject.String() {
+			continue
+		}
+		revokedAt, _ := time.Parse(timeLayout, *c.item.RevokedAt)
+		revoked = append(revoked, x509.RevocationListEntry{
+			SerialNumber:   c.cert.SerialNumber,
+			RevocationTime: revokedAt,
+			ReasonCode:     c.reason,
+		})
+	}
+
+	ca.crlNumber++
+	now := s.now()
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(ca.crlNumber),
+		ThisUpdate:                now,
+		NextUpdate:                now.Add(24 * time.Hour),
+		RevokedCertificateEntries: revoked,
+	}, ca.org.cert, ca.org.key)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, &sdk.CrlResponse{
+		CrlPem:    encodePEM("X509 CRL", der),
+		CrlNumber: float64(ca.crlNumber),
+	})
+}
+
+func (s *Server) checkOCSP(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.certificateAuthority().certs[r.PathValue("serial")]
+	if !ok {
+		writeJSON(w, http.StatusOK, &sdk.OcspResponse{Status: "unknown"})
+		return
+	}
+	if c.item.Status == certStatusRevoked {
+		writeJSON(w, http.StatusOK, &sdk.OcspResponse{Status: "revoked", RevokedAt: c.item.RevokedAt})
+		return
+	}
+	writeJSON(w, http.StatusOK, &sdk.OcspResponse{Status: "good"})
+}
@@ -0,0 +1,448 @@
+package envsynctest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/google/uuid"
+)
+
+type gpgKey struct {
+	detail *sdk.GpgKeyDetailResponse
+	// entity holds the OpenPGP key; keys imported without a private key
+	// cannot sign
+	entity *openpgp.Entity
+	seq    int
+}
+
+func (k *gpgKey) summary() *sdk.GpgKeyResponse {
+	d := k.detail
+	return &sdk.GpgKeyResponse{
+		Id:          d.Id,
+		OrgId:       d.OrgId,
+		UserId:      d.UserId,
+		Name:        d.Name,
+		Email:       d.Email,
+		Fingerprint: d.Fingerprint,
+		KeyId:       d.KeyId,
+		Algorithm:   d.Algorithm,
+		KeySize:     d.KeySize,
+		UsageFlags:  d.UsageFlags,
+		TrustLevel:  d.TrustLevel,
+		ExpiresAt:   d.ExpiresAt,
+		RevokedAt:   d.RevokedAt,
+		IsDefault:   d.IsDefault,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+}
+
+func (s *Server) registerGpgKeys(mux *http.ServeMux) {
+	mux.HandleFunc("PUT /api/gpg_key/generate", s.generateGpgKey)
+	mux.HandleFunc("PUT /api/gpg_key/import", s.importGpgKey)
+	mux.HandleFunc("POST /api/gpg_key/sign", s.signData)
+	mux.HandleFunc("POST /api/gpg_key/verify", s.verifySignature)
+	mux.HandleFunc("GET /api/gpg_key", s.listGpgKeys)
+	mux.HandleFunc("GET /api/gpg_key/{id}", s.withGpgKey(s.getGpgKey))
+	mux.HandleFunc("DELETE /api/gpg_key/{id}", s.withGpgKey(s.deleteGpgKey))
+	mux.HandleFunc("GET /api/gpg_key/{id}/export", s.withGpgKey(s.exportGpgKey))
+	mux.HandleFunc("POST /api/gpg_key/{id}/revoke", s.withGpgKey(s.revokeGpgKey))
+	mux.HandleFunc("PATCH /api/gpg_key/{id}/trust", s.withGpgKey(s.updateGpgKeyTrust))
+}
+
+func (s *Server) withGpgKey(handler func(http.ResponseWriter, *http.Request, *gpgKey)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, ok := s.gpgKeys[r.PathValue("id")]
+		if !ok {
+			notFound(w, "GPG key", r.PathValue("id"))
+			return
+		}
+		handler(w, r, key)
+	}
+}
+
+// addGpgKey stores an OpenPGP key.
+func (s *Server) addGpgKey(name, email, algorithm string, keySize *float64, usage []string, trust string, entity *openpgp.Entity) (*gpgKey, error) {
+	var armored bytes.Buffer
+	w, err := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := entity.Serialize(w); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	fingerprint := strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint))
+	now := s.timestamp()
+	key := &gpgKey{
+		detail: &sdk.GpgKeyDetailResponse{
+			Id:          uuid.NewString(),
+			OrgId:       OrgID,
+			UserId:      UserID,
+			Name:        name,
+			Email:       email,
+			Fingerprint: fingerprint,
+			KeyId:       fmt.Sprintf("%016X", entity.PrimaryKey.KeyId),
+			Algorithm:   algorithm,
+			KeySize:     keySize,
+			UsageFlags:  usage,
+			TrustLevel:  trust,
+			PublicKey:   armored.String() + "\n",
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		entity: entity,
+		seq:    s.nextSeq(),
+	}
+	if sig, _ := entity.PrimarySelfSignature(); sig != nil && sig.KeyLifetimeSecs != nil && *sig.KeyLifetimeSecs > 0 {
+		lifetime := sig.KeyLifetimeSecs
+		expires := formatTime(entity.PrimaryKey.CreationTime.Add(time.Duration(*lifetime) * time.Second))
+		key.detail.ExpiresAt = &expires
+	}
+	s.gpgKeys[key.detail.Id] = key
+	return key, nil
+}
+
+// pgpConfig returns the OpenPGP configuration of the server, using its clock.
+func (s *Server) pgpConfig() *packet.Config {
+	return &packet.Config{Time: s.now}
+}
+
+func (s *Server) generateGpgKey(w http.ResponseWriter, r *http.Request) {
+	var request sdk.GenerateGpgKeyRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if request.Name == "" || request.Email == "" || request.Algorithm == "" {
+		writeError(w, http.StatusBadRequest, "", "name, email, and algorithm are required.")
+		return
+	}
+
+	config := s.pgpConfig()
+	switch request.Algorithm {
+	case sdk.GenerateGpgKeyRequestAlgorithmRsa:
+		config.Algorithm = packet.PubKeyAlgoRSA
+		config.RSABits = 2048
+		if request.KeySize != nil {
+			config.RSABits = *request.KeySize
+		}
+	case sdk.GenerateGpgKeyRequestAlgorithmEccP256:
+		config.Algorithm, config.Curve = packet.PubKeyAlgoECDSA, packet.CurveNistP256
+	case sdk.GenerateGpgKeyRequestAlgorithmEccP384:
+		config.Algorithm, config.Curve = packet.PubKeyAlgoECDSA, packet.CurveNistP384
+	default:
+		config.Algorithm, config.Curve = packet.PubKeyAlgoEdDSA, packet.Curve25519
+	}
+	if request.ExpiresInDays != nil {
+		config.KeyLifetimeSecs = uint32(*request.ExpiresInDays * 24 * 60 * 60)
+	}
+	entity, err := openpgp.NewEntity(request.Name, "", request.Email, config)
+	if err != nil {
+		validationError(w, err.Error())
+		return
+	}
+
+	var keySize *float64
+	if request.KeySize != nil {
+		size := float64(*request.KeySize)
+		keySize = &size
+	}
+	usage := []string{"sign", "certify"}
+	if len(request.UsageFlags) > 0 {
+		usage = make([]string, len(request.UsageFlags))
+		for i, flag := range request.UsageFlags {
+			usage[i] = string(flag)
+		}
+	}
+
+	key, err := s.addGpgKey(request.Name, request.Email, string(request.Algorithm), keySize, usage, "ultimate", entity)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	if request.IsDefault != nil && *request.IsDefault {
+		for _, other := range s.gpgKeys {
+			other.detail.IsDefault = false
+		}
+		key.detail.IsDefault = true
+	}
+	s.audit("gpg_key_generated", "GPG key "+key.detail.Fingerprint+" generated.", map[string]interface{}{"gpg_key_id": key.detail.Id})
+	writeJSON(w, http.StatusCreated, key.summary())
+}
+
+func (s *Server) importGpgKey(w http.ResponseWriter, r *http.Request) {
+	var request sdk.ImportGpgKeyRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	public, err := openpgp.ReadArmoredKeyRing(strings.NewReader(request.ArmoredPublicKey))
+	if request.Name == "" || err != nil || len(public) != 1 {
+		validationError(w, "name and a valid armored_public_key are required.")
+		return
+	}
+
+	entity := public[0]
+	if request.ArmoredPrivateKey != nil && *request.ArmoredPrivateKey != "" {
+		private, err := openpgp.ReadArmoredKeyRing(strings.NewReader(*request.ArmoredPrivateKey))
+		if err != nil || len(private) != 1 || private[0].PrivateKey == nil ||
+			!bytes.Equal(private[0].PrimaryKey.Fingerprint, entity.PrimaryKey.Fingerprint) {
+			validationError(w, "armored_private_key must be the private key of armored_public_key.")
+			return
+		}
+		if private[0].PrivateKey.Encrypted {
+			passphrase := ""
+			if request.Passphrase != nil {
+				passphrase = *request.Passphrase
+			}
+			if err := private[0].DecryptPrivateKeys([]byte(passphrase)); err != nil {
+				validationError(w, "Invalid passphrase for armored_private_key.")
+				return
+			}
+		}
+		entity = private[0]
+	}
+
+	email := ""
+	if identity := entity.PrimaryIdentity(); identity != nil {
+		email = identity.UserId.Email
+	}
+	key, err := s.addGpgKey(request.Name, email, algorithmName(entity.PrimaryKey), nil, []string{"sign", "certify"}, "unknown", entity)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	// Keep the imported armor rather than re-encoding it
+	key.detail.PublicKey = request.ArmoredPublicKey
+	s.audit("gpg_key_imported", "GPG key "+key.detail.Fingerprint+" imported.", map[string]interface{}{"gpg_key_id": key.detail.Id})
+	writeJSON(w, http.StatusCreated, key.summary())
+}
+
+// algorithmName returns the algorithm of an imported key as named by the
+// API.
+func algorithmName(key *packet.PublicKey) string {
+	switch key.PubKeyAlgo {
+	case packet.PubKeyAlgoRSA, packet.PubKeyAlgoRSASignOnly:
+		return string(sdk.GenerateGpgKeyRequestAlgorithmRsa)
+	case packet.PubKeyAlgoEdDSA, packet.PubKeyAlgoEd25519:
+		return string(sdk.GenerateGpgKeyRequestAlgorithmEccCurve25519)
+	case packet.PubKeyAlgoECDSA:
+		curve, _ := key.Curve()
+		switch curve {
+		case packet.CurveNistP256:
+			return string(sdk.GenerateGpgKeyRequestAlgorithmEccP256)
+		case packet.CurveNistP384:
+			return string(sdk.GenerateGpgKeyRequestAlgorithmEccP384)
+		}
+	}
+	return "imported"
+}
+
+func (s *Server) listGpgKeys(w http.ResponseWriter, r *http.Request) {
+	keys := make([]*gpgKey, 0, len(s.gpgKeys))
+	for _, key := range s.gpgKeys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].seq < keys[j].seq
+	})
+
+	response := sdk.GpgKeysResponse{}
+	for _, key := range keys {
+		response = append(response, key.summary())
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getGpgKey(w http.ResponseWriter, r *http.Request, key *gpgKey) {
+	writeJSON(w, http.StatusOK, key.detail)
+}
+
+func (s *Server) deleteGpgKey(w http.ResponseWriter, r *http.Request, key *gpgKey) {
+	delete(s.gpgKeys, key.detail.Id)
+	s.audit("gpg_key_deleted", "GPG key "+key.detail.Fingerprint+" deleted.", map[string]interface{}{"gpg_key_id": key.detail.Id})
+	writeJSON(w, http.StatusOK, key.summary())
+}
+
+func (s *Server) exportGpgKey(w http.ResponseWriter, r *http.Request, key *gpgKey) {
+	s.audit("gpg_key_exported", "GPG key "+key.detail.Fingerprint+" exported.", map[string]interface{}{"gpg_key_id": key.detail.Id})
+	writeJSON(w, http.StatusOK, &sdk.ExportKeyResponse{
+		PublicKey:   key.detail.PublicKey,
+		Fingerprint: key.detail.Fingerprint,
+	})
+}
+
+func (s *Server) revokeGpgKey(w http.ResponseWriter, r *http.Request, key *gpgKey) {
+	var request sdk.RevokeGpgKeyRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if key.detail.RevokedAt != nil {
+		conflict(w, "GPG key is already revoked")
+		return
+	}
+
+	now := s.timestamp()
+	key.detail.RevokedAt = &now
+	key.detail.RevocationReason = request.Reason
+	key.detail.UpdatedAt = now
+	s.audit("gpg_key_revoked", "GPG key "+key.detail.Fingerprint+" revoked.", map[string]interface{}{"gpg_key_id": key.detail.Id})
+	writeJSON(w, http.StatusOK, key.detail)
+}
+
+func (s *Server) updateGpgKeyTrust(w http.ResponseWriter, r *http.Request, key *gpgKey) {
+	var request sdk.UpdateTrustLevelRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if request.TrustLevel == "" {
+		writeError(w, http.StatusBadRequest, "", "trust_level is required.")
+		return
+	}
+
+	key.detail.TrustLevel = string(request.TrustLevel)
+	key.detail.UpdatedAt = s.timestamp()
+	s.audit("gpg_key_trust_updated", "GPG key "+key.detail.Fingerprint+" trust updated.", map[string]interface{}{"gpg_key_id": key.detail.Id})
+	writeJSON(w, http.StatusOK, key.detail)
+}
+
+func (s *Server) signData(w http.ResponseWriter, r *http.Request) {
+	var request sdk.SignDataRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	key, ok := s.gpgKeys[request.GpgKeyId]
+	if !ok {
+		notFound(w, "GPG key", request.GpgKeyId)
+		return
+	}
+	if key.detail.RevokedAt != nil {
+		validationError(w, "GPG key is revoked")
+		return
+	}
+	if key.entity.PrivateKey == nil {
+		validationError(w, "GPG key has no private key")
+		return
+	}
+	data, err := base64.StdEncoding.DecodeString(request.Data)
+	if err != nil {
+		validationError(w, "data must be base64 encoded.")
+		return
+	}
+
+	signature, err := s.sign(key.entity, data, request.Mode, request.Detached == nil || *request.Detached)
+	if err != nil {
+		validationError(w, err.Error())
+		return
+	}
+
+	s.audit("gpg_data_signed", "Data signed with GPG key "+key.detail.Fingerprint+".", map[string]interface{}{"gpg_key_id": key.detail.Id})
+	writeJSON(w, http.StatusOK, &sdk.SignatureResponse{
+		Signature:   signature,
+		KeyId:       key.detail.KeyId,
+		Fingerprint: key.detail.Fingerprint,
+	})
+}
+
+// sign returns the armored signature of data in the given mode.
+func (s *Server) sign(entity *openpgp.Entity, data []byte, mode *sdk.SignDataRequestMode, detached bool) (string, error) {
+	config := s.pgpConfig()
+	var signature bytes.Buffer
+
+	switch {
+	case mode != nil && *mode == sdk.SignDataRequestModeClearsign:
+		w, err := clearsign.Encode(&signature, entity.PrivateKey, config)
+		if err != nil {
+			return "", err
+		}
+		if _, err := w.Write(data); err != nil {
+			return "", err
+		}
+		if err := w.Close(); err != nil {
+			return "", err
+		}
+	case !detached:
+		armored, err := armor.Encode(&signature, "PGP MESSAGE", nil)
+		if err != nil {
+			return "", err
+		}
+		w, err := openpgp.Sign(armored, entity, nil, config)
+		if err != nil {
+			return "", err
+		}
+		if _, err := w.Write(data); err != nil {
+			return "", err
+		}
+		if err := w.Close(); err != nil {
+			return "", err
+		}
+		if err := armored.Close(); err != nil {
+			return "", err
+		}
+	case mode != nil && *mode == sdk.SignDataRequestModeText:
+		if err := openpgp.ArmoredDetachSignText(&signature, entity, bytes.NewReader(data), config); err != nil {
+			return "", err
+		}
+	default:
+		if err := openpgp.ArmoredDetachSign(&signature, entity, bytes.NewReader(data), config); err != nil {
+			return "", err
+		}
+	}
+	return signature.String() + "\n", nil
+}
+
+func (s *Server) verifySignature(w http.ResponseWriter, r *http.Request) {
+	var request sdk.VerifySignatureRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	data, err := base64.StdEncoding.DecodeString(request.Data)
+	if err != nil {
+		validationError(w, "data must be base64 encoded.")
+		return
+	}
+
+	var keyring openpgp.EntityList
+	for _, key := range s.gpgKeys {
+		if request.GpgKeyId == nil || *request.GpgKeyId == key.detail.Id {
+			keyring = append(keyring, key.entity)
+		}
+	}
+
+	var signer *openpgp.Entity
+	if block, _ := clearsign.Decode([]byte(request.Signature)); block != nil {
+		if bytes.Equal(bytes.TrimRight(block.Plaintext, "\r\n"), bytes.TrimRight(data, "\r\n")) {
+			signer, _ = block.VerifySignature(keyring, s.pgpConfig())
+		}
+	} else {
+		signer, _ = openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(data), strings.NewReader(request.Signature), s.pgpConfig())
+	}
+
+	response := &sdk.VerifyResponse{}
+	if signer != nil {
+		fingerprint := strings.ToUpper(hex.EncodeToString(signer.PrimaryKey.Fingerprint))
+		for _, key := range s.gpgKeys {
+			if key.detail.Fingerprint == fingerprint {
+				response.Valid = true
+				response.SignerFingerprint = &key.detail.Fingerprint
+				response.SignerKeyId = &key.detail.KeyId
+			}
+		}
+	}
+
+	s.audit("gpg_signature_verified", "GPG signature verification.", map[string]interface{}{"valid": response.Valid})
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,398 @@
+package envsynctest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const (
+	defaultHistoryPerPage = 20
+	defaultTimelineLimit  = 50
+)
+
+// historyRequest covers the bodies of the point-in-time and rollback
+// endpoints.
+type historyRequest struct {
+	AppId           string    `json:"app_id"`
+	EnvTypeId       string    `json:"env_type_id"`
+	Page            *int      `json:"page"`
+	PerPage         *int      `json:"per_page"`
+	PitId           string    `json:"pit_id"`
+	FromPitId       string    `json:"from_pit_id"`
+	ToPitId         string    `json:"to_pit_id"`
+	Timestamp       time.Time `json:"timestamp"`
+	Limit           *int      `json:"limit"`
+	RollbackMessage *string   `json:"rollback_message"`
+}
+
+type pitResponse struct {
+	Id                   string `json:"id"`
+	OrgId                string `json:"org_id"`
+	AppId                string `json:"app_id"`
+	EnvTypeId            string `json:"env_type_id"`
+	ChangeRequestMessage string `json:"change_request_message"`
+	UserId               string `json:"user_id"`
+	CreatedAt            string `json:"created_at"`
+	UpdatedAt            string `json:"updated_at"`
+}
+
+type historyResponse struct {
+	Pits       []*pitResponse `json:"pits"`
+	TotalPages int            `json:"totalPages"`
+}
+
+type stateItemResponse struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	LastUpdated string `json:"last_updated"`
+	Operation   string `json:"operation"`
+}
+
+type diffItemResponse struct {
+	Key      string `json:"key"`
+	Value    string `json:"value,omitempty"`
+	OldValue string `json:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty"`
+}
+
+type diffResponse struct {
+	Added    []*diffItemResponse `json:"added"`
+	Modified []*diffItemResponse `json:"modified"`
+	Deleted  []*diffItemResponse `json:"deleted"`
+}
+
+type timelineItemResponse struct {
+	PitId                string `json:"pit_id"`
+	ChangeRequestMessage string `json:"change_request_message"`
+	UserId               string `json:"user_id"`
+	CreatedAt            string `json:"created_at"`
+	Value                string `json:"value"`
+	Operation            string `json:"operation"`
+}
+
+type operationResponse struct {
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	Operation string `json:"operation"`
+}
+
+type rollbackResponse struct {
+	Message             string               `json:"message"`
+	OperationsPerformed int                  `json:"operations_performed"`
+	Operations          []*operationResponse `json:"operations"`
+}
+
+type variableRollbackResponse struct {
+	Message         string  `json:"message"`
+	Key             string  `json:"key"`
+	Operation       string  `json:"operation"`
+	PreviousValue   *string `json:"previous_value,omitempty"`
+	TargetValue     *string `json:"target_value,omitempty"`
+	PitId           *string `json:"pit_id,omitempty"`
+	TargetTimestamp *string `json:"target_timestamp,omitempty"`
+}
+
+// historyHandler handles a request for the history of a scope.
+type historyHandler func(w http.ResponseWriter, r *http.Request, k kind, sc scope, st *store, request *historyRequest)
+
+func (s *Server) registerHistory(mux *http.ServeMux, k kind) {
+	base := "/api/" + k.prefix
+	mux.HandleFunc("POST "+base+"/history", s.withHistory(k, s.getHistory))
+	mux.HandleFunc("POST "+base+"/pit", s.withHistory(k, s.getStateAtPit))
+	mux.HandleFunc("POST "+base+"/timestamp", s.withHistory(k, s.getStateAtTimestamp))
+	mux.HandleFunc("POST "+base+"/diff", s.withHistory(k, s.getDiff))
+	mux.HandleFunc("POST "+base+"/timeline/{key}", s.withHistory(k, s.getTimeline))
+	mux.HandleFunc("POST "+base+"/rollback/pit", s.withHistory(k, s.rollbackToPit))
+	mux.HandleFunc("POST "+base+"/rollback/timestamp", s.withHistory(k, s.rollbackToTimestamp))
+	mux.HandleFunc("POST "+base+"/rollback/variable/{key}/pit", s.withHistory(k, s.rollbackVariableToPit))
+	mux.HandleFunc("POST "+base+"/rollback/variable/{key}/timestamp", s.withHistory(k, s.rollbackVariableToTimestamp))
+}
+
+func (s *Server) withHistory(k kind, handler historyHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request historyRequest
+		if !decode(w, r, &request) {
+			return
+		}
+		sc := scope{appID: request.AppId, envTypeID: request.EnvTypeId}
+		if !s.checkScope(w, sc) {
+			return
+		}
+		handler(w, r, k, sc, s.store(k, sc), &request)
+	}
+}
+
+// pitIndex returns the index of the point in time with the given ID, or -1.
+func (st *store) pitIndex(id string) int {
+	for i, p := range st.history {
+		if p.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// timestampIndex returns the index of the last point in time at or before t,
+// or -1.
+func (st *store) timestampIndex(t time.Time) int {
+	index := -1
+	for i, p := range st.history {
+		if p.createdAt.After(t) {
+			break
+		}
+		index = i
+	}
+	return index
+}
+
+// state replays the history up to and including the point in time at index.
+func (st *store) state(index int) map[string]*stateItemResponse {
+	state := make(map[string]*stateItemResponse)
+	for _, p := range st.history[:index+1] {
+		for _, c := range p.changes {
+			if c.operation == operationDelete {
+				delete(state, c.key)
+				continue
+			}
+			state[c.key] = &stateItemResponse{
+				Key:         c.key,
+				Value:       c.value,
+				LastUpdated: formatTime(p.createdAt),
+				Operation:   c.operation,
+			}
+		}
+	}
+	return state
+}
+
+func stateValues(state map[string]*stateItemResponse) map[string]string {
+	values := make(map[string]string, len(state))
+	for key, item := range state {
+		values[key] = item.Value
+	}
+	return values
+}
+
+func (s *Server) newPitResponse(sc scope, p *pit) *pitResponse {
+	return &pitResponse{
+		Id:                   p.id,
+		OrgId:                OrgID,
+		AppId:                sc.appID,
+		EnvTypeId:            sc.envTypeID,
+		ChangeRequestMessage: p.message,
+		UserId:               UserID,
+		CreatedAt:            formatTime(p.createdAt),
+		UpdatedAt:            formatTime(p.createdAt),
+	}
+}
+
+func (s *Server) getHistory(w http.ResponseWriter, r *http.Request, k kind, sc scope, st *store, request *historyRequest) {
+	page, perPage := 1, defaultHistoryPerPage
+	if request.Page != nil && *request.Page > 0 {
+		page = *request.Page
+	}
+	if request.PerPage != nil && *request.PerPage > 0 {
+		perPage = *request.PerPage
+	}
+
+	// Newest first, like the API
+	response := &historyResponse{
+		Pits:       []*pitResponse{},
+		TotalPages: (len(st.history) + perPage - 1) / perPage,
+	}
+	for i := len(st.history) - 1 - (page-1)*perPage; i >= 0 && len(response.Pits) < perPage; i-- {
+		response.Pits = append(response.Pits, s.newPitResponse(sc, st.history[i]))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func writeState(w http.ResponseWriter, state map[string]*stateItemResponse) {
+	response := make([]*stateItemResponse, 0, len(state))
+	for _, item := range state {
+		response = append(response, item)
+	}
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].Key < response[j].Key
+	})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getStateAtPit(w http.ResponseWriter, r *http.Request, k kind, sc scope, st *store, request *historyRequest) {
+	index := st.pitIndex(request.PitId)
+	if index < 0 {
+		notFound(w, "Point in time", request.PitId)
+		return
+	}
+	writeState(w, st.state(index))
+}
+
+func (s *Server) getStateAtTimestamp(w http.ResponseWriter, r *http.Request, k kind, sc scope, st *store, request *historyRequest) {
+	writeState(w, st.state(st.timestampIndex(request.Timestamp)))
+}
+
+func (s *Server) getDiff(w http.ResponseWriter, r *http.Request, k kind, sc scope, st *store, request *historyRequest) {
+	from, to := st.pitIndex(request.FromPitId), st.pitIndex(request.ToPitId)
+	if from < 0 {
+		notFound(w, "Point in time", request.FromPitId)
+		return
+	}
+	if to < 0 {
+		notFound(w, "Point in time", request.ToPitId)
+		return
+	}
+
+	fromValues := stateValues(st.state(from))
+	response := &diffResponse{
+		Added:    []*diffItemResponse{},
+		Modified: []*diffItemResponse{},
+		Deleted:  []*diffItemResponse{},
+	}
+	for _, c := range diffStates(fromValues, stateValues(st.state(to))) {
+		switch c.operation {
+		case operationCreate:
+			response.Added = append(response.Added, &diffItemResponse{Key: c.key, Value: c.value})
+		case operationUpdate:
+			response.Modified = append(response.Modified, &diffItemResponse{Key: c.key, OldValue: fromValues[c.key], NewValue: c.value})
+		case operationDelete:
+			response.Deleted = append(response.Deleted, &diffItemResponse{Key: c.key, Value: c.value})
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getTimeline(w http.ResponseWriter, r *http.Request, k kind, sc scope, st *store, request *historyRequest) {
+	key := r.PathValue("key")
+	limit := defaultTimelineLimit
+	if request.Limit != nil && *request.Limit > 0 {
+		limit = *request.Limit
+	}
+
+	response := []*timelineItemResponse{}
+	for i := len(st.history) - 1; i >= 0 && len(response) < limit; i-- {
+		p := st.history[i]
+		for _, c := range p.changes {
+			if c.key != key {
+				continue
+			}
+			response = append(response, &timelineItemResponse{
+				PitId:                p.id,
+				ChangeRequestMessage: p.message,
+				UserId:               UserID,
+				CreatedAt:            formatTime(p.createdAt),
+				Value:                c.value,
+				Operation:            c.operation,
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// rollback restores the state at the point in time at index.
+func (s *Server) rollback(w http.ResponseWriter, k kind, sc scope, st *store, index int, message string) {
+	target := map[string]string{}
+	if index >= 0 {
+		target = stateValues(st.state(index))
+	}
+	changes := diffStates(st.values(), target)
+	if len(changes) > 0 {
+		s.apply(st, message, changes)
+	}
+
+	response := &rollbackResponse{
+		Message:             message,
+		OperationsPerformed: len(changes),
+		Operations:          []*operationResponse{},
+	}
+	for _, c := range changes {
+		response.Operations = append(response.Operations, &operationResponse{Key: c.key, Value: c.value, Operation: c.operation})
+	}
+	s.auditEntries(k.prefix+"s_rolled_back", sc, changedKeys(changes))
+	writeJSON(w, http.StatusOK, response)
+}
+
+func rollbackMessage(request *historyRequest, fallback string) string {
+	if request.RollbackMessage != nil && *request.RollbackMessage != "" {
+		return *request.RollbackMessage
+	}
+	return fallback
+}
+
+func (s *Server) rollbackToPit(w http.ResponseWriter, r *http.Request, k kind, sc scope, st *store, request *historyRequest) {
+	index := st.pitIndex(request.PitId)
+	if index < 0 {
+		notFound(w, "Point in time", request.PitId)
+		return
+	}
+	s.rollback(w, k, sc, st, index, rollbackMessage(request, "Rollback to point in time "+request.PitId))
+}
+
+func (s *Server) rollbackToTimestamp(w http.ResponseWriter, r *http.Request, k kind, sc scope, st *store, request *historyRequest) {
+	index := st.timestampIndex(request.Timestamp)
+	s.rollback(w, k, sc, st, index, rollbackMessage(request, "Rollback to "+formatTime(request.Timestamp)))
+}
+
+// rollbackVariable restores a single key to its value at the point in time
+// at index.
+func (s *Server) rollbackVariable(w http.ResponseWriter, r *http.Request, k kind, sc scope, st *store, index int, message string) *variableRollbackResponse {
+	key := r.PathValue("key")
+	var target *string
+	if index >= 0 {
+		if item, ok := st.state(index)[key]; ok {
+			target = &item.Value
+		}
+	}
+	var previous *string
+	if e, ok := st.entries[key]; ok {
+		value := e.value
+		previous = &value
+	}
+
+	var c change
+	switch {
+	case previous == nil && target == nil:
+		notFound(w, "Environment variable", key)
+		return nil
+	case target == nil:
+		c = change{key: key, value: *previous, operation: operationDelete}
+	case previous == nil:
+		c = change{key: key, value: *target, operation: operationCreate}
+	default:
+		c = change{key: key, value: *target, operation: operationUpdate}
+	}
+	if previous == nil || target == nil || *previous != *target {
+		s.apply(st, message, []change{c})
+	}
+	s.auditEntries(k.prefix+"_rolled_back", sc, []string{key})
+
+	return &variableRollbackResponse{
+		Message:       message,
+		Key:           key,
+		Operation:     c.operation,
+		PreviousValue: previous,
+		TargetValue:   target,
+	}
+}
+
+func (s *Server) rollbackVariableToPit(w http.ResponseWriter, r *http.Request, k kind, sc scope, st *store, request *historyRequest) {
+	index := st.pitIndex(request.PitId)
+	if index < 0 {
+		notFound(w, "Point in time", request.PitId)
+		return
+	}
+	message := rollbackMessage(request, fmt.Sprintf("Rollback %s to point in time %s", r.PathValue("key"), request.PitId))
+	if response := s.rollbackVariable(w, r, k, sc, st, index, message); response != nil {
+		response.PitId = &request.PitId
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func (s *Server) rollbackVariableToTimestamp(w http.ResponseWriter, r *http.Request, k kind, sc scope, st *store, request *historyRequest) {
+	timestamp := formatTime(request.Timestamp)
+	message := rollbackMessage(request, fmt.Sprintf("Rollback %s to %s", r.PathValue("key"), timestamp))
+	if response := s.rollbackVariable(w, r, k, sc, st, st.timestampIndex(request.Timestamp), message); response != nil {
+		response.TargetTimestamp = &timestamp
+		writeJSON(w, http.StatusOK, response)
+	}
+}
@@ -0,0 +1,311 @@
+// Package envsynctest provides an in-memory fake of the EnvSync API for tests.
+//
+// The fake implements the endpoints used by the SDK for applications,
+// environment types, variables, secrets, point-in-time history and rollback,
+// audit logs, webhooks, GPG keys and certificates. State is kept in memory and
+// shared by all clients of a server, requests are recorded for assertions and
+// faults such as latency, server errors and rate limiting can be injected:
+//
+//	server := envsynctest.NewServer()
+//	defer server.Close()
+//
+//	client := server.Client()
+//	app, err := client.Applications.CreateApp(ctx, &api.CreateAppRequest{Name: "api"})
+//
+// The fake is not a reference implementation of the API. GPG keys and
+// signatures are real OpenPGP keys and signatures, so they can be checked
+// locally, but authentication is only enforced by servers started with
+// WithMutualTLS.
+package envsynctest
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	sdkclient "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/client"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+	"github.com/google/uuid"
+)
+
+const (
+	// OrgID is the organization that owns all resources of the fake.
+	OrgID = "org_envsynctest"
+	// UserID is the user that performs all requests against the fake.
+	UserID = "user_envsynctest"
+)
+
+// timeLayout matches the timestamps sent by the API.
+const timeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	// ClientCertificate is the verified client certificate of a mutual TLS
+	// server, if sent.
+	ClientCertificate *x509.Certificate
+}
+
+// Fault describes a failure injected into matching requests.
+type Fault struct {
+	// Method and Path select the affected requests. Empty values match any
+	// method or path.
+	Method string
+	Path   string
+
+	// Latency delays the response.
+	Latency time.Duration
+	// StatusCode, if set, is returned instead of handling the request.
+	StatusCode int
+	// RetryAfter is sent in the Retry-After header with StatusCode.
+	RetryAfter time.Duration
+
+	// Times limits the number of affected requests. Zero affects every
+	// matching request until the faults are cleared.
+	Times int
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithClock sets the function used to timestamp changes, which defaults to
+// time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// Server is an in-memory fake of the EnvSync API backed by an
+// httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	now       func() time.Time
+	mutualTLS bool
+	faults    []*Fault
+	requests  []Request
+	seq       int
+
+	apps         map[string]*app
+	envTypes     map[string]*envType
+	variables    map[scope]*store
+	secrets      map[scope]*store
+	auditLogs    []*auditLog
+	webhooks     map[string]*webhook
+	gpgKeys      map[string]*gpgKey
+	certificates *certificateAuthority
+}
+
+// NewServer starts a fake EnvSync API server. Close it when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		now:       time.Now,
+		apps:      make(map[string]*app),
+		envTypes:  make(map[string]*envType),
+		variables: make(map[scope]*store),
+		secrets:   make(map[scope]*store),
+		webhooks:  make(map[string]*webhook),
+		gpgKeys:   make(map[string]*gpgKey),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	s.registerApps(mux)
+	s.registerVariables(mux)
+	s.registerAuditLogs(mux)
+	s.registerWebhooks(mux)
+	s.registerGpgKeys(mux)
+	s.registerCertificates(mux)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Route not found")
+	})
+
+	s.Server = httptest.NewUnstartedServer(s.middleware(mux))
+	if s.mutualTLS {
+		s.startTLS()
+	} else {
+		s.Server.Start()
+	}
+	return s
+}
+
+// Client returns an SDK client for the server. The given options are applied
+// after the base URL, and the root CAs of a TLS server, so they can override
+// them.
+func (s *Server) Client(opts ...option.RequestOption) *sdkclient.Client {
+	base := []option.RequestOption{option.WithBaseURL(s.URL)}
+	if s.mutualTLS {
+		base = append(base, option.WithRootCAs(s.RootCAs()))
+	}
+	return sdkclient.NewClient(append(base, opts...)...)
+}
+
+// Inject adds a fault for matching requests. Faults are checked in the order
+// they were added and the first match applies.
+func (s *Server) Inject(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ResetRequests forgets the recorded requests.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// middleware records requests, applies faults and serializes access to the
+// state, so handlers can use it without locking.
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", "Failed to read request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header.Clone(),
+			Body:   body,
+
+			ClientCertificate: clientCertificate(r),
+		})
+		fault := s.matchFault(r)
+		s.mu.Unlock()
+
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.StatusCode != 0 {
+			if fault.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int((fault.RetryAfter+time.Second-1)/time.Second)))
+			}
+			writeError(w, fault.StatusCode, "", http.StatusText(fault.StatusCode))
+			return
+		}
+
+		w.Header().Set("X-Request-Id", uuid.NewString())
+		if s.mutualTLS && !authenticated(r) {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// matchFault returns the fault for r and counts it against its limit. It
+// returns the zero Fault if none applies.
+func (s *Server) matchFault(r *http.Request) Fault {
+	for i, fault := range s.faults {
+		if fault.Method != "" && fault.Method != r.Method {
+			continue
+		}
+		if fault.Path != "" && fault.Path != r.URL.Path {
+			continue
+		}
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return *fault
+	}
+	return Fault{}
+}
+
+// nextSeq returns increasing numbers used to order resources by creation.
+func (s *Server) nextSeq() int {
+	s.seq++
+	return s.seq
+}
+
+func (s *Server) timestamp() string {
+	return formatTime(s.now())
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// errorResponse is the error body sent by the API.
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	writeJSON(w, statusCode, errorResponse{Error: message, Code: code})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// decode reads the JSON request body into value and reports a 400 response
+// if it is malformed.
+func decode(w http.ResponseWriter, r *http.Request, value interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(value)
+	if err == nil || errors.Is(err, io.EOF) {
+		return true
+	}
+	writeError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid JSON in request body")
+	return false
+}
+
+func notFound(w http.ResponseWriter, resource, id string) {
+	writeError(w, http.StatusNotFound, "NOT_FOUND", resource+" not found: "+id)
+}
+
+func validationError(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", message)
+}
+
+func conflict(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusConflict, "CONFLICT", message)
+}
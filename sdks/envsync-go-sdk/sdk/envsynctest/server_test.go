@@ -0,0 +1,335 @@
+package envsynctest
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerApps(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	created, err := client.Applications.CreateApp(ctx, &sdk.CreateAppRequest{Name: "api"})
+	require.NoError(t, err)
+
+	_, err = client.EnvironmentTypes.CreateEnvType(ctx, &sdk.CreateEnvTypeRequest{Name: "production", AppId: created.Id})
+	require.NoError(t, err)
+
+	app, err := client.Applications.GetApp(ctx, created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "api", app.Name)
+	require.Len(t, app.EnvTypes, 1)
+	assert.Equal(t, "production", app.EnvTypes[0].Name)
+
+	_, err = client.Applications.GetApp(ctx, "missing")
+	assert.True(t, core.IsNotFound(err))
+}
+
+func TestServerVariables(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+	appID, envTypeIDs := server.AddApp("api", "staging")
+
+	_, err := client.EnvironmentVariables.BatchCreateEnvs(ctx, &sdk.BatchCreateEnvsRequest{
+		AppId:     appID,
+		EnvTypeId: envTypeIDs[0],
+		Envs: []*sdk.BatchCreateEnvsRequestEnvsItem{
+			{Key: "HOST", Value: "localhost"},
+			{Key: "PORT", Value: "8080"},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = client.EnvironmentVariables.CreateEnv(ctx, &sdk.CreateEnvRequest{
+		AppId:     appID,
+		EnvTypeId: envTypeIDs[0],
+		Key:       "HOST",
+		Value:     "example.com",
+	})
+	assert.Error(t, err, "creating an existing key")
+
+	envs, err := client.EnvironmentVariables.GetEnvs(ctx, &sdk.GetEnvRequest{AppId: appID, EnvTypeId: envTypeIDs[0]})
+	require.NoError(t, err)
+	assert.Len(t, envs, 2)
+	assert.Equal(t, map[string]string{"HOST": "localhost", "PORT": "8080"}, server.Variables(appID, envTypeIDs[0]))
+}
+
+func TestServerHistoryAndRollback(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := NewServer(WithClock(func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}))
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+	appID, envTypeIDs := server.AddApp("api", "staging")
+
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "localhost"})
+	server.SetVariables(appID, envTypeIDs[0], map[string]string{"HOST": "example.com", "PORT": "443"})
+
+	history, err := client.EnvironmentVariablesPointInTime.GetEnvHistory(ctx, &sdk.EnvHistoryRequest{AppId: appID, EnvTypeId: envTypeIDs[0]})
+	require.NoError(t, err)
+	require.Len(t, history.Pits, 2)
+
+	// History is newest first, so the first change is last
+	first := history.Pits[1].Id
+	state, err := client.EnvironmentVariablesPointInTime.GetEnvsAtPointInTime(ctx, &sdk.EnvPitRequest{AppId: appID, EnvTypeId: envTypeIDs[0], PitId: first})
+	require.NoError(t, err)
+	require.Len(t, state, 1)
+	assert.Equal(t, "localhost", state[0].Value)
+
+	rollback, err := client.EnvironmentVariablesRollback.RollbackEnvsToPitId(ctx, &sdk.RollbackToPitRequest{AppId: appID, EnvTypeId: envTypeIDs[0], PitId: first})
+	require.NoError(t, err)
+	assert.Equal(t, 2, rollback.OperationsPerformed)
+	assert.Equal(t, map[string]string{"HOST": "localhost"}, server.Variables(appID, envTypeIDs[0]))
+}
+
+func TestServerSecrets(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	appID, envTypeIDs := server.AddApp("api", "production")
+	server.SetSecrets(appID, envTypeIDs[0], map[string]string{"TOKEN": "s3cret", "KEY": "k3y"})
+
+	revealed, err := client.Secrets.RevealSecrets(context.Background(), &sdk.RevealSecretsRequest{
+		AppId:     appID,
+		EnvTypeId: envTypeIDs[0],
+		Keys:      []string{"TOKEN"},
+	})
+	require.NoError(t, err)
+	require.Len(t, revealed, 1)
+	assert.Equal(t, "s3cret", revealed[0].Value)
+}
+
+func TestServerAuditLogs(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	for _, name := range []string{"a", "b", "c"} {
+		_, err := client.Applications.CreateApp(ctx, &sdk.CreateAppRequest{Name: name})
+		require.NoError(t, err)
+	}
+
+	perPage := "2"
+	var actions []string
+	for entry, err := range client.AuditLogs.All(ctx, &sdk.GetAuditLogsRequest{PerPage: &perPage}) {
+		require.NoError(t, err)
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{"app_created", "app_created", "app_created"}, actions)
+	assert.Equal(t, actions, server.AuditActions())
+}
+
+func TestServerGpgKeys(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	key, err := client.GpgKeys.GenerateGpgKey(ctx, &sdk.GenerateGpgKeyRequest{
+		Name:      "Release",
+		Email:     "release@example.com",
+		Algorithm: sdk.GenerateGpgKeyRequestAlgorithmEccCurve25519,
+	})
+	require.NoError(t, err)
+
+	payload := base64.StdEncoding.EncodeToString([]byte("payload"))
+	signature, err := client.GpgKeys.SignDataWithGpgKey(ctx, &sdk.SignDataRequest{GpgKeyId: key.Id, Data: payload})
+	require.NoError(t, err)
+	assert.Equal(t, key.Fingerprint, signature.Fingerprint)
+
+	// Signatures are OpenPGP signatures by the exported key
+	exported, err := client.GpgKeys.ExportGpgPublicKey(ctx, key.Id)
+	require.NoError(t, err)
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(exported.PublicKey))
+	require.NoError(t, err)
+	_, err = openpgp.CheckArmoredDetachedSignature(keyring, strings.NewReader("payload"), strings.NewReader(signature.Signature), nil)
+	require.NoError(t, err)
+
+	verified, err := client.GpgKeys.VerifyGpgSignature(ctx, &sdk.VerifySignatureRequest{Data: payload, Signature: signature.Signature})
+	require.NoError(t, err)
+	assert.True(t, verified.Valid)
+
+	tampered := base64.StdEncoding.EncodeToString([]byte("tampered"))
+	verified, err = client.GpgKeys.VerifyGpgSignature(ctx, &sdk.VerifySignatureRequest{Data: tampered, Signature: signature.Signature})
+	require.NoError(t, err)
+	assert.False(t, verified.Valid)
+
+	clearsign := sdk.SignDataRequestModeClearsign
+	signature, err = client.GpgKeys.SignDataWithGpgKey(ctx, &sdk.SignDataRequest{GpgKeyId: key.Id, Data: payload, Mode: &clearsign})
+	require.NoError(t, err)
+	assert.Contains(t, signature.Signature, "-----BEGIN PGP SIGNED MESSAGE-----")
+	verified, err = client.GpgKeys.VerifyGpgSignature(ctx, &sdk.VerifySignatureRequest{Data: payload, Signature: signature.Signature})
+	require.NoError(t, err)
+	assert.True(t, verified.Valid)
+
+	_, err = client.GpgKeys.RevokeGpgKey(ctx, key.Id, &sdk.RevokeGpgKeyRequest{})
+	require.NoError(t, err)
+	_, err = client.GpgKeys.SignDataWithGpgKey(ctx, &sdk.SignDataRequest{GpgKeyId: key.Id, Data: payload})
+	assert.True(t, core.IsValidation(err))
+}
+
+func TestServerCertificates(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	_, err := client.Certificates.InitOrgCa(ctx, &sdk.InitOrgCaRequest{OrgName: "Acme"})
+	require.NoError(t, err)
+	orgCA, err := client.Certificates.GetOrgCa(ctx)
+	require.NoError(t, err)
+
+	issued, err := client.Certificates.IssueMemberCert(ctx, &sdk.IssueMemberCertRequest{MemberEmail: "dev@example.com", Role: "developer"})
+	require.NoError(t, err)
+
+	block, _ := pem.Decode([]byte(issued.CertPem))
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	intermediates := x509.NewCertPool()
+	intermediates.AppendCertsFromPEM([]byte(*orgCA.CertPem))
+	roots := x509.NewCertPool()
+	roots.AddCert(server.RootCA())
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	require.NoError(t, err)
+
+	_, err = client.Certificates.RevokeCert(ctx, issued.SerialHex, &sdk.RevokeCertRequest{Reason: 1})
+	require.NoError(t, err)
+
+	status, err := client.Certificates.CheckOcsp(ctx, issued.SerialHex)
+	require.NoError(t, err)
+	assert.Equal(t, "revoked", status.Status)
+
+	crl, err := client.Certificates.GetCrl(ctx)
+	require.NoError(t, err)
+	block, _ = pem.Decode([]byte(crl.CrlPem))
+	require.NotNil(t, block)
+	list, err := x509.ParseRevocationList(block.Bytes)
+	require.NoError(t, err)
+	require.Len(t, list.RevokedCertificateEntries, 1)
+	assert.Equal(t, cert.SerialNumber, list.RevokedCertificateEntries[0].SerialNumber)
+}
+
+func TestServerCertificateFromCSR(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	_, err := client.Certificates.InitOrgCa(ctx, &sdk.InitOrgCaRequest{OrgName: "Acme"})
+	require.NoError(t, err)
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:        pkix.Name{CommonName: "dev@example.com"},
+		EmailAddresses: []string{"dev@example.com"},
+	}, key)
+	require.NoError(t, err)
+	csrPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+
+	issued, err := client.Certificates.IssueMemberCertFromCsr(ctx, &sdk.IssueMemberCertFromCsrRequest{
+		CsrPem:      csrPEM,
+		MemberEmail: "dev@example.com",
+		Role:        "developer",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, issued.KeyPem)
+
+	block, _ := pem.Decode([]byte(issued.CertPem))
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, pub, cert.PublicKey)
+	assert.Equal(t, []string{"developer"}, cert.Subject.OrganizationalUnit)
+
+	// The request must be for the member being issued
+	_, err = client.Certificates.IssueMemberCertFromCsr(ctx, &sdk.IssueMemberCertFromCsrRequest{
+		CsrPem:      csrPEM,
+		MemberEmail: "other@example.com",
+		Role:        "developer",
+	})
+	assert.ErrorIs(t, err, core.ErrValidation)
+}
+
+func TestServerFaults(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	ctx := context.Background()
+
+	t.Run("rate limited", func(t *testing.T) {
+		server.Inject(Fault{Path: "/api/app", StatusCode: http.StatusTooManyRequests, RetryAfter: 30 * time.Second, Times: 1})
+
+		_, err := server.Client(option.WithMaxAttempts(1)).Applications.GetApps(ctx)
+		require.True(t, core.IsRateLimited(err))
+		apiErr, ok := core.AsAPIError(err)
+		require.True(t, ok)
+		assert.Equal(t, 30*time.Second, apiErr.RetryAfter)
+	})
+
+	t.Run("retried server error", func(t *testing.T) {
+		server.ResetRequests()
+		server.Inject(Fault{Method: http.MethodGet, Path: "/api/app", StatusCode: http.StatusServiceUnavailable, Times: 2})
+
+		client := server.Client(option.WithRetryPolicy(&option.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     core.ConstantBackoff(time.Millisecond),
+		}))
+		_, err := client.Applications.GetApps(ctx)
+		require.NoError(t, err)
+		assert.Len(t, server.Requests(), 3)
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		server.Inject(Fault{Latency: time.Second})
+		defer server.ClearFaults()
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := server.Client(option.WithMaxAttempts(1)).Applications.GetApps(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestServerRecordsRequests(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	_, err := server.Client(option.WithToken("token")).Applications.GetApps(context.Background())
+	require.NoError(t, err)
+
+	requests := server.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, http.MethodGet, requests[0].Method)
+	assert.Equal(t, "/api/app", requests[0].Path)
+	assert.Equal(t, "Bearer token", requests[0].Header.Get("Authorization"))
+}
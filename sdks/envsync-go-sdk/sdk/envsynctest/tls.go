@@ -0,0 +1,100 @@
+package envsynctest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// WithMutualTLS serves over TLS and accepts client certificates issued by the
+// organization CA of the server. Requests must then authenticate with a
+// client certificate, an API key or a bearer token; certificates that are
+// revoked or not issued by the server fail the TLS handshake.
+//
+// Clients returned by Server.Client trust the server certificate.
+func WithMutualTLS() Option {
+	return func(s *Server) {
+		s.mutualTLS = true
+	}
+}
+
+// startTLS starts the server with client certificate verification.
+func (s *Server) startTLS() {
+	s.Server.TLS = &tls.Config{
+		ClientAuth:            tls.RequestClientCert,
+		VerifyPeerCertificate: s.verifyClientCertificate,
+	}
+	s.Server.StartTLS()
+}
+
+// RootCAs returns a pool with the certificate of a TLS server.
+func (s *Server) RootCAs() *x509.CertPool {
+	pool := x509.NewCertPool()
+	if cert := s.Server.Certificate(); cert != nil {
+		pool.AddCert(cert)
+	}
+	return pool
+}
+
+// verifyClientCertificate checks that a client certificate, if sent, chains
+// to the organization CA, is meant for client authentication and is not
+// revoked.
+func (s *Server) verifyClientCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return nil
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs[i] = cert
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ca := s.certificateAuthority()
+	if ca.org == nil {
+		return errors.New("organization CA is not initialized")
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.root.cert)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(ca.org.cert)
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   s.now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return err
+	}
+
+	c, ok := ca.certs[hex.EncodeToString(certs[0].SerialNumber.Bytes())]
+	if !ok || c.item.Status == certStatusRevoked {
+		return errors.New("client certificate is revoked")
+	}
+	return nil
+}
+
+// authenticated reports whether a request to a mutual TLS server carries
+// credentials.
+func authenticated(r *http.Request) bool {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return true
+	}
+	return r.Header.Get("X-API-Key") != "" || strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// clientCertificate returns the client certificate of r, if any.
+func clientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	return r.TLS.PeerCertificates[0]
+}
@@ -0,0 +1,456 @@
+package envsynctest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxBatchSize is the largest batch the API accepts.
+const maxBatchSize = 100
+
+// Operations recorded in the point-in-time history.
+const (
+	operationCreate = "CREATE"
+	operationUpdate = "UPDATE"
+	operationDelete = "DELETE"
+)
+
+// scope identifies the variables of one environment type of an app.
+type scope struct {
+	appID     string
+	envTypeID string
+}
+
+type entry struct {
+	id        string
+	value     string
+	createdAt string
+	updatedAt string
+}
+
+// change is a single operation of a point in time.
+type change struct {
+	key       string
+	value     string
+	operation string
+}
+
+// pit is a point in time: the changes made by one request.
+type pit struct {
+	id        string
+	message   string
+	createdAt time.Time
+	changes   []change
+}
+
+// store holds the variables or secrets of a scope and their history.
+type store struct {
+	entries map[string]*entry
+	history []*pit
+}
+
+// kind describes the differences between the variable and secret endpoints.
+type kind struct {
+	// prefix is the route prefix and audit action prefix, "env" or
+	// "secret".
+	prefix string
+	stores func(*Server) map[scope]*store
+}
+
+var (
+	variableKind = kind{
+		prefix: "env",
+		stores: func(s *Server) map[scope]*store { return s.variables },
+	}
+	secretKind = kind{
+		prefix: "secret",
+		stores: func(s *Server) map[scope]*store { return s.secrets },
+	}
+)
+
+// SetVariables replaces the variables of an environment type, recording the
+// change in its history.
+func (s *Server) SetVariables(appID, envTypeID string, values map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replace(variableKind, scope{appID: appID, envTypeID: envTypeID}, values, "Set variables")
+}
+
+// Variables returns the current variables of an environment type.
+func (s *Server) Variables(appID, envTypeID string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.store(variableKind, scope{appID: appID, envTypeID: envTypeID}).values()
+}
+
+// SetSecrets replaces the secrets of an environment type, recording the
+// change in its history. Values are stored as given.
+func (s *Server) SetSecrets(appID, envTypeID string, values map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replace(secretKind, scope{appID: appID, envTypeID: envTypeID}, values, "Set secrets")
+}
+
+// Secrets returns the current secrets of an environment type.
+func (s *Server) Secrets(appID, envTypeID string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.store(secretKind, scope{appID: appID, envTypeID: envTypeID}).values()
+}
+
+func (s *Server) store(k kind, sc scope) *store {
+	stores := k.stores(s)
+	st, ok := stores[sc]
+	if !ok {
+		st = &store{entries: make(map[string]*entry)}
+		stores[sc] = st
+	}
+	return st
+}
+
+func (st *store) values() map[string]string {
+	values := make(map[string]string, len(st.entries))
+	for key, e := range st.entries {
+		values[key] = e.value
+	}
+	return values
+}
+
+func (st *store) sortedKeys() []string {
+	keys := make([]string, 0, len(st.entries))
+	for key := range st.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// apply performs the changes and records them as a point in time.
+func (s *Server) apply(st *store, message string, changes []change) *pit {
+	now := s.now()
+	for _, c := range changes {
+		switch c.operation {
+		case operationCreate, operationUpdate:
+			e, ok := st.entries[c.key]
+			if !ok {
+				e = &entry{id: uuid.NewString(), createdAt: formatTime(now)}
+				st.entries[c.key] = e
+			}
+			e.value = c.value
+			e.updatedAt = formatTime(now)
+		case operationDelete:
+			delete(st.entries, c.key)
+		}
+	}
+
+	p := &pit{
+		id:        uuid.NewString(),
+		message:   message,
+		createdAt: now,
+		changes:   changes,
+	}
+	st.history = append(st.history, p)
+	return p
+}
+
+// replace makes values the state of the scope.
+func (s *Server) replace(k kind, sc scope, values map[string]string, message string) {
+	st := s.store(k, sc)
+	changes := diffStates(st.values(), values)
+	if len(changes) > 0 {
+		s.apply(st, message, changes)
+	}
+}
+
+// diffStates returns the changes that turn from into to, ordered by key.
+func diffStates(from, to map[string]string) []change {
+	var changes []change
+	for key, value := range to {
+		old, ok := from[key]
+		switch {
+		case !ok:
+			changes = append(changes, change{key: key, value: value, operation: operationCreate})
+		case old != value:
+			changes = append(changes, change{key: key, value: value, operation: operationUpdate})
+		}
+	}
+	for key, value := range from {
+		if _, ok := to[key]; !ok {
+			changes = append(changes, change{key: key, value: value, operation: operationDelete})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].key < changes[j].key
+	})
+	return changes
+}
+
+// variableRequest covers the bodies of the variable and secret endpoints.
+type variableRequest struct {
+	AppId     string   `json:"app_id"`
+	EnvTypeId string   `json:"env_type_id"`
+	Key       string   `json:"key"`
+	Value     string   `json:"value"`
+	Keys      []string `json:"keys"`
+	Envs      []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"envs"`
+}
+
+// entryResponse matches both EnvResponse and SecretResponse.
+type entryResponse struct {
+	Id        string `json:"id"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	AppId     string `json:"app_id"`
+	EnvTypeId string `json:"env_type_id"`
+	OrgId     string `json:"org_id"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type messageResponse struct {
+	Message string `json:"message"`
+}
+
+func newEntryResponse(sc scope, key string, e *entry) *entryResponse {
+	return &entryResponse{
+		Id:        e.id,
+		Key:       key,
+		Value:     e.value,
+		AppId:     sc.appID,
+		EnvTypeId: sc.envTypeID,
+		OrgId:     OrgID,
+		CreatedAt: e.createdAt,
+		UpdatedAt: e.updatedAt,
+	}
+}
+
+func (s *Server) registerVariables(mux *http.ServeMux) {
+	for _, k := range []kind{variableKind, secretKind} {
+		base := "/api/" + k.prefix
+		mux.HandleFunc("POST "+base, s.withScope(k, s.listEntries))
+		mux.HandleFunc("DELETE "+base, s.withScope(k, s.deleteEntry))
+		mux.HandleFunc("POST "+base+"/i/{key}", s.withScope(k, s.getEntry))
+		mux.HandleFunc("PATCH "+base+"/i/{key}", s.withScope(k, s.updateEntry))
+		mux.HandleFunc("PUT "+base+"/single", s.withScope(k, s.createEntry))
+		mux.HandleFunc("PUT "+base+"/batch", s.withScope(k, s.batchCreateEntries))
+		mux.HandleFunc("PATCH "+base+"/batch", s.withScope(k, s.batchUpdateEntries))
+		mux.HandleFunc("DELETE "+base+"/batch", s.withScope(k, s.batchDeleteEntries))
+
+		s.registerHistory(mux, k)
+	}
+	mux.HandleFunc("POST /api/secret/reveal", s.withScope(secretKind, s.revealSecrets))
+}
+
+// scopedHandler handles a request for the variables or secrets of a scope.
+type scopedHandler func(w http.ResponseWriter, r *http.Request, k kind, sc scope, request *variableRequest)
+
+// withScope decodes the request body and checks that the app and environment
+// type it refers to exist.
+func (s *Server) withScope(k kind, handler scopedHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request variableRequest
+		if !decode(w, r, &request) {
+			return
+		}
+		sc := scope{appID: request.AppId, envTypeID: request.EnvTypeId}
+		if !s.checkScope(w, sc) {
+			return
+		}
+
+		handler(w, r, k, sc, &request)
+	}
+}
+
+// checkScope reports a 400 or 404 response unless the app and environment type
+// of sc exist.
+func (s *Server) checkScope(w http.ResponseWriter, sc scope) bool {
+	if sc.appID == "" || sc.envTypeID == "" {
+		writeError(w, http.StatusBadRequest, "", "org_id, app_id, and env_type_id are required.")
+		return false
+	}
+	if _, ok := s.apps[sc.appID]; !ok {
+		notFound(w, "App", sc.appID)
+		return false
+	}
+	if e, ok := s.envTypes[sc.envTypeID]; !ok || e.appID != sc.appID {
+		notFound(w, "Environment type", sc.envTypeID)
+		return false
+	}
+	return true
+}
+
+func (s *Server) auditEntries(action string, sc scope, keys []string) {
+	s.audit(action, fmt.Sprintf("%s in app %s for environment type %s for keys: %s.", action, sc.appID, sc.envTypeID, strings.Join(keys, ", ")),
+		map[string]interface{}{"app_id": sc.appID, "env_type_id": sc.envTypeID, "keys": keys})
+}
+
+func (s *Server) listEntries(w http.ResponseWriter, r *http.Request, k kind, sc scope, _ *variableRequest) {
+	st := s.store(k, sc)
+	response := []*entryResponse{}
+	for _, key := range st.sortedKeys() {
+		response = append(response, newEntryResponse(sc, key, st.entries[key]))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getEntry(w http.ResponseWriter, r *http.Request, k kind, sc scope, _ *variableRequest) {
+	key := r.PathValue("key")
+	e, ok := s.store(k, sc).entries[key]
+	if !ok {
+		notFound(w, "Environment variable", key)
+		return
+	}
+	writeJSON(w, http.StatusOK, newEntryResponse(sc, key, e))
+}
+
+func (s *Server) createEntry(w http.ResponseWriter, r *http.Request, k kind, sc scope, request *variableRequest) {
+	if request.Key == "" {
+		writeError(w, http.StatusBadRequest, "", "key, org_id, app_id, and env_type_id are required.")
+		return
+	}
+	st := s.store(k, sc)
+	if _, ok := st.entries[request.Key]; ok {
+		writeError(w, http.StatusBadRequest, "", "Environment variable already exists.")
+		return
+	}
+
+	s.apply(st, "Created "+request.Key, []change{{key: request.Key, value: request.Value, operation: operationCreate}})
+	s.auditEntries(k.prefix+"_created", sc, []string{request.Key})
+	writeJSON(w, http.StatusCreated, newEntryResponse(sc, request.Key, st.entries[request.Key]))
+}
+
+func (s *Server) updateEntry(w http.ResponseWriter, r *http.Request, k kind, sc scope, request *variableRequest) {
+	key := r.PathValue("key")
+	st := s.store(k, sc)
+	if _, ok := st.entries[key]; !ok {
+		notFound(w, "Environment variable", key)
+		return
+	}
+
+	s.apply(st, "Updated "+key, []change{{key: key, value: request.Value, operation: operationUpdate}})
+	s.auditEntries(k.prefix+"_updated", sc, []string{key})
+	writeJSON(w, http.StatusOK, newEntryResponse(sc, key, st.entries[key]))
+}
+
+func (s *Server) deleteEntry(w http.ResponseWriter, r *http.Request, k kind, sc scope, request *variableRequest) {
+	st := s.store(k, sc)
+	e, ok := st.entries[request.Key]
+	if !ok {
+		notFound(w, "Environment variable", request.Key)
+		return
+	}
+
+	s.apply(st, "Deleted "+request.Key, []change{{key: request.Key, value: e.value, operation: operationDelete}})
+	s.auditEntries(k.prefix+"_deleted", sc, []string{request.Key})
+	writeJSON(w, http.StatusOK, &messageResponse{Message: "Env deleted successfully"})
+}
+
+// batchEnvs validates the envs of a batch request.
+func batchEnvs(w http.ResponseWriter, request *variableRequest) ([]change, bool) {
+	if len(request.Envs) > maxBatchSize {
+		writeError(w, http.StatusBadRequest, "", fmt.Sprintf("Batch size exceeds maximum of %d items", maxBatchSize))
+		return nil, false
+	}
+	changes := make([]change, 0, len(request.Envs))
+	for _, env := range request.Envs {
+		if env.Key == "" {
+			writeError(w, http.StatusBadRequest, "", "key is required for every env.")
+			return nil, false
+		}
+		changes = append(changes, change{key: env.Key, value: env.Value})
+	}
+	return changes, true
+}
+
+func changedKeys(changes []change) []string {
+	keys := make([]string, len(changes))
+	for i, c := range changes {
+		keys[i] = c.key
+	}
+	return keys
+}
+
+func (s *Server) batchCreateEntries(w http.ResponseWriter, r *http.Request, k kind, sc scope, request *variableRequest) {
+	changes, ok := batchEnvs(w, request)
+	if !ok {
+		return
+	}
+	st := s.store(k, sc)
+	for i := range changes {
+		if _, exists := st.entries[changes[i].key]; exists {
+			conflict(w, "Environment variable already exists: "+changes[i].key)
+			return
+		}
+		changes[i].operation = operationCreate
+	}
+
+	keys := changedKeys(changes)
+	s.apply(st, fmt.Sprintf("Batch created %d %ss: %s", len(changes), k.prefix, strings.Join(keys, ", ")), changes)
+	s.auditEntries(k.prefix+"s_batch_created", sc, keys)
+	writeJSON(w, http.StatusCreated, &messageResponse{Message: "Envs created successfully"})
+}
+
+func (s *Server) batchUpdateEntries(w http.ResponseWriter, r *http.Request, k kind, sc scope, request *variableRequest) {
+	changes, ok := batchEnvs(w, request)
+	if !ok {
+		return
+	}
+	st := s.store(k, sc)
+	for i := range changes {
+		if _, exists := st.entries[changes[i].key]; !exists {
+			notFound(w, "Environment variable", changes[i].key)
+			return
+		}
+		changes[i].operation = operationUpdate
+	}
+
+	keys := changedKeys(changes)
+	s.apply(st, fmt.Sprintf("Batch updated %d %ss: %s", len(changes), k.prefix, strings.Join(keys, ", ")), changes)
+	s.auditEntries(k.prefix+"s_batch_updated", sc, keys)
+	writeJSON(w, http.StatusOK, &messageResponse{Message: "Envs updated successfully"})
+}
+
+func (s *Server) batchDeleteEntries(w http.ResponseWriter, r *http.Request, k kind, sc scope, request *variableRequest) {
+	if len(request.Keys) > maxBatchSize {
+		writeError(w, http.StatusBadRequest, "", fmt.Sprintf("Batch size exceeds maximum of %d items", maxBatchSize))
+		return
+	}
+
+	// Missing keys are ignored, like the API does
+	st := s.store(k, sc)
+	var changes []change
+	for _, key := range request.Keys {
+		if e, ok := st.entries[key]; ok {
+			changes = append(changes, change{key: key, value: e.value, operation: operationDelete})
+		}
+	}
+	if len(changes) > 0 {
+		s.apply(st, fmt.Sprintf("Batch deleted %d %ss: %s", len(changes), k.prefix, strings.Join(changedKeys(changes), ", ")), changes)
+	}
+	s.auditEntries(k.prefix+"s_batch_deleted", sc, request.Keys)
+	writeJSON(w, http.StatusOK, &messageResponse{Message: "Envs deleted successfully"})
+}
+
+func (s *Server) revealSecrets(w http.ResponseWriter, r *http.Request, k kind, sc scope, request *variableRequest) {
+	st := s.store(k, sc)
+	selected := request.Keys
+	if len(selected) == 0 {
+		selected = st.sortedKeys()
+	}
+	response := []*entryResponse{}
+	for _, key := range selected {
+		if e, ok := st.entries[key]; ok {
+			response = append(response, newEntryResponse(sc, key, e))
+		}
+	}
+	s.auditEntries(k.prefix+"s_revealed", sc, selected)
+	writeJSON(w, http.StatusOK, response)
+}
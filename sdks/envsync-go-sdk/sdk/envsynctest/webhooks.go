@@ -0,0 +1,132 @@
+package envsynctest
+
+import (
+	"net/http"
+	"sort"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	"github.com/google/uuid"
+)
+
+type webhook struct {
+	response *sdk.WebhookResponse
+	seq      int
+}
+
+func (s *Server) registerWebhooks(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/webhook", s.getWebhooks)
+	mux.HandleFunc("POST /api/webhook", s.postWebhook)
+	mux.HandleFunc("GET /api/webhook/{id}", s.getWebhook)
+	mux.HandleFunc("PUT /api/webhook/{id}", s.putWebhook)
+	mux.HandleFunc("DELETE /api/webhook/{id}", s.deleteWebhook)
+}
+
+func (s *Server) getWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks := make([]*webhook, 0, len(s.webhooks))
+	for _, wh := range s.webhooks {
+		webhooks = append(webhooks, wh)
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].seq < webhooks[j].seq
+	})
+
+	response := sdk.WebhooksResponse{}
+	for _, wh := range webhooks {
+		response = append(response, wh.response)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) postWebhook(w http.ResponseWriter, r *http.Request) {
+	var request sdk.CreateWebhookRequest
+	if !decode(w, r, &request) {
+		return
+	}
+	if request.Name == "" || request.Url == "" {
+		writeError(w, http.StatusBadRequest, "", "name and url are required.")
+		return
+	}
+
+	now := s.timestamp()
+	response := &sdk.WebhookResponse{
+		Id:          uuid.NewString(),
+		Name:        request.Name,
+		OrgId:       OrgID,
+		UserId:      UserID,
+		Url:         request.Url,
+		EventTypes:  request.EventTypes,
+		IsActive:    true,
+		WebhookType: sdk.WebhookResponseWebhookType(request.WebhookType),
+		AppId:       request.AppId,
+		LinkedTo:    sdk.WebhookResponseLinkedToOrg,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if response.EventTypes == nil {
+		response.EventTypes = []string{}
+	}
+	if request.LinkedTo != nil {
+		response.LinkedTo = sdk.WebhookResponseLinkedTo(*request.LinkedTo)
+	}
+	s.webhooks[response.Id] = &webhook{response: response, seq: s.nextSeq()}
+	s.audit("webhook_created", "Webhook "+response.Name+" created.", map[string]interface{}{"webhook_id": response.Id})
+	writeJSON(w, http.StatusCreated, response)
+}
+
+func (s *Server) getWebhook(w http.ResponseWriter, r *http.Request) {
+	wh, ok := s.webhooks[r.PathValue("id")]
+	if !ok {
+		notFound(w, "Webhook", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, wh.response)
+}
+
+func (s *Server) putWebhook(w http.ResponseWriter, r *http.Request) {
+	wh, ok := s.webhooks[r.PathValue("id")]
+	if !ok {
+		notFound(w, "Webhook", r.PathValue("id"))
+		return
+	}
+	var request sdk.UpdateWebhookRequest
+	if !decode(w, r, &request) {
+		return
+	}
+
+	response := wh.response
+	if request.Name != nil {
+		response.Name = *request.Name
+	}
+	if request.Url != nil {
+		response.Url = *request.Url
+	}
+	if request.EventTypes != nil {
+		response.EventTypes = request.EventTypes
+	}
+	if request.IsActive != nil {
+		response.IsActive = *request.IsActive
+	}
+	if request.WebhookType != nil {
+		response.WebhookType = sdk.WebhookResponseWebhookType(*request.WebhookType)
+	}
+	if request.AppId != nil {
+		response.AppId = request.AppId
+	}
+	if request.LinkedTo != nil {
+		response.LinkedTo = sdk.WebhookResponseLinkedTo(*request.LinkedTo)
+	}
+	response.UpdatedAt = s.timestamp()
+	s.audit("webhook_updated", "Webhook "+response.Name+" updated.", map[string]interface{}{"webhook_id": response.Id})
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	wh, ok := s.webhooks[r.PathValue("id")]
+	if !ok {
+		notFound(w, "Webhook", r.PathValue("id"))
+		return
+	}
+	delete(s.webhooks, wh.response.Id)
+	s.audit("webhook_deleted", "Webhook "+wh.response.Name+" deleted.", map[string]interface{}{"webhook_id": wh.response.Id})
+	writeJSON(w, http.StatusOK, wh.response)
+}
@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -61,10 +64,13 @@ func (c *Client) GenerateGpgKey(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "gpgkeys.GenerateGpgKey",
 			URL:             endpointURL,
 			Method:          http.MethodPut,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -108,10 +114,13 @@ func (c *Client) ImportGpgKey(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "gpgkeys.ImportGpgKey",
 			URL:             endpointURL,
 			Method:          http.MethodPut,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -155,10 +164,13 @@ func (c *Client) SignDataWithGpgKey(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "gpgkeys.SignDataWithGpgKey",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -202,10 +214,13 @@ func (c *Client) VerifyGpgSignature(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "gpgkeys.VerifyGpgSignature",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -247,10 +262,13 @@ func (c *Client) ListGpgKeys(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "gpgkeys.ListGpgKeys",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -295,10 +313,13 @@ func (c *Client) GetGpgKey(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "gpgkeys.GetGpgKey",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -343,10 +364,13 @@ func (c *Client) DeleteGpgKey(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "gpgkeys.DeleteGpgKey",
 			URL:             endpointURL,
 			Method:          http.MethodDelete,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -391,10 +415,13 @@ func (c *Client) ExportGpgPublicKey(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "gpgkeys.ExportGpgPublicKey",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -441,10 +468,13 @@ func (c *Client) RevokeGpgKey(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "gpgkeys.RevokeGpgKey",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -492,10 +522,13 @@ func (c *Client) UpdateGpgKeyTrustLevel(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "gpgkeys.UpdateGpgKeyTrustLevel",
 			URL:             endpointURL,
 			Method:          http.MethodPatch,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// BatchFunc sends a single chunk of a batch.
+type BatchFunc[T any] func(ctx context.Context, chunk []T) error
+
+// Batch splits items into chunks of options.Size, sends up to
+// options.Concurrency of them at the same time and reports the outcome of
+// every key. key returns the key identifying an item.
+//
+// A failed chunk does not stop the others. When a chunk is rejected because
+// of its content, such as a key that already exists, it is split in halves
+// and sent again until the offending keys are isolated, so that only they are
+// reported as failed. Chunks that fail for any other reason, or that were not
+// sent because the context was cancelled, fail as a whole.
+func Batch[T any](ctx context.Context, items []T, key func(T) string, options *core.BatchOptions, send BatchFunc[T]) *core.BatchResult {
+	size, concurrency := core.MaxBatchSize, core.DefaultBatchConcurrency
+	var skip map[string]bool
+	if options != nil {
+		if options.Size > 0 && options.Size < size {
+			size = options.Size
+		}
+		if options.Concurrency > 0 {
+			concurrency = options.Concurrency
+		}
+		if options.Resume != nil {
+			skip = make(map[string]bool, len(options.Resume.Succeeded))
+			for _, k := range options.Resume.Succeeded {
+				skip[k] = true
+			}
+		}
+	}
+
+	pending := make([]T, 0, len(items))
+	for _, item := range items {
+		if !skip[key(item)] {
+			pending = append(pending, item)
+		}
+	}
+
+	var chunks [][]T
+	for start := 0; start < len(pending); start += size {
+		chunks = append(chunks, pending[start:min(start+size, len(pending))])
+	}
+
+	results := make([]*core.BatchResult, len(chunks))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			results[i] = failChunk(chunk, key, ctx.Err())
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i] = &core.BatchResult{}
+			sendChunk(ctx, chunk, key, send, results[i])
+		}()
+	}
+	wg.Wait()
+
+	result := &core.BatchResult{}
+	for _, chunkResult := range results {
+		result.Succeeded = append(result.Succeeded, chunkResult.Succeeded...)
+		result.Failed = append(result.Failed, chunkResult.Failed...)
+	}
+	return result
+}
+
+// sendChunk sends chunk and records its outcome in result, bisecting it when
+// it is rejected because of its content.
+func sendChunk[T any](ctx context.Context, chunk []T, key func(T) string, send BatchFunc[T], result *core.BatchResult) {
+	err := ctx.Err()
+	if err == nil {
+		err = send(ctx, chunk)
+	}
+	switch {
+	case err == nil:
+		for _, item := range chunk {
+			result.Succeeded = append(result.Succeeded, key(item))
+		}
+	case len(chunk) > 1 && isRejected(err):
+		middle := len(chunk) / 2
+		sendChunk(ctx, chunk[:middle], key, send, result)
+		sendChunk(ctx, chunk[middle:], key, send, result)
+	default:
+		failed := failChunk(chunk, key, err)
+		result.Failed = append(result.Failed, failed.Failed...)
+	}
+}
+
+func failChunk[T any](chunk []T, key func(T) string, err error) *core.BatchResult {
+	result := &core.BatchResult{}
+	for _, item := range chunk {
+		result.Failed = append(result.Failed, &core.BatchFailure{Key: key(item), Err: err})
+	}
+	return result
+}
+
+// isRejected reports whether err rejects the content of a request, as
+// opposed to a failure that would affect any request.
+func isRejected(err error) bool {
+	apiError, ok := core.AsAPIError(err)
+	if !ok {
+		return false
+	}
+	switch apiError.StatusCode {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity:
+		return true
+	}
+	return false
+}
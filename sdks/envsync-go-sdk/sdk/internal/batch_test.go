@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func batchKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("KEY_%03d", i)
+	}
+	return keys
+}
+
+func identity(key string) string { return key }
+
+func TestBatchChunks(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	result := Batch(context.Background(), batchKeys(250), identity, nil, func(_ context.Context, chunk []string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sizes = append(sizes, len(chunk))
+		return nil
+	})
+
+	slices.Sort(sizes)
+	assert.Equal(t, []int{50, 100, 100}, sizes)
+	assert.Equal(t, batchKeys(250), result.Succeeded)
+	assert.Empty(t, result.Failed)
+	assert.NoError(t, result.Err())
+}
+
+func TestBatchSizeIsCapped(t *testing.T) {
+	var largest atomic.Int64
+	Batch(context.Background(), batchKeys(300), identity, &core.BatchOptions{Size: 500}, func(_ context.Context, chunk []string) error {
+		if n := int64(len(chunk)); n > largest.Load() {
+			largest.Store(n)
+		}
+		return nil
+	})
+	assert.EqualValues(t, core.MaxBatchSize, largest.Load())
+}
+
+func TestBatchConcurrency(t *testing.T) {
+	var running, peak atomic.Int64
+	Batch(context.Background(), batchKeys(20), identity, &core.BatchOptions{Size: 2, Concurrency: 3}, func(context.Context, []string) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+	assert.EqualValues(t, 3, peak.Load())
+}
+
+func TestBatchIsolatesRejectedKeys(t *testing.T) {
+	bad := map[string]bool{"KEY_007": true, "KEY_042": true}
+	var requests atomic.Int64
+	result := Batch(context.Background(), batchKeys(50), identity, &core.BatchOptions{Size: 25}, func(_ context.Context, chunk []string) error {
+		requests.Add(1)
+		for _, key := range chunk {
+			if bad[key] {
+				return core.NewAPIError(http.StatusConflict, errors.New("already exists: "+key))
+			}
+		}
+		return nil
+	})
+
+	assert.Equal(t, []string{"KEY_007", "KEY_042"}, result.FailedKeys())
+	assert.Len(t, result.Succeeded, 48)
+	assert.Less(t, requests.Load(), int64(50))
+
+	err := result.Err()
+	batchError, ok := core.AsBatchError(err)
+	require.True(t, ok)
+	assert.Same(t, result, batchError.Result)
+	assert.ErrorIs(t, err, core.ErrConflict)
+	assert.Contains(t, err.Error(), "2 of 50 keys failed (KEY_007, KEY_042): 409")
+}
+
+func TestBatchServerErrorFailsChunk(t *testing.T) {
+	result := Batch(context.Background(), batchKeys(10), identity, &core.BatchOptions{Size: 5}, func(_ context.Context, chunk []string) error {
+		if chunk[0] == "KEY_005" {
+			return core.NewAPIError(http.StatusInternalServerError, errors.New("boom"))
+		}
+		return nil
+	})
+
+	assert.Equal(t, batchKeys(5), result.Succeeded)
+	assert.Equal(t, batchKeys(10)[5:], result.FailedKeys())
+}
+
+func TestBatchResume(t *testing.T) {
+	first := Batch(context.Background(), batchKeys(10), identity, &core.BatchOptions{Size: 5}, func(_ context.Context, chunk []string) error {
+		if chunk[0] == "KEY_005" {
+			return errors.New("connection reset")
+		}
+		return nil
+	})
+	require.Len(t, first.Failed, 5)
+
+	var sent []string
+	second := Batch(context.Background(), batchKeys(10), identity, &core.BatchOptions{Size: 5, Concurrency: 1, Resume: first}, func(_ context.Context, chunk []string) error {
+		sent = append(sent, chunk...)
+		return nil
+	})
+	assert.Equal(t, batchKeys(10)[5:], sent)
+	assert.Equal(t, batchKeys(10)[5:], second.Succeeded)
+	assert.Empty(t, second.Failed)
+}
+
+func TestBatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls atomic.Int64
+	result := Batch(ctx, batchKeys(10), identity, &core.BatchOptions{Size: 2}, func(context.Context, []string) error {
+		calls.Add(1)
+		return nil
+	})
+	assert.Zero(t, calls.Load())
+	assert.Len(t, result.Failed, 10)
+	assert.ErrorIs(t, result.Err(), context.Canceled)
+}
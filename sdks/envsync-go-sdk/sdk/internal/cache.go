@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// readOperations are the operations sent with POST that only read data, so
+// their responses may be cached like those of GET requests.
+var readOperations = map[string]bool{
+	"environmentvariables.GetEnvs":                         true,
+	"environmentvariables.GetEnv":                          true,
+	"environmentvariablespointintime.GetEnvHistory":        true,
+	"environmentvariablespointintime.GetEnvsAtPointInTime": true,
+	"environmentvariablespointintime.GetEnvsAtTimestamp":   true,
+	"environmentvariablespointintime.GetEnvDiff":           true,
+	"environmentvariablespointintime.GetVariableTimeline":  true,
+	"secrets.GetSecrets":                                   true,
+	"secrets.GetSecret":                                    true,
+	"secrets.RevealSecrets":                                true,
+	"secretspointintime.GetSecretHistory":                  true,
+	"secretspointintime.GetSecretsAtPointInTime":           true,
+	"secretspointintime.GetSecretsAtTimestamp":             true,
+	"secretspointintime.GetSecretDiff":                     true,
+	"secretspointintime.GetSecretVariableTimeline":         true,
+}
+
+// isReadRequest reports whether a request only reads data.
+func isReadRequest(method, operation string) bool {
+	return method == http.MethodGet || readOperations[operation]
+}
+
+// isSensitive reports whether the response of an operation holds secrets.
+func isSensitive(operation string) bool {
+	return strings.HasPrefix(operation, "secrets.") || strings.HasPrefix(operation, "secretspointintime.")
+}
+
+// cacheKey identifies the response to a request by its method, URL, body and
+// credentials, so responses are never shared between identities.
+func cacheKey(req *http.Request) (string, error) {
+	hash := sha256.New()
+	for _, part := range []string{
+		req.Method,
+		req.URL.String(),
+		req.Header.Get("Authorization"),
+		req.Header.Get("X-API-Key"),
+	} {
+		io.WriteString(hash, part)
+		hash.Write([]byte{0})
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+		if _, err := io.Copy(hash, body); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// cachedCall wraps the sending of a request with the cache. Read requests are
+// served from fresh entries, revalidated with conditional headers and cached
+// when successful; successful writes clear the cache.
+func cachedCall(cache *core.Cache, operation string, send func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		if !isReadRequest(req.Method, operation) {
+			resp, err := send(req)
+			if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				cache.Store.Clear()
+			}
+			return resp, err
+		}
+
+		key, err := cacheKey(req)
+		if err != nil {
+			return nil, err
+		}
+		entry, fresh := cache.Lookup(key)
+		if fresh {
+			return entry.Response(req), nil
+		}
+		if entry != nil {
+			if entry.ETag != "" {
+				req.Header.Set("If-None-Match", entry.ETag)
+			}
+			if entry.LastModified != "" {
+				req.Header.Set("If-Modified-Since", entry.LastModified)
+			}
+		}
+
+		resp, err := send(req)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case resp.StatusCode == http.StatusNotModified && entry != nil:
+			resp.Body.Close()
+			cache.Revalidated(key, entry, resp.Header)
+			return entry.Response(req), nil
+		case resp.StatusCode == http.StatusOK:
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			cache.Save(key, resp, body, isSensitive(operation))
+		}
+		return resp, nil
+	}
+}
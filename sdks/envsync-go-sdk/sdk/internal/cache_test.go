@@ -0,0 +1,236 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheServer serves a versioned value and records the conditional headers
+// it receives.
+type cacheServer struct {
+	mu          sync.Mutex
+	version     int
+	validators  bool
+	header      http.Header
+	requests    int
+	notModified int
+	conditional []string
+}
+
+func (s *cacheServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	if r.Method == http.MethodPatch {
+		s.version++
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	etag := `"v` + string(rune('0'+s.version)) + `"`
+	s.conditional = append(s.conditional, r.Header.Get("If-None-Match"))
+	for name, values := range s.header {
+		w.Header()[name] = values
+	}
+	if s.validators {
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			s.notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]any{"version": s.version, "auth": r.Header.Get("Authorization")})
+}
+
+type cacheClock struct{ now time.Time }
+
+func (c *cacheClock) Now() time.Time                         { return c.now }
+func (c *cacheClock) After(d time.Duration) <-chan time.Time { return nil }
+
+type versionResponse struct {
+	Version int    `json:"version"`
+	Auth    string `json:"auth"`
+}
+
+func newCacheCaller(cache *core.Cache) *Caller {
+	return NewCaller(&CallerParams{Client: http.DefaultClient, Cache: cache})
+}
+
+func callVersion(t *testing.T, caller *Caller, url, method, operation, token string) versionResponse {
+	t.Helper()
+	var response versionResponse
+	headers := http.Header{}
+	if token != "" {
+		headers.Set("Authorization", "Bearer "+token)
+	}
+	err := caller.Call(context.Background(), &CallParams{
+		Operation: operation,
+		URL:       url,
+		Method:    method,
+		Headers:   headers,
+		Request:   map[string]string{"app_id": "app"},
+		Response:  &response,
+	})
+	require.NoError(t, err)
+	return response
+}
+
+func TestCacheConditionalRequests(t *testing.T) {
+	backend := &cacheServer{validators: true}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+	caller := newCacheCaller(&core.Cache{Store: core.NewMemoryCacheStore()})
+
+	assert.Equal(t, 0, callVersion(t, caller, server.URL, http.MethodGet, "", "").Version)
+	assert.Equal(t, 0, callVersion(t, caller, server.URL, http.MethodGet, "", "").Version)
+	assert.Equal(t, []string{"", `"v0"`}, backend.conditional)
+	assert.Equal(t, 1, backend.notModified)
+
+	backend.version = 1
+	assert.Equal(t, 1, callVersion(t, caller, server.URL, http.MethodGet, "", "").Version)
+	assert.Equal(t, 1, callVersion(t, caller, server.URL, http.MethodGet, "", "").Version)
+	assert.Equal(t, 2, backend.notModified)
+}
+
+func TestCacheTTL(t *testing.T) {
+	backend := &cacheServer{}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+	clock := &cacheClock{now: time.Now()}
+	caller := newCacheCaller(&core.Cache{Store: core.NewMemoryCacheStore(), TTL: time.Minute, Clock: clock})
+
+	callVersion(t, caller, server.URL, http.MethodPost, "environmentvariables.GetEnvs", "")
+	backend.version = 1
+	assert.Equal(t, 0, callVersion(t, caller, server.URL, http.MethodPost, "environmentvariables.GetEnvs", "").Version)
+	assert.Equal(t, 1, backend.requests)
+
+	clock.now = clock.now.Add(time.Minute)
+	assert.Equal(t, 1, callVersion(t, caller, server.URL, http.MethodPost, "environmentvariables.GetEnvs", "").Version)
+	assert.Equal(t, 2, backend.requests)
+}
+
+func TestCacheSkipsUncacheableResponses(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		operation string
+		ttl       time.Duration
+		header    http.Header
+	}{
+		{"no validators or ttl", http.MethodGet, "", 0, nil},
+		{"no-store", http.MethodGet, "", time.Minute, http.Header{"Cache-Control": {"no-store"}}},
+		{"post that writes", http.MethodPost, "applications.CreateApp", time.Minute, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &cacheServer{header: tt.header}
+			server := httptest.NewServer(backend)
+			defer server.Close()
+			caller := newCacheCaller(&core.Cache{Store: core.NewMemoryCacheStore(), TTL: tt.ttl})
+
+			callVersion(t, caller, server.URL, tt.method, tt.operation, "")
+			callVersion(t, caller, server.URL, tt.method, tt.operation, "")
+			assert.Equal(t, 2, backend.requests)
+		})
+	}
+}
+
+func TestCacheKeyedByIdentity(t *testing.T) {
+	backend := &cacheServer{}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+	caller := newCacheCaller(&core.Cache{Store: core.NewMemoryCacheStore(), TTL: time.Minute})
+
+	assert.Equal(t, "Bearer alice", callVersion(t, caller, server.URL, http.MethodGet, "", "alice").Auth)
+	assert.Equal(t, "Bearer bob", callVersion(t, caller, server.URL, http.MethodGet, "", "bob").Auth)
+	assert.Equal(t, "Bearer alice", callVersion(t, caller, server.URL, http.MethodGet, "", "alice").Auth)
+	assert.Equal(t, 2, backend.requests)
+}
+
+func TestCacheClearedByWrites(t *testing.T) {
+	backend := &cacheServer{}
+	server := httptest.NewServer(backend)
+	defer server.Close()
+	caller := newCacheCaller(&core.Cache{Store: core.NewMemoryCacheStore(), TTL: time.Hour})
+
+	callVersion(t, caller, server.URL, http.MethodGet, "", "")
+	require.NoError(t, caller.Call(context.Background(), &CallParams{
+		Operation: "environmentvariables.UpdateEnv",
+		URL:       server.URL,
+		Method:    http.MethodPatch,
+	}))
+	assert.Equal(t, 1, callVersion(t, caller, server.URL, http.MethodGet, "", "").Version)
+}
+
+func TestFileCacheStore(t *testing.T) {
+	entry := &core.CacheEntry{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       []byte(`{"value":"s3cr3t"}`),
+		Expires:    time.Now().Add(time.Hour).UTC().Round(0),
+	}
+	sensitive := *entry
+	sensitive.Sensitive = true
+
+	t.Run("plain", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := core.NewFileCacheStore(dir, nil)
+		require.NoError(t, err)
+
+		store.Set("public", entry)
+		got, ok := store.Get("public")
+		require.True(t, ok)
+		assert.Equal(t, entry, got)
+
+		// Secrets are not written to disk without encryption
+		store.Set("secret", &sensitive)
+		_, ok = store.Get("secret")
+		assert.False(t, ok)
+
+		store.Clear()
+		_, ok = store.Get("public")
+		assert.False(t, ok)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		dir := t.TempDir()
+		key := []byte(strings.Repeat("k", 32))
+		store, err := core.NewFileCacheStore(dir, key)
+		require.NoError(t, err)
+
+		store.Set("secret", &sensitive)
+		got, ok := store.Get("secret")
+		require.True(t, ok)
+		assert.Equal(t, sensitive.Body, got.Body)
+
+		files, err := filepath.Glob(filepath.Join(dir, "*"))
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		raw, err := os.ReadFile(files[0])
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), "s3cr3t")
+
+		// Entries written with another key are ignored
+		other, err := core.NewFileCacheStore(dir, []byte(strings.Repeat("x", 32)))
+		require.NoError(t, err)
+		_, ok = other.Get("secret")
+		assert.False(t, ok)
+	})
+
+	_, err := core.NewFileCacheStore(t.TempDir(), []byte("short"))
+	assert.Error(t, err)
+}
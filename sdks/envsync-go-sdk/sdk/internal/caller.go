@@ -23,14 +23,19 @@ const (
 
 // Caller calls APIs and deserializes their response, if any.
 type Caller struct {
-	client  core.HTTPClient
-	retrier *Retrier
+	client     core.HTTPClient
+	retrier    *Retrier
+	middleware []core.Middleware
+	cache      *core.Cache
 }
 
 // CallerParams represents the parameters used to constrcut a new *Caller.
 type CallerParams struct {
 	Client      core.HTTPClient
 	MaxAttempts uint
+	RetryPolicy *core.RetryPolicy
+	Middleware  []core.Middleware
+	Cache       *core.Cache
 }
 
 // NewCaller returns a new *Caller backed by the given parameters.
@@ -43,17 +48,25 @@ func NewCaller(params *CallerParams) *Caller {
 	if params.MaxAttempts > 0 {
 		retryOptions = append(retryOptions, WithMaxAttempts(params.MaxAttempts))
 	}
+	if params.RetryPolicy != nil {
+		retryOptions = append(retryOptions, WithRetryPolicy(params.RetryPolicy))
+	}
 	return &Caller{
-		client:  httpClient,
-		retrier: NewRetrier(retryOptions...),
+		client:     httpClient,
+		retrier:    NewRetrier(retryOptions...),
+		middleware: params.Middleware,
+		cache:      params.Cache,
 	}
 }
 
 // CallParams represents the parameters used to issue an API call.
 type CallParams struct {
+	Operation          string
 	URL                string
 	Method             string
 	MaxAttempts        uint
+	RetryPolicy        *core.RetryPolicy
+	Middleware         []core.Middleware
 	Headers            http.Header
 	BodyProperties     map[string]interface{}
 	QueryParameters    url.Values
@@ -69,9 +82,12 @@ func (c *Caller) Call(ctx context.Context, params *CallParams) error {
 	resp, err := c.CallRaw(
 		ctx,
 		&CallRawParams{
+			Operation:       params.Operation,
 			URL:             params.URL,
 			Method:          params.Method,
 			MaxAttempts:     params.MaxAttempts,
+			RetryPolicy:     params.RetryPolicy,
+			Middleware:      params.Middleware,
 			Headers:         params.Headers,
 			BodyProperties:  params.BodyProperties,
 			QueryParameters: params.QueryParameters,
@@ -111,9 +127,12 @@ func (c *Caller) Call(ctx context.Context, params *CallParams) error {
 
 // CallRawParams represents the parameters used to issue an API call.
 type CallRawParams struct {
+	Operation       string
 	URL             string
 	Method          string
 	MaxAttempts     uint
+	RetryPolicy     *core.RetryPolicy
+	Middleware      []core.Middleware
 	Headers         http.Header
 	BodyProperties  map[string]interface{}
 	QueryParameters url.Values
@@ -125,6 +144,9 @@ type CallRawParams struct {
 // CallRaw issues an API call according to the given call parameters and returns the raw HTTP response.
 // The caller is responsible for closing the response body.
 func (c *Caller) CallRaw(ctx context.Context, params *CallRawParams) (*http.Response, error) {
+	if params.Operation != "" {
+		ctx = core.WithOperation(ctx, params.Operation)
+	}
 	url := buildURL(params.URL, params.QueryParameters)
 	req, err := newRequest(
 		ctx,
@@ -152,13 +174,26 @@ func (c *Caller) CallRaw(ctx context.Context, params *CallRawParams) (*http.Resp
 	if params.MaxAttempts > 0 {
 		retryOptions = append(retryOptions, WithMaxAttempts(params.MaxAttempts))
 	}
+	if params.RetryPolicy != nil {
+		retryOptions = append(retryOptions, WithRetryPolicy(params.RetryPolicy))
+	}
 
-	resp, err := c.retrier.Run(
-		client.Do,
-		req,
-		params.ErrorDecoder,
-		retryOptions...,
-	)
+	// Middleware of the client wraps that of the call
+	do := core.Chain(c.middleware...)(core.Chain(params.Middleware...)(client.Do))
+
+	send := func(req *http.Request) (*http.Response, error) {
+		return c.retrier.Run(
+			RetryFunc(do),
+			req,
+			params.ErrorDecoder,
+			retryOptions...,
+		)
+	}
+	if c.cache != nil && c.cache.Store != nil {
+		send = cachedCall(c.cache, params.Operation, send)
+	}
+
+	resp, err := send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -244,28 +279,29 @@ func newRequestBody(request interface{}, bodyProperties map[string]interface{})
 }
 
 // decodeError decodes the error from the given HTTP response. Note that
-// it's the caller's responsibility to close the response body.
+// we never return a nil error from this function, and the error carries
+// the structured details of the response (see withErrorDetails).
 func decodeError(response *http.Response, errorDecoder ErrorDecoder) error {
+	raw, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read error from response body: %w", err)
+	}
 	if errorDecoder != nil {
 		// This endpoint has custom errors, so we'll
 		// attempt to unmarshal the error into a structured
 		// type based on the status code.
-		return errorDecoder(response.StatusCode, response.Body)
+		return withErrorDetails(errorDecoder(response.StatusCode, bytes.NewReader(raw)), raw, response.Header)
 	}
-	// This endpoint doesn't have any custom error
-	// types, so we just read the body as-is, and
-	// put it into a normal error.
-	bytes, err := io.ReadAll(response.Body)
-	if err != nil && err != io.EOF {
-		return err
-	}
-	if err == io.EOF {
+	if len(raw) == 0 {
 		// The error didn't have a response body,
 		// so all we can do is return an error
 		// with the status code.
-		return core.NewAPIError(response.StatusCode, nil)
+		return withErrorDetails(core.NewAPIError(response.StatusCode, nil), raw, response.Header)
 	}
-	return core.NewAPIError(response.StatusCode, errors.New(string(bytes)))
+	// This endpoint doesn't have any custom error
+	// types, so we just read the body as-is, and
+	// put it into a normal error.
+	return withErrorDetails(core.NewAPIError(response.StatusCode, errors.New(string(raw))), raw, response.Header)
 }
 
 // isNil is used to determine if the request value is equal to nil (i.e. an interface
@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// requestIDHeaders are the response headers that may carry the ID of the
+// request, in order of preference.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Trace-Id"}
+
+// errorBody covers the error bodies sent by the API: {"error": "...",
+// "code": "..."} for application errors and {"error": {"issues": [...]}}
+// for rejected request schemas.
+type errorBody struct {
+	Error   json.RawMessage `json:"error"`
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Errors  []errorIssue    `json:"errors"`
+	Issues  []errorIssue    `json:"issues"`
+}
+
+type errorIssue struct {
+	Field   string        `json:"field"`
+	Path    []interface{} `json:"path"`
+	Code    string        `json:"code"`
+	Message string        `json:"message"`
+}
+
+type nestedErrorBody struct {
+	Name    string       `json:"name"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Issues  []errorIssue `json:"issues"`
+}
+
+// withErrorDetails fills in the structured fields of the *core.APIError in
+// err's chain from the raw response body and headers, and returns err.
+func withErrorDetails(err error, raw []byte, header http.Header) error {
+	apiError, ok := core.AsAPIError(err)
+	if !ok {
+		return err
+	}
+
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			apiError.RequestID = id
+			break
+		}
+	}
+	apiError.RetryAfter = core.ParseRetryAfter(header.Get("Retry-After"), time.Now())
+
+	var body errorBody
+	if json.Unmarshal(raw, &body) != nil {
+		return err
+	}
+	apiError.Code = body.Code
+	apiError.Message = body.Message
+	issues := append(body.Errors, body.Issues...)
+
+	var message string
+	var nested nestedErrorBody
+	switch {
+	case json.Unmarshal(body.Error, &message) == nil:
+		apiError.Message = message
+	case json.Unmarshal(body.Error, &nested) == nil:
+		if apiError.Code == "" {
+			apiError.Code = nested.Code
+		}
+		if apiError.Message == "" {
+			apiError.Message = nested.Message
+		}
+		issues = append(issues, nested.Issues...)
+		if apiError.Message == "" && len(nested.Issues) > 0 {
+			apiError.Message = "invalid request"
+		}
+	}
+
+	for _, issue := range issues {
+		apiError.FieldErrors = append(apiError.FieldErrors, core.FieldError{
+			Field:   issue.field(),
+			Code:    issue.Code,
+			Message: issue.Message,
+		})
+	}
+
+	return err
+}
+
+// field returns the name of the rejected field, joining nested paths with
+// dots.
+func (i errorIssue) field() string {
+	if i.Field != "" || len(i.Path) == 0 {
+		return i.Field
+	}
+	parts := make([]string, len(i.Path))
+	for n, part := range i.Path {
+		parts[n] = fmt.Sprint(part)
+	}
+	return strings.Join(parts, ".")
+}
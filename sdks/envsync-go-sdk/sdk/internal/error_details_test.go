@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeErrorDetails(t *testing.T) {
+	tests := []struct {
+		description     string
+		giveStatusCode  int
+		giveHeader      http.Header
+		giveBody        string
+		giveDecoder     ErrorDecoder
+		wantCode        string
+		wantMessage     string
+		wantFieldErrors []core.FieldError
+		wantRequestID   string
+		wantRetryAfter  time.Duration
+	}{
+		{
+			description:    "application error",
+			giveStatusCode: http.StatusNotFound,
+			giveHeader:     http.Header{"X-Request-Id": []string{"req-123"}},
+			giveBody:       `{"error": "App not found: 42", "code": "NOT_FOUND"}`,
+			wantCode:       "NOT_FOUND",
+			wantMessage:    "App not found: 42",
+			wantRequestID:  "req-123",
+		},
+		{
+			description:    "schema validation error",
+			giveStatusCode: http.StatusBadRequest,
+			giveBody:       `{"success": false, "error": {"name": "ZodError", "issues": [{"code": "invalid_type", "path": ["env_types", 0, "name"], "message": "Required"}]}}`,
+			wantMessage:    "invalid request",
+			wantFieldErrors: []core.FieldError{
+				{Field: "env_types.0.name", Code: "invalid_type", Message: "Required"},
+			},
+		},
+		{
+			description:    "rate limited",
+			giveStatusCode: http.StatusTooManyRequests,
+			giveHeader:     http.Header{"Retry-After": []string{"7"}},
+			giveBody:       `Too Many Requests`,
+			wantRetryAfter: 7 * time.Second,
+		},
+		{
+			description:    "custom error type",
+			giveStatusCode: http.StatusNotFound,
+			giveHeader:     http.Header{"X-Correlation-Id": []string{"corr-1"}},
+			giveBody:       `{"message": "ID not found"}`,
+			giveDecoder: NewErrorDecoder(ErrorCodes{
+				http.StatusNotFound: func(apiError *core.APIError) error {
+					return &unwrappingError{APIError: apiError}
+				},
+			}),
+			wantMessage:   "ID not found",
+			wantRequestID: "corr-1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			header := test.giveHeader
+			if header == nil {
+				header = http.Header{}
+			}
+			err := decodeError(&http.Response{
+				StatusCode: test.giveStatusCode,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewBufferString(test.giveBody)),
+			}, test.giveDecoder)
+
+			apiError, ok := core.AsAPIError(err)
+			require.True(t, ok)
+			assert.Equal(t, test.giveStatusCode, apiError.StatusCode)
+			assert.Equal(t, test.wantCode, apiError.Code)
+			assert.Equal(t, test.wantMessage, apiError.Message)
+			assert.Equal(t, test.wantFieldErrors, apiError.FieldErrors)
+			assert.Equal(t, test.wantRequestID, apiError.RequestID)
+			assert.Equal(t, test.wantRetryAfter, apiError.RetryAfter)
+		})
+	}
+}
+
+// unwrappingError mirrors the generated error types, which unwrap to their
+// *core.APIError.
+type unwrappingError struct {
+	*core.APIError
+}
+
+func (u *unwrappingError) Unwrap() error {
+	return u.APIError
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		is         func(error) bool
+	}{
+		{http.StatusNotFound, core.IsNotFound},
+		{http.StatusUnauthorized, core.IsUnauthorized},
+		{http.StatusForbidden, core.IsForbidden},
+		{http.StatusConflict, core.IsConflict},
+		{http.StatusTooManyRequests, core.IsRateLimited},
+		{http.StatusBadRequest, core.IsValidation},
+		{http.StatusUnprocessableEntity, core.IsValidation},
+	}
+	for _, test := range tests {
+		t.Run(http.StatusText(test.statusCode), func(t *testing.T) {
+			apiError := core.NewAPIError(test.statusCode, nil)
+			assert.True(t, test.is(apiError))
+			assert.True(t, test.is(fmt.Errorf("wrapped: %w", &NotFoundError{APIError: apiError})))
+			assert.False(t, test.is(core.NewAPIError(http.StatusInternalServerError, nil)))
+		})
+	}
+
+	assert.False(t, core.IsNotFound(nil))
+	assert.EqualError(t, &core.APIError{StatusCode: http.StatusNotFound, RequestID: "req-1"}, "404 (request ID: req-1)")
+}
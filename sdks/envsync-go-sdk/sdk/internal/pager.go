@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// DefaultPageSize is the number of items requested per page when the request
+// does not set one.
+const DefaultPageSize = 100
+
+// Page is a single page of results.
+type Page[T any] struct {
+	Items      []T
+	TotalPages int
+}
+
+// PageFunc fetches the given page, starting at one.
+type PageFunc[T any] func(ctx context.Context, page int) (*Page[T], error)
+
+// Paginate returns an iterator over the items of every page, starting at the
+// given page.
+//
+// The next page is fetched in the background while the items of the current
+// one are consumed. Iteration stops after the last page, on the first error,
+// when the context is cancelled or when the caller breaks out of the loop,
+// in which case the pending request is cancelled before the loop returns.
+func Paginate[T any](ctx context.Context, start int, fetch PageFunc[T]) iter.Seq2[T, error] {
+	if start < 1 {
+		start = 1
+	}
+	return func(yield func(T, error) bool) {
+		var wg sync.WaitGroup
+		ctx, cancel := context.WithCancel(ctx)
+		defer wg.Wait()
+		defer cancel()
+
+		var zero T
+		pending := prefetch(ctx, &wg, start, fetch)
+		for number := start; ; number++ {
+			result := <-pending
+			if result.err != nil {
+				yield(zero, result.err)
+				return
+			}
+
+			page := result.page
+			last := page == nil || len(page.Items) == 0 || number >= page.TotalPages
+			if !last {
+				pending = prefetch(ctx, &wg, number+1, fetch)
+			}
+
+			if page != nil {
+				for _, item := range page.Items {
+					if err := ctx.Err(); err != nil {
+						yield(zero, err)
+						return
+					}
+					if !yield(item, nil) {
+						return
+					}
+				}
+			}
+
+			if last {
+				return
+			}
+		}
+	}
+}
+
+type pageResult[T any] struct {
+	page *Page[T]
+	err  error
+}
+
+// prefetch fetches a page in the background. The returned channel is
+// buffered, so the fetch finishes even if the result is never received.
+func prefetch[T any](ctx context.Context, wg *sync.WaitGroup, number int, fetch PageFunc[T]) <-chan pageResult[T] {
+	results := make(chan pageResult[T], 1)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		page, err := fetch(ctx, number)
+		if err == nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		results <- pageResult[T]{page: page, err: err}
+	}()
+	return results
+}
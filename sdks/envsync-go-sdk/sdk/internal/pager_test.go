@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePages serves pages of consecutive integers and records the requests.
+type fakePages struct {
+	mu         sync.Mutex
+	perPage    int
+	totalPages int
+	requested  []int
+	failOn     int
+	emptyFrom  int
+	block      chan struct{}
+}
+
+func (f *fakePages) fetch(ctx context.Context, page int) (*Page[int], error) {
+	f.mu.Lock()
+	f.requested = append(f.requested, page)
+	f.mu.Unlock()
+
+	if f.block != nil && page > 1 {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if page == f.failOn {
+		return nil, errors.New("page failed")
+	}
+	if page > f.totalPages || (f.emptyFrom > 0 && page >= f.emptyFrom) {
+		return &Page[int]{TotalPages: f.totalPages}, nil
+	}
+	items := make([]int, f.perPage)
+	for i := range items {
+		items[i] = (page-1)*f.perPage + i
+	}
+	return &Page[int]{Items: items, TotalPages: f.totalPages}, nil
+}
+
+func (f *fakePages) pages() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]int(nil), f.requested...)
+}
+
+func TestPaginate(t *testing.T) {
+	t.Run("iterates over every page", func(t *testing.T) {
+		pages := &fakePages{perPage: 3, totalPages: 3}
+
+		var items []int
+		for item, err := range Paginate(context.Background(), 1, pages.fetch) {
+			require.NoError(t, err)
+			items = append(items, item)
+		}
+
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8}, items)
+		assert.Equal(t, []int{1, 2, 3}, pages.pages())
+	})
+
+	t.Run("starts at the given page", func(t *testing.T) {
+		pages := &fakePages{perPage: 2, totalPages: 3}
+
+		var items []int
+		for item, err := range Paginate(context.Background(), 2, pages.fetch) {
+			require.NoError(t, err)
+			items = append(items, item)
+		}
+
+		assert.Equal(t, []int{2, 3, 4, 5}, items)
+	})
+
+	t.Run("stops at an empty page", func(t *testing.T) {
+		pages := &fakePages{perPage: 2, totalPages: 5, emptyFrom: 2}
+
+		count := 0
+		for _, err := range Paginate(context.Background(), 1, pages.fetch) {
+			require.NoError(t, err)
+			count++
+		}
+
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []int{1, 2}, pages.pages())
+	})
+
+	t.Run("reports errors and stops", func(t *testing.T) {
+		pages := &fakePages{perPage: 2, totalPages: 5, failOn: 2}
+
+		var items []int
+		var errs []error
+		for item, err := range Paginate(context.Background(), 1, pages.fetch) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			items = append(items, item)
+		}
+
+		assert.Equal(t, []int{0, 1}, items)
+		require.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], "page failed")
+	})
+
+	t.Run("prefetches the next page", func(t *testing.T) {
+		pages := &fakePages{perPage: 2, totalPages: 3}
+
+		for item, err := range Paginate(context.Background(), 1, pages.fetch) {
+			require.NoError(t, err)
+			if item == 0 {
+				assert.Eventually(t, func() bool {
+					return len(pages.pages()) == 2
+				}, time.Second, time.Millisecond)
+			}
+		}
+	})
+
+	t.Run("cancels the prefetch on early termination", func(t *testing.T) {
+		pages := &fakePages{perPage: 2, totalPages: 3, block: make(chan struct{})}
+
+		for item, err := range Paginate(context.Background(), 1, pages.fetch) {
+			require.NoError(t, err)
+			if item == 1 {
+				break
+			}
+		}
+
+		assert.Equal(t, []int{1, 2}, pages.pages())
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		pages := &fakePages{perPage: 2, totalPages: 3}
+
+		var items []int
+		var lastErr error
+		for item, err := range Paginate(ctx, 1, pages.fetch) {
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			items = append(items, item)
+			cancel()
+		}
+
+		assert.Equal(t, []int{0}, items)
+		assert.ErrorIs(t, lastErr, context.Canceled)
+	})
+}
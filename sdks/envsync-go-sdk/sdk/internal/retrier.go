@@ -1,10 +1,15 @@
 package internal
 
 import (
+	"context"
 	"crypto/rand"
+	"errors"
 	"math/big"
 	"net/http"
 	"time"
+
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+	"github.com/google/uuid"
 )
 
 const (
@@ -27,10 +32,19 @@ func WithMaxAttempts(attempts uint) RetryOption {
 	}
 }
 
-// Retrier retries failed requests a configurable number of times with an
-// exponential back-off between each retry.
+// WithRetryPolicy configures the policy that decides whether and when
+// requests are retried.
+func WithRetryPolicy(policy *core.RetryPolicy) RetryOption {
+	return func(opts *retryOptions) {
+		opts.policy = policy
+	}
+}
+
+// Retrier retries failed requests according to a *core.RetryPolicy with a
+// back-off between each retry.
 type Retrier struct {
 	attempts uint
+	policy   *core.RetryPolicy
 }
 
 // NewRetrier constructs a new *Retrier with the given options, if any.
@@ -39,19 +53,28 @@ func NewRetrier(opts ...RetryOption) *Retrier {
 	for _, opt := range opts {
 		opt(options)
 	}
+	policy := core.DefaultRetryPolicy()
+	if options.policy != nil {
+		policy = options.policy
+	}
 	attempts := uint(defaultRetryAttempts)
+	if policy.MaxAttempts > 0 {
+		attempts = policy.MaxAttempts
+	}
 	if options.attempts > 0 {
 		attempts = options.attempts
 	}
 	return &Retrier{
 		attempts: attempts,
+		policy:   policy,
 	}
 }
 
 // Run issues the request and, upon failure, retries the request if possible.
 //
-// The request will be retried as long as the request is deemed retryable and the
-// number of retry attempts has not grown larger than the configured retry limit.
+// The request will be retried as long as the policy deems it retryable, the
+// number of attempts has not reached the configured limit and the request
+// context leaves enough time for the next attempt.
 func (r *Retrier) Run(
 	fn RetryFunc,
 	request *http.Request,
@@ -62,104 +85,211 @@ func (r *Retrier) Run(
 	for _, opt := range opts {
 		opt(options)
 	}
+	policy := r.policy
 	maxRetryAttempts := r.attempts
+	if options.policy != nil {
+		policy = options.policy
+		if policy.MaxAttempts > 0 {
+			maxRetryAttempts = policy.MaxAttempts
+		}
+	}
 	if options.attempts > 0 {
 		maxRetryAttempts = options.attempts
 	}
-	var (
-		retryAttempt  uint
-		previousError error
-	)
-	return r.run(
-		fn,
-		request,
-		errorDecoder,
-		maxRetryAttempts,
-		retryAttempt,
-		previousError,
-	)
-}
 
-func (r *Retrier) run(
-	fn RetryFunc,
-	request *http.Request,
-	errorDecoder ErrorDecoder,
-	maxRetryAttempts uint,
-	retryAttempt uint,
-	previousError error,
-) (*http.Response, error) {
-	if retryAttempt >= maxRetryAttempts {
-		return nil, previousError
+	if policy.IdempotencyKeys && request.Header.Get(core.IdempotencyKeyHeader) == "" &&
+		!isIdempotentOperation(request.Method, core.Operation(request.Context())) {
+		// The same key is sent on every attempt so the server can
+		// deduplicate them
+		request.Header.Set(core.IdempotencyKeyHeader, uuid.NewString())
 	}
 
-	// If the call has been cancelled, don't issue the request.
-	if err := request.Context().Err(); err != nil {
-		return nil, err
-	}
+	ctx := request.Context()
+	for retryAttempt := uint(0); ; retryAttempt++ {
+		// If the call has been cancelled, don't issue the request.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
-	response, err := fn(request)
-	if err != nil {
-		return nil, err
-	}
+		if retryAttempt > 0 {
+			if err := rewindBody(request); err != nil {
+				return nil, err
+			}
+		}
 
-	if r.shouldRetry(response) {
-		defer response.Body.Close()
+		response, err := fn(request)
 
-		delay, err := r.retryDelay(retryAttempt)
+		var decision core.RetryDecision
+		var retryAfter time.Duration
 		if err != nil {
-			return nil, err
+			// Transport errors may have happened after the server processed
+			// the request, so they are only retried when that is safe
+			decision = core.RetryIdempotent
+			if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				decision = core.RetryNever
+			}
+		} else {
+			decision = core.RetryNever
+			if response.StatusCode < 200 || response.StatusCode >= 300 {
+				decision = policy.StatusDecision(response.StatusCode)
+			}
+			retryAfter = core.ParseRetryAfter(response.Header.Get("Retry-After"), time.Now())
+		}
+
+		if !r.shouldRetry(policy, request, decision, retryAttempt+1, maxRetryAttempts) {
+			return response, err
 		}
 
-		time.Sleep(delay)
+		delay := retryAfter
+		if delay <= 0 {
+			delay, err = r.retryDelay(policy, retryAttempt)
+			if err != nil {
+				if response != nil {
+					response.Body.Close()
+				}
+				return nil, err
+			}
+		}
+		// The server must not be able to stall the call indefinitely
+		if maxDelay := retryDelayLimit(policy); delay > maxDelay {
+			delay = maxDelay
+		}
 
-		return r.run(
-			fn,
-			request,
-			errorDecoder,
-			maxRetryAttempts,
-			retryAttempt+1,
-			decodeError(response, errorDecoder),
-		)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			// The next attempt could not finish in time, so report this one
+			return response, err
+		}
+
+		var previousError error
+		if response != nil {
+			previousError = decodeError(response, errorDecoder)
+			response.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if previousError != nil {
+				return nil, previousError
+			}
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
 	}
+}
 
-	return response, nil
+// shouldRetry returns true if another attempt is allowed for a failure with
+// the given decision.
+func (r *Retrier) shouldRetry(
+	policy *core.RetryPolicy,
+	request *http.Request,
+	decision core.RetryDecision,
+	attempts uint,
+	maxRetryAttempts uint,
+) bool {
+	if attempts >= maxRetryAttempts {
+		return false
+	}
+	// A body that cannot be read again cannot be sent again
+	if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+		return false
+	}
+	switch decision {
+	case core.RetryAlways:
+		return true
+	case core.RetryIdempotent:
+		return policy.RetryNonIdempotent || request.Header.Get(core.IdempotencyKeyHeader) != "" ||
+			isIdempotentOperation(request.Method, core.Operation(request.Context()))
+	}
+	return false
 }
 
-// shouldRetry returns true if the request should be retried based on the given
-// response status code.
-func (r *Retrier) shouldRetry(response *http.Response) bool {
-	return response.StatusCode == http.StatusTooManyRequests ||
-		response.StatusCode == http.StatusRequestTimeout ||
-		response.StatusCode >= http.StatusInternalServerError
+// createOperations are the operations sent with PUT that create resources, so
+// repeating them could create duplicates.
+var createOperations = map[string]bool{
+	"environmentvariables.CreateEnv":       true,
+	"environmentvariables.BatchCreateEnvs": true,
+	"secrets.CreateSecret":                 true,
+	"secrets.BatchCreateSecrets":           true,
+	"gpgkeys.GenerateGpgKey":               true,
+	"gpgkeys.ImportGpgKey":                 true,
+	"onboarding.AcceptOrgInvite":           true,
+	"onboarding.AcceptUserInvite":          true,
 }
 
-// retryDelay calculates the delay time in milliseconds based on the retry attempt.
-func (r *Retrier) retryDelay(retryAttempt uint) (time.Duration, error) {
-	// Apply exponential backoff.
-	delay := minRetryDelay + minRetryDelay*time.Duration(retryAttempt*retryAttempt)
+// isIdempotentOperation reports whether a request can be repeated without
+// applying it twice. The operation takes precedence over the method, since
+// some reads are sent with POST and some creates with PUT.
+func isIdempotentOperation(method, operation string) bool {
+	if readOperations[operation] {
+		return true
+	}
+	if createOperations[operation] {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryDelayLimit returns the longest delay the policy allows before a retry.
+func retryDelayLimit(policy *core.RetryPolicy) time.Duration {
+	if policy.MaxDelay > 0 {
+		return policy.MaxDelay
+	}
+	return core.DefaultMaxRetryDelay
+}
 
-	// Do not allow the number to exceed maxRetryDelay.
-	if delay > maxRetryDelay {
-		delay = maxRetryDelay
+// retryDelay calculates the delay before the given retry from the policy's
+// back-off and jitter.
+func (r *Retrier) retryDelay(policy *core.RetryPolicy, retryAttempt uint) (time.Duration, error) {
+	backoff := policy.Backoff
+	if backoff == nil {
+		backoff = core.DefaultBackoff
+	}
+	delay := backoff(retryAttempt)
+	if delay <= 0 {
+		return 0, nil
 	}
 
-	// Apply some jitter by randomizing the value in the range of 75%-100%.
-	max := big.NewInt(int64(delay / 4))
-	jitter, err := rand.Int(rand.Reader, max)
+	// Apply some jitter by randomizing the value within the configured
+	// fraction of the delay.
+	jitterRange := int64(float64(delay) * min(max(policy.Jitter, 0), 1))
+	if jitterRange <= 0 {
+		return delay, nil
+	}
+	jitter, err := rand.Int(rand.Reader, big.NewInt(jitterRange))
 	if err != nil {
 		return 0, err
 	}
 
 	delay -= time.Duration(jitter.Int64())
 
-	// Never sleep less than the base sleep seconds.
-	if delay < minRetryDelay {
-		delay = minRetryDelay
+	// Never sleep less than the base delay of the first retry.
+	if base := backoff(0); delay < base {
+		delay = base
 	}
 
 	return delay, nil
 }
 
+// rewindBody resets the request body before it is sent again.
+func rewindBody(request *http.Request) error {
+	if request.GetBody == nil || request.Body == nil || request.Body == http.NoBody {
+		return nil
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return err
+	}
+	request.Body = body
+	return nil
+}
+
 type retryOptions struct {
 	attempts uint
+	policy   *core.RetryPolicy
 }
@@ -3,6 +3,7 @@ package internal
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -209,3 +210,283 @@ var expectedRetryDurations = []time.Duration{
 	5000 * time.Millisecond,
 	5000 * time.Millisecond,
 }
+
+func TestRetrierPolicy(t *testing.T) {
+	fastPolicy := func() *core.RetryPolicy {
+		policy := core.DefaultRetryPolicy()
+		policy.MaxAttempts = 3
+		policy.Backoff = core.ConstantBackoff(time.Millisecond)
+		return policy
+	}
+
+	tests := []struct {
+		description  string
+		method       string
+		operation    string
+		policy       *core.RetryPolicy
+		statusCodes  []int
+		wantAttempts int
+		wantStatus   int
+	}{
+		{
+			description:  "non-idempotent requests are not retried on 5xx by default",
+			method:       http.MethodPost,
+			policy:       fastPolicy(),
+			statusCodes:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantAttempts: 1,
+			wantStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			description:  "non-idempotent requests are retried on 429",
+			method:       http.MethodPost,
+			policy:       fastPolicy(),
+			statusCodes:  []int{http.StatusTooManyRequests, http.StatusOK},
+			wantAttempts: 2,
+		},
+		{
+			description:  "create operations sent with PUT are not retried on 5xx",
+			method:       http.MethodPut,
+			operation:    "environmentvariables.BatchCreateEnvs",
+			policy:       fastPolicy(),
+			statusCodes:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantAttempts: 1,
+			wantStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			description:  "updates sent with PUT are retried on 5xx",
+			method:       http.MethodPut,
+			operation:    "webhooks.UpdateWebhook",
+			policy:       fastPolicy(),
+			statusCodes:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantAttempts: 2,
+		},
+		{
+			description:  "reads sent with POST are retried on 5xx",
+			method:       http.MethodPost,
+			operation:    "environmentvariables.GetEnvs",
+			policy:       fastPolicy(),
+			statusCodes:  []int{http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 2,
+		},
+		{
+			description: "non-idempotent requests are retried when allowed",
+			method:      http.MethodPost,
+			policy: func() *core.RetryPolicy {
+				policy := fastPolicy()
+				policy.RetryNonIdempotent = true
+				return policy
+			}(),
+			statusCodes:  []int{http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 2,
+		},
+		{
+			description: "status rules override the defaults",
+			method:      http.MethodGet,
+			policy: func() *core.RetryPolicy {
+				policy := fastPolicy()
+				policy.StatusRules = map[int]core.RetryDecision{
+					http.StatusConflict:            core.RetryAlways,
+					http.StatusInternalServerError: core.RetryNever,
+				}
+				return policy
+			}(),
+			statusCodes:  []int{http.StatusConflict, http.StatusInternalServerError, http.StatusOK},
+			wantAttempts: 2,
+			wantStatus:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var attempts int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.statusCodes[attempts])
+				attempts++
+			}))
+			defer server.Close()
+
+			caller := NewCaller(&CallerParams{Client: server.Client()})
+			err := caller.Call(context.Background(), &CallParams{
+				Operation:          tc.operation,
+				URL:                server.URL,
+				Method:             tc.method,
+				RetryPolicy:        tc.policy,
+				ResponseIsOptional: true,
+			})
+
+			assert.Equal(t, tc.wantAttempts, attempts)
+			if tc.wantStatus != 0 {
+				var apiError *core.APIError
+				require.ErrorAs(t, err, &apiError)
+				assert.Equal(t, tc.wantStatus, apiError.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRetrierIdempotencyKeys(t *testing.T) {
+	tests := []struct {
+		description string
+		method      string
+		operation   string
+		wantKey     bool
+	}{
+		{"POST creates carry a key", http.MethodPost, "applications.CreateApp", true},
+		{"PUT creates carry a key", http.MethodPut, "environmentvariables.BatchCreateEnvs", true},
+		{"POST reads carry no key", http.MethodPost, "environmentvariables.GetEnvs", false},
+		{"PUT updates carry no key", http.MethodPut, "webhooks.UpdateWebhook", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var keys []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				keys = append(keys, r.Header.Get(core.IdempotencyKeyHeader))
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.JSONEq(t, `{"id":"1"}`, string(body))
+				if len(keys) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+
+			policy := core.DefaultRetryPolicy()
+			policy.Backoff = core.ConstantBackoff(time.Millisecond)
+			policy.IdempotencyKeys = true
+
+			caller := NewCaller(&CallerParams{Client: server.Client(), RetryPolicy: policy})
+			err := caller.Call(context.Background(), &CallParams{
+				Operation:          tc.operation,
+				URL:                server.URL,
+				Method:             tc.method,
+				Request:            &Request{Id: "1"},
+				ResponseIsOptional: true,
+			})
+			require.NoError(t, err)
+
+			// Both kinds are retried: one because of its key, the other
+			// because it is idempotent
+			require.Len(t, keys, 2)
+			if !tc.wantKey {
+				assert.Empty(t, keys[0])
+				return
+			}
+			assert.NotEmpty(t, keys[0])
+			assert.Equal(t, keys[0], keys[1], "every attempt must carry the same key")
+		})
+	}
+}
+
+func TestRetrierHonorsRetryAfter(t *testing.T) {
+	var timestamps []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamps = append(timestamps, time.Now())
+		if len(timestamps) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	policy := core.DefaultRetryPolicy()
+	policy.Backoff = core.ConstantBackoff(time.Millisecond)
+
+	caller := NewCaller(&CallerParams{Client: server.Client(), RetryPolicy: policy})
+	err := caller.Call(context.Background(), &CallParams{
+		URL:                server.URL,
+		Method:             http.MethodGet,
+		ResponseIsOptional: true,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, timestamps, 2)
+	assert.GreaterOrEqual(t, timestamps[1].Sub(timestamps[0]), time.Second)
+}
+
+func TestRetrierCapsRetryAfter(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	policy := core.DefaultRetryPolicy()
+	policy.MaxDelay = 10 * time.Millisecond
+
+	start := time.Now()
+	caller := NewCaller(&CallerParams{Client: server.Client(), RetryPolicy: policy})
+	err := caller.Call(context.Background(), &CallParams{
+		URL:                server.URL,
+		Method:             http.MethodGet,
+		ResponseIsOptional: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetrierRespectsContextDeadline(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	caller := NewCaller(&CallerParams{Client: server.Client()})
+	err := caller.Call(ctx, &CallParams{
+		URL:                server.URL,
+		Method:             http.MethodGet,
+		MaxAttempts:        5,
+		ResponseIsOptional: true,
+	})
+
+	var apiError *core.APIError
+	require.ErrorAs(t, err, &apiError)
+	assert.Equal(t, http.StatusServiceUnavailable, apiError.StatusCode)
+	assert.Equal(t, 1, attempts, "a retry that cannot finish before the deadline must not be attempted")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetrierRetriesTransportErrors(t *testing.T) {
+	var attempts int
+	client := &fakeHTTPClient{do: func(r *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}}
+
+	policy := core.DefaultRetryPolicy()
+	policy.Backoff = core.ConstantBackoff(time.Millisecond)
+
+	caller := NewCaller(&CallerParams{Client: client, RetryPolicy: policy})
+	err := caller.Call(context.Background(), &CallParams{
+		URL:                "http://envsync.invalid",
+		Method:             http.MethodGet,
+		ResponseIsOptional: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+type fakeHTTPClient struct {
+	do func(*http.Request) (*http.Response, error)
+}
+
+func (f *fakeHTTPClient) Do(r *http.Request) (*http.Response, error) {
+	return f.do(r)
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	core "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// redacted replaces scrubbed values in logged bodies.
+const redacted = "[REDACTED]"
+
+// sensitiveFields are the JSON fields whose values are scrubbed from logged
+// bodies. Variables and secrets both carry their content in "value".
+var sensitiveFields = map[string]bool{
+	"value":               true,
+	"secret":              true,
+	"password":            true,
+	"passphrase":          true,
+	"token":               true,
+	"access_token":        true,
+	"api_key":             true,
+	"private_key":         true,
+	"key_pem":             true,
+	"armored_private_key": true,
+	"data":                true,
+}
+
+// Logging logs every attempt of a request to logger with its operation,
+// method, path, status, duration and request ID. Failed attempts are logged
+// as warnings and errors.
+//
+// When debug logging is enabled, request and response bodies are logged too,
+// with the values of fields such as "value", "password" and "private_key"
+// replaced by "[REDACTED]". Headers, which carry credentials, are never
+// logged.
+func Logging(logger *slog.Logger) core.Middleware {
+	return func(next core.RoundTripFunc) core.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			debug := logger.Enabled(ctx, slog.LevelDebug)
+
+			attrs := []slog.Attr{
+				slog.String("operation", core.Operation(ctx)),
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+			}
+			if debug && req.Body != nil {
+				body, err := io.ReadAll(req.Body)
+				req.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				attrs = append(attrs, slog.String("request_body", Scrub(body)))
+			}
+
+			start := time.Now()
+			resp, err := next(req)
+			attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				logger.LogAttrs(ctx, slog.LevelError, "envsync request failed", attrs...)
+				return nil, err
+			}
+
+			attrs = append(attrs, slog.Int("status", resp.StatusCode))
+			if requestID := resp.Header.Get("X-Request-Id"); requestID != "" {
+				attrs = append(attrs, slog.String("request_id", requestID))
+			}
+			if debug {
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				resp.Body = io.NopCloser(bytes.NewReader(body))
+				attrs = append(attrs, slog.String("response_body", Scrub(body)))
+			}
+
+			level := slog.LevelInfo
+			if resp.StatusCode >= http.StatusBadRequest {
+				level = slog.LevelWarn
+			}
+			logger.LogAttrs(context.WithoutCancel(ctx), level, "envsync request", attrs...)
+			return resp, nil
+		}
+	}
+}
+
+// Scrub returns a JSON body with the values of sensitive fields replaced by
+// "[REDACTED]". Bodies that are not JSON are replaced by their size.
+func Scrub(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	scrubbed, err := json.Marshal(scrubValue(value))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	return string(scrubbed)
+}
+
+func scrubValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveFields[strings.ToLower(key)] {
+				if field != nil {
+					v[key] = redacted
+				}
+				continue
+			}
+			v[key] = scrubValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = scrubValue(item)
+		}
+	}
+	return value
+}
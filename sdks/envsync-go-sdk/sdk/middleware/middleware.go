@@ -0,0 +1,52 @@
+// Package middleware provides middleware for the EnvSync client, installed
+// with option.WithMiddleware:
+//
+//	client := client.NewClient(
+//		option.WithMiddleware(
+//			middleware.Tracing(nil),
+//			middleware.Metrics(nil),
+//			middleware.Logging(slog.Default()),
+//			middleware.Timeout(10*time.Second),
+//		),
+//	)
+//
+// Middleware runs once per attempt, so retried requests are logged, traced
+// and timed out individually.
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	core "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// Timeout limits the time an attempt may take, including reading the
+// response body, to d. Retries get a new timeout each.
+func Timeout(d time.Duration) core.Middleware {
+	return func(next core.RoundTripFunc) core.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			ctx, cancel := context.WithTimeout(req.Context(), d)
+			resp, err := next(req.WithContext(ctx))
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			// The body is read after the middleware returns
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+	}
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
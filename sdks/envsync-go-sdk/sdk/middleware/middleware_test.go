@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	core "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/envsynctest"
+	"github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMiddlewareOrderAndOperation(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+
+	var calls []string
+	record := func(name string) core.Middleware {
+		return func(next core.RoundTripFunc) core.RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+":"+core.Operation(req.Context()))
+				return next(req)
+			}
+		}
+	}
+
+	client := server.Client(option.WithMiddleware(record("client1"), record("client2")))
+	_, err := client.Applications.GetApps(context.Background(), option.WithMiddleware(record("call")))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"client1:applications.GetApps",
+		"client2:applications.GetApps",
+		"call:applications.GetApps",
+	}, calls)
+}
+
+func TestMiddlewareRunsPerAttempt(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+	server.Inject(envsynctest.Fault{StatusCode: http.StatusServiceUnavailable, Times: 1})
+
+	var attempts int
+	client := server.Client(
+		option.WithRetryPolicy(&option.RetryPolicy{MaxAttempts: 2, Backoff: core.ConstantBackoff(time.Millisecond)}),
+		option.WithMiddleware(func(next core.RoundTripFunc) core.RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return next(req)
+			}
+		}),
+	)
+	_, err := client.Applications.GetApps(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestLogging(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+	appID, envTypeIDs := server.AddApp("api", "prod")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := server.Client(option.WithToken("t0ken"), option.WithMiddleware(Logging(logger)))
+
+	_, err := client.EnvironmentVariables.CreateEnv(context.Background(), &sdk.CreateEnvRequest{
+		AppId:     appID,
+		EnvTypeId: envTypeIDs[0],
+		Key:       "DB_PASSWORD",
+		Value:     "hunter2",
+	})
+	require.NoError(t, err)
+	_, err = client.EnvironmentVariables.GetEnvs(context.Background(), &sdk.GetEnvRequest{AppId: appID, EnvTypeId: envTypeIDs[0]})
+	require.NoError(t, err)
+
+	logs := buf.String()
+	assert.Contains(t, logs, `"operation":"environmentvariables.CreateEnv"`)
+	assert.Contains(t, logs, `"operation":"environmentvariables.GetEnvs"`)
+	assert.Contains(t, logs, `"request_id"`)
+	assert.Contains(t, logs, "DB_PASSWORD")
+	assert.Contains(t, logs, redacted)
+	assert.NotContains(t, logs, "hunter2")
+	assert.NotContains(t, logs, "t0ken")
+}
+
+func TestLoggingWithoutDebug(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	_, err := server.Client(option.WithMiddleware(Logging(logger))).Applications.GetApp(context.Background(), "missing")
+	require.Error(t, err)
+
+	assert.Contains(t, buf.String(), "level=WARN")
+	assert.Contains(t, buf.String(), "status=404")
+	assert.NotContains(t, buf.String(), "response_body")
+}
+
+func TestScrub(t *testing.T) {
+	assert.Equal(t,
+		`{"envs":[{"key":"A","value":"[REDACTED]"}],"private_key":"[REDACTED]","public_key":"pk"}`,
+		Scrub([]byte(`{"envs":[{"key":"A","value":"1"}],"private_key":"secret","public_key":"pk"}`)),
+	)
+	assert.Equal(t, `{"value":null}`, Scrub([]byte(`{"value":null}`)))
+	assert.Equal(t, "[9 bytes]", Scrub([]byte("not json!")))
+	assert.Equal(t, "", Scrub(nil))
+}
+
+func TestTimeout(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+	client := server.Client(option.WithMaxAttempts(1), option.WithMiddleware(Timeout(20*time.Millisecond)))
+
+	_, err := client.Applications.GetApps(context.Background())
+	require.NoError(t, err)
+
+	server.Inject(envsynctest.Fault{Latency: time.Second})
+	_, err = client.Applications.GetApps(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTracing(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client := server.Client(option.WithMaxAttempts(1), option.WithMiddleware(Tracing(provider)))
+	_, err := client.Applications.GetApps(context.Background())
+	require.NoError(t, err)
+	_, err = client.Applications.GetApp(context.Background(), "missing")
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "applications.GetApps", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("http.response.status_code", 200))
+	assert.Equal(t, "applications.GetApp", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+type recordingHistogram struct {
+	noop.Float64Histogram
+
+	mu      sync.Mutex
+	records []attribute.Set
+}
+
+func (h *recordingHistogram) Record(_ context.Context, _ float64, opts ...metric.RecordOption) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, metric.NewRecordConfig(opts).Attributes())
+}
+
+type recordingMeter struct {
+	noop.Meter
+	histogram *recordingHistogram
+}
+
+func (m recordingMeter) Float64Histogram(string, ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return m.histogram, nil
+}
+
+type recordingMeterProvider struct {
+	noop.MeterProvider
+	meter recordingMeter
+}
+
+func (p recordingMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return p.meter
+}
+
+func TestMetrics(t *testing.T) {
+	server := envsynctest.NewServer()
+	defer server.Close()
+	histogram := &recordingHistogram{}
+	provider := recordingMeterProvider{meter: recordingMeter{histogram: histogram}}
+
+	client := server.Client(option.WithMaxAttempts(1), option.WithMiddleware(Metrics(provider)))
+	_, err := client.Applications.GetApp(context.Background(), "missing")
+	require.Error(t, err)
+
+	require.Len(t, histogram.records, 1)
+	attrs := histogram.records[0]
+	operation, _ := attrs.Value("envsync.operation")
+	assert.Equal(t, "applications.GetApp", operation.AsString())
+	status, _ := attrs.Value("http.response.status_code")
+	assert.Equal(t, int64(404), status.AsInt64())
+	errorType, _ := attrs.Value("error.type")
+	assert.True(t, strings.HasPrefix(errorType.AsString(), "404"))
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	core "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// instrumentationName identifies the spans and metrics of the SDK.
+const instrumentationName = "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/middleware"
+
+// operationKey is the attribute holding the name of the API operation.
+const operationKey = attribute.Key("envsync.operation")
+
+// Tracing records a client span for every attempt of a request, named after
+// the API operation, and propagates the trace context to the API with the
+// global propagator. A nil provider uses the global tracer provider.
+func Tracing(provider trace.TracerProvider) core.Middleware {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	tracer := provider.Tracer(instrumentationName)
+
+	return func(next core.RoundTripFunc) core.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			operation := core.Operation(req.Context())
+			name := operation
+			if name == "" {
+				name = req.Method
+			}
+
+			ctx, span := tracer.Start(req.Context(), name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					operationKey.String(operation),
+					semconv.HTTPRequestMethodKey.String(req.Method),
+					semconv.URLFull(req.URL.Redacted()),
+					semconv.ServerAddress(req.URL.Hostname()),
+				),
+			)
+			defer span.End()
+
+			req = req.WithContext(ctx)
+			otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next(req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return nil, err
+			}
+
+			span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+			if resp.StatusCode >= http.StatusBadRequest {
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+			}
+			return resp, nil
+		}
+	}
+}
+
+// Metrics records the duration of every attempt of a request in the
+// http.client.request.duration histogram, by operation, method and status. A
+// nil provider uses the global meter provider.
+func Metrics(provider metric.MeterProvider) core.Middleware {
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	duration, err := provider.Meter(instrumentationName).Float64Histogram(
+		"http.client.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of EnvSync API requests."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return func(next core.RoundTripFunc) core.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+
+			attrs := []attribute.KeyValue{
+				operationKey.String(core.Operation(req.Context())),
+				semconv.HTTPRequestMethodKey.String(req.Method),
+			}
+			if err != nil {
+				attrs = append(attrs, semconv.ErrorTypeKey.String("transport"))
+			} else {
+				attrs = append(attrs, semconv.HTTPResponseStatusCode(resp.StatusCode))
+				if resp.StatusCode >= http.StatusBadRequest {
+					attrs = append(attrs, semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode)))
+				}
+			}
+			if duration != nil {
+				duration.Record(req.Context(), time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+			}
+			return resp, err
+		}
+	}
+}
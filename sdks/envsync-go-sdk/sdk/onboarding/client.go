@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -61,10 +64,13 @@ func (c *Client) CreateOrgInvite(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "onboarding.CreateOrgInvite",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -110,10 +116,13 @@ func (c *Client) GetOrgInviteByCode(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "onboarding.GetOrgInviteByCode",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -160,10 +169,13 @@ func (c *Client) AcceptOrgInvite(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "onboarding.AcceptOrgInvite",
 			URL:             endpointURL,
 			Method:          http.MethodPut,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -209,10 +221,13 @@ func (c *Client) GetUserInviteByCode(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "onboarding.GetUserInviteByCode",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -259,10 +274,13 @@ func (c *Client) UpdateUserInvite(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "onboarding.UpdateUserInvite",
 			URL:             endpointURL,
 			Method:          http.MethodPatch,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -310,10 +328,13 @@ func (c *Client) AcceptUserInvite(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "onboarding.AcceptUserInvite",
 			URL:             endpointURL,
 			Method:          http.MethodPut,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -355,10 +376,13 @@ func (c *Client) GetAllUserInvites(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "onboarding.GetAllUserInvites",
 			URL:             endpointURL,
 			Method:          http.MethodGet,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -401,10 +425,13 @@ func (c *Client) CreateUserInvite(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "onboarding.CreateUserInvite",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -450,10 +477,13 @@ func (c *Client) DeleteUserInvite(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "onboarding.DeleteUserInvite",
 			URL:             endpointURL,
 			Method:          http.MethodDelete,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -0,0 +1,30 @@
+package option
+
+import (
+	core "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// WithBatchSize sets the number of items sent per request by the chunked
+// batch methods, such as BatchCreateEnvsChunked. It is capped at 100, the
+// largest batch the API accepts.
+func WithBatchSize(size int) *core.BatchOption {
+	return &core.BatchOption{
+		Size: size,
+	}
+}
+
+// WithBatchConcurrency sets the number of requests the chunked batch methods
+// send at the same time. It defaults to 4.
+func WithBatchConcurrency(concurrency int) *core.BatchOption {
+	return &core.BatchOption{
+		Concurrency: concurrency,
+	}
+}
+
+// WithBatchResume makes the chunked batch methods skip the keys that
+// succeeded in result, so a partially failed batch can be sent again as is.
+func WithBatchResume(result *core.BatchResult) *core.BatchOption {
+	return &core.BatchOption{
+		Resume: result,
+	}
+}
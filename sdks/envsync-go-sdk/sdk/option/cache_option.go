@@ -0,0 +1,33 @@
+package option
+
+import (
+	"time"
+
+	core "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// Cache configures the response cache of a client.
+type Cache = core.Cache
+
+// WithCache caches the responses of read endpoints in memory, shared by all
+// the endpoints of a client created with client.NewClient. Responses are
+// revalidated with the server using their ETag or Last-Modified header when
+// it sends them, and otherwise served from the cache for ttl. A successful
+// request that changes data clears the cache.
+func WithCache(ttl time.Duration) *core.CacheOption {
+	return &core.CacheOption{
+		Cache: &core.Cache{
+			Store: core.NewMemoryCacheStore(),
+			TTL:   ttl,
+		},
+	}
+}
+
+// WithResponseCache caches the responses of read endpoints in the given
+// cache, for example one backed by core.NewFileCacheStore so responses are
+// reused across processes.
+func WithResponseCache(cache *Cache) *core.CacheOption {
+	return &core.CacheOption{
+		Cache: cache,
+	}
+}
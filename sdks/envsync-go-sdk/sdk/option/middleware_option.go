@@ -0,0 +1,22 @@
+package option
+
+import (
+	core "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// RoundTripFunc sends an HTTP request and returns its response.
+type RoundTripFunc = core.RoundTripFunc
+
+// Middleware wraps the sending of each HTTP request.
+type Middleware = core.Middleware
+
+// WithMiddleware adds middleware that observes or modifies every attempt of
+// a request. The first middleware given is the outermost, and middleware set
+// on the client wraps middleware set on an individual request. The name of
+// the API operation is available from the request context with
+// core.Operation.
+func WithMiddleware(middleware ...Middleware) *core.MiddlewareOption {
+	return &core.MiddlewareOption{
+		Middleware: middleware,
+	}
+}
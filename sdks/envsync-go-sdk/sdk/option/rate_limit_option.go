@@ -0,0 +1,35 @@
+package option
+
+import (
+	core "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// RateLimit configures a rate limiter created with core.NewRateLimiter.
+type RateLimit = core.RateLimit
+
+// WithRateLimit limits the client to rps requests per second on average, with
+// bursts of up to burst requests. The limit is shared by all the endpoints of
+// a client created with client.NewClient, and requests are also held back
+// when the server reports that its rate limit is exhausted.
+func WithRateLimit(rps float64, burst int) *core.RateLimitOption {
+	return &core.RateLimitOption{
+		Limiter: core.NewRateLimiter(core.RateLimit{RequestsPerSecond: rps, Burst: burst}),
+	}
+}
+
+// WithMaxConcurrency limits the client to n requests in flight at the same
+// time, shared by all the endpoints of a client created with
+// client.NewClient.
+func WithMaxConcurrency(n int) *core.RateLimitOption {
+	return &core.RateLimitOption{
+		Limiter: core.NewRateLimiter(core.RateLimit{MaxConcurrent: n}),
+	}
+}
+
+// WithRateLimiter applies a rate limiter created with core.NewRateLimiter, so
+// it can be shared by several clients or given a custom clock.
+func WithRateLimiter(limiter *core.RateLimiter) *core.RateLimitOption {
+	return &core.RateLimitOption{
+		Limiter: limiter,
+	}
+}
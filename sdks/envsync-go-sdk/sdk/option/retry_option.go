@@ -0,0 +1,31 @@
+package option
+
+import (
+	core "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// RetryPolicy configures how failed requests are retried.
+type RetryPolicy = core.RetryPolicy
+
+// WithRetryPolicy configures how failed requests are retried. MaxAttempts,
+// Backoff and MaxDelay keep their defaults when left at zero, but a zero
+// Jitter turns jitter off, so start from core.DefaultRetryPolicy to keep it.
+func WithRetryPolicy(policy *RetryPolicy) *core.RetryPolicyOption {
+	copied := *policy
+	if policy.StatusRules != nil {
+		copied.StatusRules = make(map[int]core.RetryDecision, len(policy.StatusRules))
+		for status, decision := range policy.StatusRules {
+			copied.StatusRules[status] = decision
+		}
+	}
+	return &core.RetryPolicyOption{
+		RetryPolicy: &copied,
+	}
+}
+
+// WithIdempotencyKeys adds an Idempotency-Key header to requests of
+// non-idempotent operations so they are retried safely. It keeps the retry
+// policy configured by earlier options, and WithRetryPolicy keeps it enabled.
+func WithIdempotencyKeys() *core.IdempotencyKeysOption {
+	return &core.IdempotencyKeysOption{}
+}
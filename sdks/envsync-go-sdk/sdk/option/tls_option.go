@@ -0,0 +1,38 @@
+package option
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	core "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+)
+
+// WithClientCertificate authenticates with mutual TLS using the PEM-encoded
+// certificate and private key, such as a member certificate issued by the
+// organization CA. The certificate may be followed by its intermediate CAs.
+// If the certificate or key is invalid, every request fails with the parse
+// error.
+//
+// TLS options configure a copy of the transport of the HTTP client, so they
+// must come after WithHTTPClient, whose client must be an *http.Client with
+// an *http.Transport or the default transport. The copy is made once per
+// client.NewClient and shared by all of its API clients.
+func WithClientCertificate(certPEM, keyPEM []byte) *core.TLSOption {
+	return core.NewClientCertificateOption(certPEM, keyPEM)
+}
+
+// WithClientKeyPair authenticates with mutual TLS using a loaded certificate,
+// for example one backed by a hardware key.
+func WithClientKeyPair(cert tls.Certificate) *core.TLSOption {
+	return &core.TLSOption{
+		Certificates: []tls.Certificate{cert},
+	}
+}
+
+// WithRootCAs verifies the server certificate against the given pool instead
+// of the system roots, for servers behind a private CA.
+func WithRootCAs(pool *x509.CertPool) *core.TLSOption {
+	return &core.TLSOption{
+		RootCAs: pool,
+	}
+}
@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	context "context"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	core "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/core"
+	internal "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/internal"
+	option "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+)
+
+// BatchCreateSecretsChunked creates any number of secrets, split into batches of
+// option.WithBatchSize sent option.WithBatchConcurrency at a time. The
+// result lists the keys that were created and those that failed, and the
+// error is a *core.BatchError if any failed.
+func (c *Client) BatchCreateSecretsChunked(
+	ctx context.Context,
+	request *sdk.BatchCreateSecretsRequest,
+	opts ...option.RequestOption,
+) (*core.BatchResult, error) {
+	result := internal.Batch(ctx, request.GetEnvs(), (*sdk.BatchCreateSecretsRequestEnvsItem).GetKey, core.NewRequestOptions(opts...).Batch,
+		func(ctx context.Context, chunk []*sdk.BatchCreateSecretsRequestEnvsItem) error {
+			_, err := c.BatchCreateSecrets(ctx, &sdk.BatchCreateSecretsRequest{
+				AppId:     request.GetAppId(),
+				EnvTypeId: request.GetEnvTypeId(),
+				Envs:      chunk,
+			}, opts...)
+			return err
+		},
+	)
+	return result, result.Err()
+}
+
+// BatchUpdateSecretsChunked updates any number of secrets in batches, like
+// BatchCreateSecretsChunked.
+func (c *Client) BatchUpdateSecretsChunked(
+	ctx context.Context,
+	request *sdk.BatchCreateSecretsRequest,
+	opts ...option.RequestOption,
+) (*core.BatchResult, error) {
+	result := internal.Batch(ctx, request.GetEnvs(), (*sdk.BatchCreateSecretsRequestEnvsItem).GetKey, core.NewRequestOptions(opts...).Batch,
+		func(ctx context.Context, chunk []*sdk.BatchCreateSecretsRequestEnvsItem) error {
+			_, err := c.BatchUpdateSecrets(ctx, &sdk.BatchCreateSecretsRequest{
+				AppId:     request.GetAppId(),
+				EnvTypeId: request.GetEnvTypeId(),
+				Envs:      chunk,
+			}, opts...)
+			return err
+		},
+	)
+	return result, result.Err()
+}
+
+// DeleteBatchSecretsChunked deletes any number of secrets in batches, like
+// BatchCreateSecretsChunked.
+func (c *Client) DeleteBatchSecretsChunked(
+	ctx context.Context,
+	request *sdk.BatchDeleteSecretsRequest,
+	opts ...option.RequestOption,
+) (*core.BatchResult, error) {
+	var keys []string
+	if request != nil {
+		keys = request.Keys
+	}
+	result := internal.Batch(ctx, keys, func(key string) string { return key }, core.NewRequestOptions(opts...).Batch,
+		func(ctx context.Context, chunk []string) error {
+			_, err := c.DeleteBatchSecrets(ctx, &sdk.BatchDeleteSecretsRequest{
+				AppId:     request.AppId,
+				EnvTypeId: request.EnvTypeId,
+				Keys:      chunk,
+			}, opts...)
+			return err
+		},
+	)
+	return result, result.Err()
+}
@@ -25,6 +25,9 @@ func NewClient(opts ...option.RequestOption) *Client {
 			&internal.CallerParams{
 				Client:      options.HTTPClient,
 				MaxAttempts: options.MaxAttempts,
+				RetryPolicy: options.RetryPolicy,
+				Middleware:  options.Middleware,
+				Cache:       options.Cache,
 			},
 		),
 		header: options.ToHeader(),
@@ -61,10 +64,13 @@ func (c *Client) GetSecrets(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "secrets.GetSecrets",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -108,10 +114,13 @@ func (c *Client) DeleteSecret(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "secrets.DeleteSecret",
 			URL:             endpointURL,
 			Method:          http.MethodDelete,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -159,10 +168,13 @@ func (c *Client) GetSecret(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "secrets.GetSecret",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -210,10 +222,13 @@ func (c *Client) UpdateSecret(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "secrets.UpdateSecret",
 			URL:             endpointURL,
 			Method:          http.MethodPatch,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -257,10 +272,13 @@ func (c *Client) CreateSecret(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "secrets.CreateSecret",
 			URL:             endpointURL,
 			Method:          http.MethodPut,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -304,10 +322,13 @@ func (c *Client) BatchCreateSecrets(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "secrets.BatchCreateSecrets",
 			URL:             endpointURL,
 			Method:          http.MethodPut,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -351,10 +372,13 @@ func (c *Client) DeleteBatchSecrets(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "secrets.DeleteBatchSecrets",
 			URL:             endpointURL,
 			Method:          http.MethodDelete,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -398,10 +422,13 @@ func (c *Client) BatchUpdateSecrets(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "secrets.BatchUpdateSecrets",
 			URL:             endpointURL,
 			Method:          http.MethodPatch,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
@@ -445,10 +472,13 @@ func (c *Client) RevealSecrets(
 	if err := c.caller.Call(
 		ctx,
 		&internal.CallParams{
+			Operation:       "secrets.RevealSecrets",
 			URL:             endpointURL,
 			Method:          http.MethodPost,
 			Headers:         headers,
 			MaxAttempts:     options.MaxAttempts,
+			RetryPolicy:     options.RetryPolicy,
+			Middleware:      options.Middleware,
 			BodyProperties:  options.BodyProperties,
 			QueryParameters: options.QueryParameters,
 			Client:          options.HTTPClient,
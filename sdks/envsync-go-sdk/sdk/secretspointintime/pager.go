@@ -0,0 +1,44 @@
+package secretspointintime
+
+import (
+	context "context"
+	iter "iter"
+
+	sdk "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk"
+	internal "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/internal"
+	option "github.com/EnvSync-Cloud/envsync/sdks/envsync-go-sdk/sdk/option"
+)
+
+// AllSecretHistory iterates over the secret point-in-time history of every
+// page, starting at request.Page. request.PerPage sets the page size and
+// defaults to internal.DefaultPageSize.
+func (c *Client) AllSecretHistory(
+	ctx context.Context,
+	request *sdk.SecretHistoryRequest,
+	opts ...option.RequestOption,
+) iter.Seq2[*sdk.SecretHistoryResponsePitsItem, error] {
+	query := sdk.SecretHistoryRequest{}
+	if request != nil {
+		query = *request
+	}
+	start := 1
+	if query.Page != nil {
+		start = *query.Page
+	}
+	if query.PerPage == nil || *query.PerPage <= 0 {
+		query.PerPage = sdk.Int(internal.DefaultPageSize)
+	}
+
+	return internal.Paginate(ctx, start, func(ctx context.Context, page int) (*internal.Page[*sdk.SecretHistoryResponsePitsItem], error) {
+		pageQuery := query
+		pageQuery.Page = sdk.Int(page)
+		response, err := c.GetSecretHistory(ctx, &pageQuery, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &internal.Page[*sdk.SecretHistoryResponsePitsItem]{
+			Items:      response.GetPits(),
+			TotalPages: response.GetTotalPages(),
+		}, nil
+	})
+}
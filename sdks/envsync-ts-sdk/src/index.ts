@@ -77,6 +77,7 @@ export type { GpgKeysResponse } from './models/GpgKeysResponse';
 export { GrantAccessRequest } from './models/GrantAccessRequest';
 export type { ImportGpgKeyRequest } from './models/ImportGpgKeyRequest';
 export type { InitOrgCARequest } from './models/InitOrgCARequest';
+export type { IssueMemberCertFromCSRRequest } from './models/IssueMemberCertFromCSRRequest';
 export type { IssueMemberCertRequest } from './models/IssueMemberCertRequest';
 export type { LoginUrlResponse } from './models/LoginUrlResponse';
 export type { MemberCertResponse } from './models/MemberCertResponse';
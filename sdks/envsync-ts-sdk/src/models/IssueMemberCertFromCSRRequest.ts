@@ -0,0 +1,12 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+export type IssueMemberCertFromCSRRequest = {
+    csr_pem: string;
+    member_email: string;
+    role: string;
+    description?: string;
+    metadata?: Record<string, string>;
+};
+
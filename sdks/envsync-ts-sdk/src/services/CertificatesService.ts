@@ -5,6 +5,7 @@
 import type { CertificateListResponse } from '../models/CertificateListResponse';
 import type { CRLResponse } from '../models/CRLResponse';
 import type { InitOrgCARequest } from '../models/InitOrgCARequest';
+import type { IssueMemberCertFromCSRRequest } from '../models/IssueMemberCertFromCSRRequest';
 import type { IssueMemberCertRequest } from '../models/IssueMemberCertRequest';
 import type { MemberCertResponse } from '../models/MemberCertResponse';
 import type { OCSPResponse } from '../models/OCSPResponse';
@@ -86,6 +87,27 @@ export class CertificatesService {
             },
         });
     }
+    /**
+     * Issue Member Certificate From CSR
+     * Issue a member certificate for the public key of a PKCS#10 certificate signing request. The private key never leaves the caller, so key_pem is empty. Requires a miniKMS that implements IssueMemberCertFromCSR, enabled with MINIKMS_CSR_ISSUANCE_ENABLED.
+     * @param requestBody
+     * @returns MemberCertResponse Member certificate issued successfully
+     * @throws ApiError
+     */
+    public issueMemberCertFromCsr(
+        requestBody?: IssueMemberCertFromCSRRequest,
+    ): CancelablePromise<MemberCertResponse> {
+        return this.httpRequest.request({
+            method: 'POST',
+            url: '/api/certificate/issue/csr',
+            body: requestBody,
+            mediaType: 'application/json',
+            errors: {
+                500: `Internal server error`,
+                501: `CSR issuance is not enabled on this server`,
+            },
+        });
+    }
     /**
      * Get CRL
      * Retrieve the Certificate Revocation List for the organization